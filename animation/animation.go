@@ -0,0 +1,285 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package animation renders sequences of plots as animated
+// GIF images or as numbered sequences of PNG images.
+package animation
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	vgdraw "github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/vgimg"
+)
+
+// DefaultDelay is the delay between frames used
+// if no delay is specified.
+const DefaultDelay = 100 * time.Millisecond
+
+// Animation is a sequence of plots rendered as frames
+// of a raster animation.
+type Animation struct {
+	// Width and Height are the dimensions
+	// of each frame.
+	Width, Height vg.Length
+
+	// DPI is the resolution of the frames in
+	// dots per inch. If DPI is zero,
+	// vgimg.DefaultDPI is used.
+	DPI int
+
+	// Delay is the time each frame is shown for.
+	// GIF frame delays are rounded to hundredths
+	// of a second. If Delay is zero, DefaultDelay
+	// is used.
+	Delay time.Duration
+
+	// FixedRange specifies whether every frame is
+	// drawn with the same axis ranges, covering the
+	// union of the axis ranges of all the plots.
+	FixedRange bool
+
+	// LoopCount controls the number of times a GIF
+	// animation is shown, with the same meaning as
+	// image/gif.GIF's LoopCount field. The animation
+	// loops forever if LoopCount is zero.
+	LoopCount int
+
+	// Colors is the maximum number of colors in the
+	// palette of each GIF frame. If Colors is zero,
+	// 256 colors are used.
+	Colors int
+
+	// Dither specifies whether GIF frames are
+	// dithered when reducing them to their palettes.
+	Dither bool
+
+	// Workers is the number of frames that are
+	// rendered concurrently. If Workers is zero,
+	// runtime.GOMAXPROCS(0) is used.
+	Workers int
+
+	n     int
+	frame func(i int) (*plot.Plot, error)
+}
+
+// New returns an animation of the given plots with
+// frames of the given size.
+func New(w, h vg.Length, plots ...*plot.Plot) *Animation {
+	return NewFunc(w, h, len(plots), func(i int) (*plot.Plot, error) {
+		return plots[i], nil
+	})
+}
+
+// NewFunc returns an animation of n frames with the given
+// size. The plot for frame i is returned by calling fn(i).
+// fn is called once for each frame, in order, from a single
+// goroutine, so it need not be safe for concurrent use.
+func NewFunc(w, h vg.Length, n int, fn func(i int) (*plot.Plot, error)) *Animation {
+	return &Animation{Width: w, Height: h, n: n, frame: fn}
+}
+
+// Len returns the number of frames in the animation.
+func (a *Animation) Len() int {
+	return a.n
+}
+
+// Render returns the rendered frames of the animation.
+func (a *Animation) Render() ([]*image.RGBA, error) {
+	frames := make([]*image.RGBA, a.n)
+	err := a.render(func(i int, img *image.RGBA) error {
+		frames[i] = img
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return frames, nil
+}
+
+// WriteGIF writes the animation to w as an animated GIF. The
+// palette of each frame is chosen using vgimg.MedianCut.
+func (a *Animation) WriteGIF(w io.Writer) error {
+	colors := a.Colors
+	if colors == 0 {
+		colors = 256
+	}
+	if colors < 2 || colors > 256 {
+		return fmt.Errorf("animation: invalid number of colors: %d", colors)
+	}
+	var drawer draw.Drawer = draw.Src
+	if a.Dither {
+		drawer = draw.FloydSteinberg
+	}
+
+	delay := a.delay()
+	g := &gif.GIF{
+		Image:     make([]*image.Paletted, a.n),
+		Delay:     make([]int, a.n),
+		LoopCount: a.LoopCount,
+	}
+	err := a.render(func(i int, img *image.RGBA) error {
+		b := img.Bounds()
+		pal := vgimg.MedianCut{}.Quantize(make(color.Palette, 0, colors), img)
+		p := image.NewPaletted(b, pal)
+		drawer.Draw(p, b, img, b.Min)
+		g.Image[i] = p
+		g.Delay[i] = int(math.Floor(delay.Seconds()*100 + 0.5))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return gif.EncodeAll(w, g)
+}
+
+// WritePNGs writes each frame of the animation to a PNG file
+// named by formatting the frame number with pattern, for
+// example "frame%03d.png".
+func (a *Animation) WritePNGs(pattern string) error {
+	first := fmt.Sprintf(pattern, 0)
+	if strings.Contains(first, "%!") || first == fmt.Sprintf(pattern, 1) {
+		return fmt.Errorf("animation: pattern does not include frame number: %q", pattern)
+	}
+	return a.render(func(i int, img *image.RGBA) error {
+		f, err := os.Create(fmt.Sprintf(pattern, i))
+		if err != nil {
+			return err
+		}
+		err = png.Encode(f, img)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	})
+}
+
+func (a *Animation) delay() time.Duration {
+	if a.Delay == 0 {
+		return DefaultDelay
+	}
+	return a.Delay
+}
+
+// plots returns the plots for all frames of the animation,
+// with their axis ranges unified if FixedRange is true.
+func (a *Animation) plots() ([]*plot.Plot, error) {
+	if a.frame == nil {
+		return nil, errors.New("animation: no frame function")
+	}
+	plots := make([]*plot.Plot, a.n)
+	for i := range plots {
+		p, err := a.frame(i)
+		if err != nil {
+			return nil, fmt.Errorf("animation: frame %d: %v", i, err)
+		}
+		if p == nil {
+			return nil, fmt.Errorf("animation: frame %d: nil plot", i)
+		}
+		plots[i] = p
+	}
+	if !a.FixedRange || len(plots) == 0 {
+		return plots, nil
+	}
+
+	xmin, xmax := math.Inf(1), math.Inf(-1)
+	ymin, ymax := math.Inf(1), math.Inf(-1)
+	for _, p := range plots {
+		xmin, xmax = math.Min(xmin, p.X.Min), math.Max(xmax, p.X.Max)
+		ymin, ymax = math.Min(ymin, p.Y.Min), math.Max(ymax, p.Y.Max)
+	}
+	for _, p := range plots {
+		p.X.Min, p.X.Max = xmin, xmax
+		p.Y.Min, p.Y.Max = ymin, ymax
+	}
+	return plots, nil
+}
+
+// render renders each frame of the animation concurrently,
+// calling fn with each rendered frame from the goroutine that
+// rendered it. Frames drawn from the same plot share an image
+// that fn must not modify. The first error returned by fn is
+// returned.
+func (a *Animation) render(fn func(i int, img *image.RGBA) error) error {
+	plots, err := a.plots()
+	if err != nil {
+		return err
+	}
+	dpi := a.DPI
+	if dpi == 0 {
+		dpi = vgimg.DefaultDPI
+	}
+	if a.Width <= 0 || a.Height <= 0 || dpi < 0 {
+		return fmt.Errorf("animation: invalid frame size: %vx%v at %d dpi", a.Width, a.Height, dpi)
+	}
+	w := int(a.Width/vg.Inch*vg.Length(dpi) + 0.5)
+	h := int(a.Height/vg.Inch*vg.Length(dpi) + 0.5)
+
+	// Plot.Draw modifies the plot, so a plot used for
+	// several frames is rendered once and shared.
+	var jobs [][]int
+	seen := make(map[*plot.Plot]int)
+	for i, p := range plots {
+		j, ok := seen[p]
+		if !ok {
+			j = len(jobs)
+			seen[p] = j
+			jobs = append(jobs, nil)
+		}
+		jobs[j] = append(jobs[j], i)
+	}
+
+	workers := a.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	work := make(chan []int)
+	for k := 0; k < workers; k++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range work {
+				img := image.NewRGBA(image.Rect(0, 0, w, h))
+				c := vgimg.NewWith(vgimg.UseImage(img), vgimg.UseDPI(dpi))
+				plots[idx[0]].Draw(vgdraw.New(c))
+				for _, i := range idx {
+					if err := fn(i, img); err != nil {
+						once.Do(func() { firstErr = fmt.Errorf("animation: frame %d: %v", i, err) })
+					}
+				}
+			}
+		}()
+	}
+	for _, idx := range jobs {
+		work <- idx
+	}
+	close(work)
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,168 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package animation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/gif"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/plotter"
+	"github.com/gonum/plot/vg"
+)
+
+// linePlot returns a plot of a line through (0, 0) and (i, i*i).
+func linePlot(i int) (*plot.Plot, error) {
+	p, err := plot.New()
+	if err != nil {
+		return nil, err
+	}
+	l, err := plotter.NewLine(plotter.XYs{{0, 0}, {float64(i), float64(i * i)}})
+	if err != nil {
+		return nil, err
+	}
+	p.Add(l)
+	return p, nil
+}
+
+func TestFixedRange(t *testing.T) {
+	for _, fixed := range []bool{false, true} {
+		var plots []*plot.Plot
+		a := NewFunc(vg.Inch, vg.Inch, 4, func(i int) (*plot.Plot, error) {
+			p, err := linePlot(i + 1)
+			plots = append(plots, p)
+			return p, err
+		})
+		a.FixedRange = fixed
+		frames, err := a.Render()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(frames) != 4 {
+			t.Fatalf("unexpected number of frames: got:%d want:4", len(frames))
+		}
+		for i, p := range plots {
+			wantX, wantY := float64(i+1), float64((i+1)*(i+1))
+			if fixed {
+				wantX, wantY = 4, 16
+			}
+			if p.X.Min != 0 || p.X.Max != wantX || p.Y.Min != 0 || p.Y.Max != wantY {
+				t.Errorf("unexpected range for frame %d with fixed range %t: got:[%v,%v]x[%v,%v] want:[0,%v]x[0,%v]",
+					i, fixed, p.X.Min, p.X.Max, p.Y.Min, p.Y.Max, wantX, wantY)
+			}
+		}
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	var plots []*plot.Plot
+	for i := 0; i < 5; i++ {
+		p, err := linePlot(i)
+		if err != nil {
+			t.Fatalf("failed to create plot: %v", err)
+		}
+		plots = append(plots, p)
+	}
+	// Repeat a plot to check that shared frames are handled.
+	plots = append(plots, plots[0])
+
+	a := New(vg.Inch, vg.Inch/2, plots...)
+	a.Delay = 250 * time.Millisecond
+	a.LoopCount = 2
+	a.Colors = 16
+	a.Workers = 3
+	var buf bytes.Buffer
+	err := a.WriteGIF(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("failed to decode gif: %v", err)
+	}
+	if len(g.Image) != len(plots) {
+		t.Fatalf("unexpected number of frames: got:%d want:%d", len(g.Image), len(plots))
+	}
+	if g.LoopCount != 2 {
+		t.Errorf("unexpected loop count: got:%d want:2", g.LoopCount)
+	}
+	for i, img := range g.Image {
+		if g.Delay[i] != 25 {
+			t.Errorf("unexpected delay for frame %d: got:%d want:25", i, g.Delay[i])
+		}
+		if b := img.Bounds(); b.Dx() != 96 || b.Dy() != 48 {
+			t.Errorf("unexpected size for frame %d: got:%v want:96x48", i, b.Size())
+		}
+		if len(img.Palette) > 16 {
+			t.Errorf("unexpected palette size for frame %d: got:%d want at most 16", i, len(img.Palette))
+		}
+	}
+
+	a.Colors = 1
+	if err := a.WriteGIF(&buf); err == nil {
+		t.Error("expected error for invalid number of colors")
+	}
+}
+
+func TestWritePNGs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "animation")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	a := NewFunc(vg.Inch, vg.Inch, 3, linePlot)
+	a.DPI = 30
+	err = a.WritePNGs(filepath.Join(dir, "frame%02d.png"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		f, err := os.Open(filepath.Join(dir, fmt.Sprintf("frame%02d.png", i)))
+		if err != nil {
+			t.Fatalf("missing frame %d: %v", i, err)
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("failed to decode frame %d: %v", i, err)
+		}
+		if b := img.Bounds(); b.Dx() != 30 || b.Dy() != 30 {
+			t.Errorf("unexpected size for frame %d: got:%v want:30x30", i, b.Size())
+		}
+	}
+
+	err = a.WritePNGs(filepath.Join(dir, "frame.png"))
+	if err == nil || !strings.Contains(err.Error(), "pattern does not include frame number") {
+		t.Errorf("unexpected error for pattern without verb: %v", err)
+	}
+}
+
+func TestFrameError(t *testing.T) {
+	calls := 0
+	a := NewFunc(vg.Inch, vg.Inch, 5, func(i int) (*plot.Plot, error) {
+		calls++
+		if i == 2 {
+			return nil, errors.New("no data")
+		}
+		return linePlot(i)
+	})
+	_, err := a.Render()
+	if err == nil || err.Error() != "animation: frame 2: no data" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("unexpected number of frame calls: got:%d want:3", calls)
+	}
+}
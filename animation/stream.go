@@ -0,0 +1,180 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package animation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	vgdraw "github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/vgimg"
+)
+
+// DefaultRate is the frame rate used if
+// no frame rate is specified.
+const DefaultRate = 10
+
+// Stream renders a plot of live data repeatedly at a
+// target frame rate, such as a plot of plotter.RingXYs
+// that are appended to by another goroutine.
+type Stream struct {
+	// Width and Height are the dimensions of
+	// the frames written by WriteFrames.
+	Width, Height vg.Length
+
+	// DPI is the resolution of the frames written
+	// by WriteFrames in dots per inch. If DPI is
+	// zero, vgimg.DefaultDPI is used.
+	DPI int
+
+	// Rate is the target number of frames drawn
+	// per second. If Rate is zero, DefaultRate
+	// is used. A frame that takes longer than
+	// the interval between frames to draw delays
+	// the following frames.
+	Rate float64
+
+	// Window, if positive, is the span of the X
+	// axis of each frame. The X axis ends at the
+	// maximum of the X axis of the plot and slides
+	// as new data arrives, showing, for example,
+	// the last Window seconds of a feed with X
+	// values in seconds. The Y axis still covers
+	// all of the data. A window of the most recent
+	// samples is kept by the capacity of the
+	// plotter.RingXYs of the data instead.
+	Window float64
+
+	frame func() (*plot.Plot, error)
+}
+
+// NewStream returns a stream of frames with the
+// given size. The plot for each frame is returned
+// by calling fn, which should build the plot from
+// the current data so that its axis ranges fit the
+// data. Data that is appended to by another
+// goroutine, such as a plotter.RingXYs, should be
+// plotted from a copy made by its Snapshot method,
+// so that it cannot change while the frame is
+// drawn. fn is called from a single goroutine.
+func NewStream(w, h vg.Length, fn func() (*plot.Plot, error)) *Stream {
+	return &Stream{Width: w, Height: h, frame: fn}
+}
+
+// interval returns the time between frames.
+func (s *Stream) interval() (time.Duration, error) {
+	rate := s.Rate
+	if rate == 0 {
+		rate = DefaultRate
+	}
+	d := float64(time.Second) / rate
+	if !(rate > 0) || !(d >= 1) || d > math.MaxInt64 {
+		return 0, fmt.Errorf("animation: invalid frame rate: %v", rate)
+	}
+	return time.Duration(d), nil
+}
+
+// Plot returns the plot for the next frame,
+// with its X axis limited to the Window.
+func (s *Stream) Plot() (*plot.Plot, error) {
+	if s.frame == nil {
+		return nil, errors.New("animation: no frame function")
+	}
+	p, err := s.frame()
+	if err != nil {
+		return nil, fmt.Errorf("animation: %v", err)
+	}
+	if p == nil {
+		return nil, errors.New("animation: nil plot")
+	}
+	if s.Window > 0 {
+		p.X.Min = p.X.Max - s.Window
+	}
+	return p, nil
+}
+
+// Draw draws the next frame to c.
+func (s *Stream) Draw(c *vgimg.Canvas) error {
+	p, err := s.Plot()
+	if err != nil {
+		return err
+	}
+	p.Draw(vgdraw.New(c))
+	return nil
+}
+
+// Run draws frames to c at the frame rate until done
+// is closed, calling fn, if it is not nil, after each
+// frame is drawn, for example to show the image of c.
+// The first error returned by drawing a frame or by
+// fn stops the stream and is returned.
+func (s *Stream) Run(c *vgimg.Canvas, done <-chan struct{}, fn func(*vgimg.Canvas) error) error {
+	d, err := s.interval()
+	if err != nil {
+		return err
+	}
+	tick := time.NewTicker(d)
+	defer tick.Stop()
+	for {
+		err := s.Draw(c)
+		if err != nil {
+			return err
+		}
+		if fn != nil {
+			err = fn(c)
+			if err != nil {
+				return err
+			}
+		}
+		// Check done first so that no frame is drawn
+		// after it is closed, even if a tick is also
+		// ready.
+		select {
+		case <-done:
+			return nil
+		default:
+		}
+		select {
+		case <-done:
+			return nil
+		case <-tick.C:
+		}
+	}
+}
+
+// WriteFrames writes each frame to w, one after
+// another, at the frame rate until done is closed.
+// Supported formats are jpg|jpeg, png and tif|tiff.
+func (s *Stream) WriteFrames(w io.Writer, format string, done <-chan struct{}) error {
+	dpi := s.DPI
+	if dpi == 0 {
+		dpi = vgimg.DefaultDPI
+	}
+	if s.Width <= 0 || s.Height <= 0 || dpi < 0 {
+		return fmt.Errorf("animation: invalid frame size: %vx%v at %d dpi", s.Width, s.Height, dpi)
+	}
+	c := vgimg.NewWith(vgimg.UseWH(s.Width, s.Height), vgimg.UseDPI(dpi))
+
+	var wt io.WriterTo
+	switch format {
+	case "jpg", "jpeg":
+		wt = vgimg.JpegCanvas{Canvas: c}
+	case "png":
+		wt = vgimg.PngCanvas{Canvas: c}
+	case "tif", "tiff":
+		wt = vgimg.TiffCanvas{Canvas: c}
+	default:
+		return fmt.Errorf("animation: unsupported format: %q", format)
+	}
+	return s.Run(c, done, func(*vgimg.Canvas) error {
+		_, err := wt.WriteTo(w)
+		return err
+	})
+}
@@ -0,0 +1,165 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package animation
+
+import (
+	"bytes"
+	"image/png"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/plotter"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/vgimg"
+)
+
+// ringStream returns a stream of a line of r, with a
+// point appended to r before each frame is drawn.
+func ringStream(r *plotter.RingXYs) *Stream {
+	var i int
+	return NewStream(vg.Inch, vg.Inch, func() (*plot.Plot, error) {
+		if err := r.Append(float64(i), float64(i%3)); err != nil {
+			return nil, err
+		}
+		i++
+		p, err := plot.New()
+		if err != nil {
+			return nil, err
+		}
+		l, err := plotter.NewLine(r.Snapshot())
+		if err != nil {
+			return nil, err
+		}
+		p.Add(l)
+		return p, nil
+	})
+}
+
+func TestStreamWindow(t *testing.T) {
+	r, err := plotter.NewRingXYs(100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := ringStream(r)
+	s.Window = 5
+	for i := 0; i < 10; i++ {
+		p, err := s.Plot()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p.X.Max != float64(i) || p.X.Min != float64(i-5) {
+			t.Errorf("unexpected X range of frame %d: got:[%v, %v] want:[%d, %d]", i, p.X.Min, p.X.Max, i-5, i)
+		}
+	}
+}
+
+func TestStreamRun(t *testing.T) {
+	r, err := plotter.NewRingXYs(4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := ringStream(r)
+	s.Rate = 1000
+	c := vgimg.New(vg.Inch, vg.Inch)
+	done := make(chan struct{})
+	var n int
+	err = s.Run(c, done, func(got *vgimg.Canvas) error {
+		if got != c {
+			t.Error("unexpected canvas")
+		}
+		n++
+		if n == 5 {
+			close(done)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 5 {
+		t.Errorf("unexpected number of frames: got:%d want:5", n)
+	}
+	if r.Len() != 4 {
+		t.Errorf("unexpected number of points: got:%d want:4", r.Len())
+	}
+}
+
+// frameWriter closes done after n
+// PNG images have been written.
+type frameWriter struct {
+	bytes.Buffer
+	frames int
+	n      int
+	done   chan struct{}
+}
+
+func (w *frameWriter) Write(p []byte) (int, error) {
+	if bytes.Contains(p, []byte("IEND")) {
+		w.frames++
+		if w.frames == w.n {
+			close(w.done)
+		}
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestStreamRate(t *testing.T) {
+	s := NewStream(vg.Inch, vg.Inch, nil)
+	for _, rate := range []float64{-1, math.NaN(), math.Inf(1), 1e300, 1e-300} {
+		s.Rate = rate
+		if _, err := s.interval(); err == nil {
+			t.Errorf("expected error for frame rate %v", rate)
+		}
+	}
+	for _, test := range []struct {
+		rate float64
+		want time.Duration
+	}{
+		{rate: 0, want: time.Second / DefaultRate},
+		{rate: 50, want: 20 * time.Millisecond},
+		{rate: 1e9, want: time.Nanosecond},
+	} {
+		s.Rate = test.rate
+		d, err := s.interval()
+		if err != nil || d != test.want {
+			t.Errorf("unexpected interval for frame rate %v: got:%v,%v want:%v", test.rate, d, err, test.want)
+		}
+	}
+}
+
+func TestStreamWriteFrames(t *testing.T) {
+	r, err := plotter.NewRingXYs(4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := ringStream(r)
+	s.Rate = 1000
+	if err := s.WriteFrames(&frameWriter{}, "gif", nil); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+
+	w := &frameWriter{n: 3, done: make(chan struct{})}
+	err = s.WriteFrames(w, "png", w.done)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w.frames != 3 {
+		t.Fatalf("unexpected number of frames: got:%d want:3", w.frames)
+	}
+	for i := 0; i < 3; i++ {
+		img, err := png.Decode(&w.Buffer)
+		if err != nil {
+			t.Fatalf("unexpected error decoding frame %d: %v", i, err)
+		}
+		if b := img.Bounds(); b.Dx() != vgimg.DefaultDPI || b.Dy() != vgimg.DefaultDPI {
+			t.Errorf("unexpected size of frame %d: got:%v", i, b)
+		}
+	}
+	if w.Len() != 0 {
+		t.Errorf("unexpected trailing bytes: %d", w.Len())
+	}
+}
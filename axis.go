@@ -7,6 +7,7 @@ package plot
 import (
 	"image/color"
 	"math"
+	"sort"
 	"strconv"
 
 	"github.com/gonum/floats"
@@ -31,6 +32,16 @@ type Normalizer interface {
 	Normalize(min, max, x float64) float64
 }
 
+// Denormalizer rescales values from the normalized coordinate
+// system to the data coordinate system. It is implemented by
+// Normalizers that can be inverted.
+type Denormalizer interface {
+	// Denormalize transforms a value n in the normalized
+	// coordinate system to the data coordinate system,
+	// inverting Normalize.
+	Denormalize(min, max, n float64) float64
+}
+
 // An Axis represents either a horizontal or vertical
 // axis of a plot.
 type Axis struct {
@@ -39,7 +50,10 @@ type Axis struct {
 	Min, Max float64
 
 	Label struct {
-		// Text is the axis label string.
+		// Text is the axis label string.  It may
+		// contain markup as described for
+		// draw.TextStyle if the Markup field
+		// of TextStyle is set.
 		Text string
 
 		// TextStyle is the style of the axis label text.
@@ -56,8 +70,14 @@ type Axis struct {
 
 	Tick struct {
 		// Label is the TextStyle on the tick labels.
+		// The Rotation of the style rotates the labels
+		// about the ends nearest to the axis.
 		Label draw.TextStyle
 
+		// Layout specifies how tick labels that
+		// would overlap each other are laid out.
+		Layout TickLabelLayout
+
 		// LineStyle is the LineStyle of the tick lines.
 		draw.LineStyle
 
@@ -121,6 +141,27 @@ func makeAxis() (Axis, error) {
 	return a, nil
 }
 
+// TickLabelLayout specifies how the tick labels of an
+// axis are laid out when they would overlap each other.
+type TickLabelLayout int
+
+const (
+	// FixedTickLabels draws the tick labels as they are,
+	// even if they overlap.
+	FixedTickLabels TickLabelLayout = iota
+
+	// RotateTickLabels rotates the labels of a horizontal
+	// axis by 45 degrees, or by 90 degrees if they still
+	// overlap.  Rotating the labels of a vertical axis does
+	// not separate them, so they are staggered instead.
+	RotateTickLabels
+
+	// StaggerTickLabels alternates the tick labels
+	// between two rows, or two columns for a vertical
+	// axis.
+	StaggerTickLabels
+)
+
 // sanitizeRange ensures that the range of the
 // axis makes sense.
 func (a *Axis) sanitizeRange() {
@@ -143,23 +184,38 @@ func (a *Axis) sanitizeRange() {
 // set the axis to a standard linear scale.
 type LinearScale struct{}
 
-var _ Normalizer = LinearScale{}
+var (
+	_ Normalizer   = LinearScale{}
+	_ Denormalizer = LinearScale{}
+)
 
 func (LinearScale) Normalize(min, max, x float64) float64 {
 	return (x - min) / (max - min)
 }
 
+func (LinearScale) Denormalize(min, max, n float64) float64 {
+	return min + n*(max-min)
+}
+
 // LocScale can be used as the value of an Axis.Scale function to
 // set the axis to a log scale.
 type LogScale struct{}
 
-var _ Normalizer = LogScale{}
+var (
+	_ Normalizer   = LogScale{}
+	_ Denormalizer = LogScale{}
+)
 
 func (LogScale) Normalize(min, max, x float64) float64 {
 	logMin := log(min)
 	return (log(x) - logMin) / (log(max) - logMin)
 }
 
+func (LogScale) Denormalize(min, max, n float64) float64 {
+	logMin := log(min)
+	return math.Exp(logMin + n*(log(max)-logMin))
+}
+
 // Norm returns the value of x, given in the data coordinate
 // system, normalized to its distance as a fraction of the
 // range of this axis.  For example, if x is a.Min then the return
@@ -168,6 +224,18 @@ func (a *Axis) Norm(x float64) float64 {
 	return a.Scale.Normalize(a.Min, a.Max, x)
 }
 
+// Denorm returns the value in the data coordinate system
+// of n, given as a fraction of the range of this axis.
+// It is the inverse of Norm.  If the Scale of the axis
+// does not implement Denormalizer then Denorm returns NaN.
+func (a *Axis) Denorm(n float64) float64 {
+	d, ok := a.Scale.(Denormalizer)
+	if !ok {
+		return math.NaN()
+	}
+	return d.Denormalize(a.Min, a.Max, n)
+}
+
 // drawTicks returns true if the tick marks should be drawn.
 func (a *Axis) drawTicks() bool {
 	return a.Tick.Width > 0 && a.Tick.Length > 0
@@ -177,6 +245,84 @@ func (a *Axis) drawTicks() bool {
 // of a plot.
 type horizontalAxis struct {
 	Axis
+
+	// stagger is true if the tick labels
+	// alternate between two rows.
+	stagger bool
+}
+
+// layout resolves the tick label layout of the
+// axis given the length of the axis.
+func (a *horizontalAxis) layout(length vg.Length) {
+	if a.Tick.Layout == FixedTickLabels || !a.overlaps(length) {
+		return
+	}
+	if a.Tick.Layout == RotateTickLabels {
+		for _, rot := range []float64{math.Pi / 4, math.Pi / 2} {
+			a.Tick.Label.Rotation = rot
+			if !a.overlaps(length) {
+				return
+			}
+		}
+		return
+	}
+	a.stagger = true
+}
+
+// overlaps returns true if any tick labels would
+// overlap on an axis of the given length.
+func (a *horizontalAxis) overlaps(length vg.Length) bool {
+	marks := a.Tick.Marker.Ticks(a.Min, a.Max)
+	xalign, yalign := a.labelAlign()
+	pitch, _ := a.labelRows(marks)
+	return labelsOverlap(a.Tick.Label, marks, xalign, yalign, func(t Tick, row int) (draw.Point, bool) {
+		x := a.Norm(t.Value)
+		if !a.stagger {
+			row = 0
+		}
+		return draw.Point{X: length * vg.Length(x), Y: -vg.Length(row) * pitch}, 0 <= x && x <= 1
+	})
+}
+
+// labelAlign returns the alignment of the tick labels that
+// places the end of each label nearest to the axis at its
+// tick mark.
+func (a *horizontalAxis) labelAlign() (xalign, yalign float64) {
+	sin, cos := math.Sincos(a.Tick.Label.Rotation)
+	switch {
+	case sin > rotationTol:
+		return -1, -0.5
+	case sin < -rotationTol:
+		return 0, -0.5
+	case cos < 0:
+		return -0.5, -1
+	}
+	return -0.5, 0
+}
+
+// labelRows returns the distance between the rows of
+// tick labels and the distance that the labels extend
+// below their anchor points.
+func (a *horizontalAxis) labelRows(marks []Tick) (pitch, below vg.Length) {
+	xalign, yalign := a.labelAlign()
+	min, max := labelExtents(a.Tick.Label, marks, xalign, yalign)
+	pitch = max.Y - min.Y
+	if a.stagger {
+		pitch += a.Tick.Label.Font.Extents().Descent
+	}
+	return pitch, -min.Y
+}
+
+// labelHeight returns the height of the tick labels.
+func (a *horizontalAxis) labelHeight(marks []Tick) vg.Length {
+	if a.Tick.Label.Rotation == 0 && !a.stagger {
+		return tickLabelHeight(a.Tick.Label, marks)
+	}
+	pitch, _ := a.labelRows(marks)
+	if a.stagger {
+		return 2*pitch - a.Tick.Label.Font.Extents().Descent
+	}
+	return pitch
 }
 
 // size returns the height of the axis.
@@ -189,7 +335,7 @@ func (a *horizontalAxis) size() (h vg.Length) {
 		if a.drawTicks() {
 			h += a.Tick.Length
 		}
-		h += tickLabelHeight(a.Tick.Label, marks)
+		h += a.labelHeight(marks)
 	}
 	h += a.Width / 2
 	h += a.Padding
@@ -206,16 +352,23 @@ func (a *horizontalAxis) draw(c draw.Canvas) {
 	}
 
 	marks := a.Tick.Marker.Ticks(a.Min, a.Max)
-	for _, t := range marks {
+	xalign, yalign := a.labelAlign()
+	pitch, below := a.labelRows(marks)
+	rows := staggerRows(marks)
+	for i, t := range marks {
 		x := c.X(a.Norm(t.Value))
 		if !c.ContainsX(x) || t.IsMinor() {
 			continue
 		}
-		c.FillText(a.Tick.Label, x, y, -0.5, 0, t.Label)
+		ly := y + below
+		if a.stagger && rows[i] == 0 {
+			ly += pitch
+		}
+		c.FillText(a.Tick.Label, x, ly, xalign, yalign, t.Label)
 	}
 
 	if len(marks) > 0 {
-		y += tickLabelHeight(a.Tick.Label, marks)
+		y += a.labelHeight(marks)
 	} else {
 		y += a.Width / 2
 	}
@@ -238,14 +391,15 @@ func (a *horizontalAxis) draw(c draw.Canvas) {
 
 // GlyphBoxes returns the GlyphBoxes for the tick labels.
 func (a *horizontalAxis) GlyphBoxes(*Plot) (boxes []GlyphBox) {
+	xalign, yalign := a.labelAlign()
 	for _, t := range a.Tick.Marker.Ticks(a.Min, a.Max) {
 		if t.IsMinor() {
 			continue
 		}
-		w := a.Tick.Label.Width(t.Label)
+		r := labelBox(a.Tick.Label, t.Label, xalign, yalign)
 		box := GlyphBox{
 			X:         a.Norm(t.Value),
-			Rectangle: draw.Rectangle{draw.Point{X: -w / 2}, draw.Point{X: w / 2}},
+			Rectangle: draw.Rectangle{draw.Point{X: r.Min.X}, draw.Point{X: r.Max.X}},
 		}
 		boxes = append(boxes, box)
 	}
@@ -255,6 +409,75 @@ func (a *horizontalAxis) GlyphBoxes(*Plot) (boxes []GlyphBox) {
 // A verticalAxis is drawn vertically up the left side of a plot.
 type verticalAxis struct {
 	Axis
+
+	// stagger is true if the tick labels
+	// alternate between two columns.
+	stagger bool
+}
+
+// layout resolves the tick label layout of the
+// axis given the length of the axis.
+func (a *verticalAxis) layout(length vg.Length) {
+	if a.Tick.Layout == FixedTickLabels || !a.overlaps(length) {
+		return
+	}
+	a.stagger = true
+}
+
+// overlaps returns true if any tick labels would
+// overlap on an axis of the given length.
+func (a *verticalAxis) overlaps(length vg.Length) bool {
+	marks := a.Tick.Marker.Ticks(a.Min, a.Max)
+	xalign, yalign := a.labelAlign()
+	pitch, _ := a.labelColumns(marks)
+	return labelsOverlap(a.Tick.Label, marks, xalign, yalign, func(t Tick, col int) (draw.Point, bool) {
+		y := a.Norm(t.Value)
+		if !a.stagger {
+			col = 0
+		}
+		return draw.Point{X: -vg.Length(col) * pitch, Y: length * vg.Length(y)}, 0 <= y && y <= 1
+	})
+}
+
+// labelAlign returns the alignment of the tick labels that
+// places the end of each label nearest to the axis at its
+// tick mark.
+func (a *verticalAxis) labelAlign() (xalign, yalign float64) {
+	sin, cos := math.Sincos(a.Tick.Label.Rotation)
+	switch {
+	case cos > rotationTol:
+		return -1, -0.5
+	case cos < -rotationTol:
+		return 0, -0.5
+	case sin < 0:
+		return -0.5, -1
+	}
+	return -0.5, 0
+}
+
+// labelColumns returns the distance between the columns
+// of tick labels and the distance that the labels extend
+// to the left of their anchor points.
+func (a *verticalAxis) labelColumns(marks []Tick) (pitch, left vg.Length) {
+	xalign, yalign := a.labelAlign()
+	min, max := labelExtents(a.Tick.Label, marks, xalign, yalign)
+	pitch = max.X - min.X
+	if a.stagger {
+		pitch += a.Tick.Label.Width(" ")
+	}
+	return pitch, -min.X
+}
+
+// labelWidth returns the width of the tick labels.
+func (a *verticalAxis) labelWidth(marks []Tick) vg.Length {
+	if a.Tick.Label.Rotation == 0 && !a.stagger {
+		return tickLabelWidth(a.Tick.Label, marks)
+	}
+	pitch, _ := a.labelColumns(marks)
+	if a.stagger {
+		return 2*pitch - a.Tick.Label.Width(" ")
+	}
+	return pitch
 }
 
 // size returns the width of the axis.
@@ -264,7 +487,7 @@ func (a *verticalAxis) size() (w vg.Length) {
 		w += a.Label.Height(a.Label.Text)
 	}
 	if marks := a.Tick.Marker.Ticks(a.Min, a.Max); len(marks) > 0 {
-		if lwidth := tickLabelWidth(a.Tick.Label, marks); lwidth > 0 {
+		if lwidth := a.labelWidth(marks); lwidth > 0 {
 			w += lwidth
 			w += a.Label.Width(" ")
 		}
@@ -289,16 +512,24 @@ func (a *verticalAxis) draw(c draw.Canvas) {
 		x += -a.Label.Font.Extents().Descent
 	}
 	marks := a.Tick.Marker.Ticks(a.Min, a.Max)
-	if w := tickLabelWidth(a.Tick.Label, marks); len(marks) > 0 && w > 0 {
+	xalign, yalign := a.labelAlign()
+	pitch, left := a.labelColumns(marks)
+	cols := staggerRows(marks)
+	lx := x + left
+	if w := a.labelWidth(marks); len(marks) > 0 && w > 0 {
 		x += w
 	}
 	major := false
-	for _, t := range marks {
+	for i, t := range marks {
 		y := c.Y(a.Norm(t.Value))
 		if !c.ContainsY(y) || t.IsMinor() {
 			continue
 		}
-		c.FillText(a.Tick.Label, x, y, -1, -0.5, t.Label)
+		tx := lx
+		if a.stagger && cols[i] == 0 {
+			tx += pitch
+		}
+		c.FillText(a.Tick.Label, tx, y, xalign, yalign, t.Label)
 		major = true
 	}
 	if major {
@@ -321,14 +552,15 @@ func (a *verticalAxis) draw(c draw.Canvas) {
 
 // GlyphBoxes returns the GlyphBoxes for the tick labels
 func (a *verticalAxis) GlyphBoxes(*Plot) (boxes []GlyphBox) {
+	xalign, yalign := a.labelAlign()
 	for _, t := range a.Tick.Marker.Ticks(a.Min, a.Max) {
 		if t.IsMinor() {
 			continue
 		}
-		h := a.Tick.Label.Height(t.Label)
+		r := labelBox(a.Tick.Label, t.Label, xalign, yalign)
 		box := GlyphBox{
 			Y:         a.Norm(t.Value),
-			Rectangle: draw.Rectangle{draw.Point{Y: -h / 2}, draw.Point{Y: h / 2}},
+			Rectangle: draw.Rectangle{draw.Point{Y: r.Min.Y}, draw.Point{Y: r.Max.Y}},
 		}
 		boxes = append(boxes, box)
 	}
@@ -468,6 +700,101 @@ func (t Tick) lengthOffset(len vg.Length) vg.Length {
 	return 0
 }
 
+// rotationTol is the tolerance used to decide
+// whether rotated text is horizontal or vertical.
+const rotationTol = 1e-6
+
+// labelBox returns the bounds of a tick label drawn with
+// the given style and alignment, relative to the point
+// at which it is drawn.
+func labelBox(sty draw.TextStyle, txt string, xalign, yalign float64) draw.Rectangle {
+	r := sty.Rectangle(txt)
+	dx := sty.Width(txt) * vg.Length(xalign)
+	dy := sty.Height(txt) * vg.Length(yalign)
+	sin, cos := math.Sincos(sty.Rotation)
+	off := draw.Point{
+		X: dx*vg.Length(cos) - dy*vg.Length(sin),
+		Y: dx*vg.Length(sin) + dy*vg.Length(cos),
+	}
+	return draw.Rectangle{
+		Min: draw.Point{X: r.Min.X + off.X, Y: r.Min.Y + off.Y},
+		Max: draw.Point{X: r.Max.X + off.X, Y: r.Max.Y + off.Y},
+	}
+}
+
+// labelExtents returns the smallest rectangle that contains
+// the origin and the labelBox of every major tick label.
+func labelExtents(sty draw.TextStyle, ticks []Tick, xalign, yalign float64) (min, max draw.Point) {
+	for _, t := range ticks {
+		if t.IsMinor() {
+			continue
+		}
+		r := labelBox(sty, t.Label, xalign, yalign)
+		min.X = vg.Length(math.Min(float64(min.X), float64(r.Min.X)))
+		min.Y = vg.Length(math.Min(float64(min.Y), float64(r.Min.Y)))
+		max.X = vg.Length(math.Max(float64(max.X), float64(r.Max.X)))
+		max.Y = vg.Length(math.Max(float64(max.Y), float64(r.Max.Y)))
+	}
+	return min, max
+}
+
+// staggerRows returns the row, zero or one, of each tick
+// when the major tick labels alternate between two rows
+// in order of their values.
+func staggerRows(ticks []Tick) []int {
+	var vals []float64
+	for _, t := range ticks {
+		if !t.IsMinor() {
+			vals = append(vals, t.Value)
+		}
+	}
+	sort.Float64s(vals)
+	rows := make([]int, len(ticks))
+	for i, t := range ticks {
+		if !t.IsMinor() {
+			rows[i] = sort.SearchFloat64s(vals, t.Value) % 2
+		}
+	}
+	return rows
+}
+
+// labelsOverlap returns true if any two major tick labels
+// drawn with the given style and alignment would overlap.
+// The anchor function returns the point at which the label
+// of a tick in the given stagger row is drawn, and whether
+// the label is drawn at all.
+func labelsOverlap(sty draw.TextStyle, ticks []Tick, xalign, yalign float64, anchor func(t Tick, row int) (draw.Point, bool)) bool {
+	// The labels are compared in the rotated
+	// coordinate system of the text, where
+	// their bounds are aligned with the axes.
+	type bounds struct{ u0, u1, v0, v1 vg.Length }
+	var labels []bounds
+	sin, cos := math.Sincos(sty.Rotation)
+	rows := staggerRows(ticks)
+	for i, t := range ticks {
+		if t.IsMinor() {
+			continue
+		}
+		p, ok := anchor(t, rows[i])
+		if !ok {
+			continue
+		}
+		w, h := sty.Width(t.Label), sty.Height(t.Label)
+		u := p.X*vg.Length(cos) + p.Y*vg.Length(sin) + w*vg.Length(xalign)
+		v := p.Y*vg.Length(cos) - p.X*vg.Length(sin) + h*vg.Length(yalign)
+		labels = append(labels, bounds{u0: u, u1: u + w, v0: v, v1: v + h})
+	}
+	pad := sty.Width(" ")
+	for i, a := range labels {
+		for _, b := range labels[i+1:] {
+			if a.u0 < b.u1+pad && b.u0 < a.u1+pad && a.v0 < b.v1+pad && b.v0 < a.v1+pad {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // tickLabelHeight returns height of the tick mark labels.
 func tickLabelHeight(sty draw.TextStyle, ticks []Tick) vg.Length {
 	maxHeight := vg.Length(0)
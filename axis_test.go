@@ -7,6 +7,8 @@ package plot
 import (
 	"math"
 	"testing"
+
+	"github.com/gonum/plot/vg"
 )
 
 func TestAxisSmallTick(t *testing.T) {
@@ -51,3 +53,97 @@ func TestAxisSmallTick(t *testing.T) {
 		}
 	}
 }
+
+func TestTickLabelLayout(t *testing.T) {
+	ax, err := makeAxis()
+	if err != nil {
+		t.Fatalf("failed to make axis: %v", err)
+	}
+	var ticks []Tick
+	for i, name := range []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October"} {
+		ticks = append(ticks, Tick{Value: float64(i), Label: name})
+	}
+	ax.Tick.Marker = ConstantTicks(ticks)
+	ax.Min, ax.Max = 0, 9
+
+	for _, test := range []struct {
+		layout   TickLabelLayout
+		length   float64
+		rotation float64
+		stagger  bool
+	}{
+		{layout: FixedTickLabels, length: 150},
+		{layout: RotateTickLabels, length: 1000},
+		{layout: RotateTickLabels, length: 150, rotation: math.Pi / 4},
+		{layout: RotateTickLabels, length: 100, rotation: math.Pi / 2},
+		{layout: StaggerTickLabels, length: 1000},
+		{layout: StaggerTickLabels, length: 150, stagger: true},
+	} {
+		a := horizontalAxis{Axis: ax}
+		a.Tick.Layout = test.layout
+		a.layout(vg.Length(test.length))
+		if a.Tick.Label.Rotation != test.rotation || a.stagger != test.stagger {
+			t.Errorf("unexpected layout %d for length %v: got rotation:%v stagger:%t want rotation:%v stagger:%t",
+				test.layout, test.length, a.Tick.Label.Rotation, a.stagger, test.rotation, test.stagger)
+		}
+		if test.layout != FixedTickLabels && a.Tick.Label.Rotation == math.Pi/4 && a.overlaps(vg.Length(test.length)) {
+			t.Errorf("unexpected overlap of rotated labels for length %v", test.length)
+		}
+		fixed := horizontalAxis{Axis: ax}
+		if (test.rotation != 0 || test.stagger) && a.size() <= fixed.size() {
+			t.Errorf("expected taller axis for layout %d and length %v: got:%v fixed:%v",
+				test.layout, test.length, a.size(), fixed.size())
+		}
+	}
+
+	// Vertical labels are as wide as the text is tall.
+	a := horizontalAxis{Axis: ax}
+	a.Tick.Label.Rotation = math.Pi / 2
+	h := a.Tick.Label.Height("May")
+	for _, b := range a.GlyphBoxes(nil) {
+		if b.X == 4.0/9 && math.Abs(float64(b.Size().X-h)) > 1e-9 {
+			t.Errorf("unexpected glyph box width for rotated label: got:%v want:%v", b.Size().X, h)
+		}
+	}
+
+	// Vertical axis labels are staggered
+	// since rotation cannot separate them.
+	v := verticalAxis{Axis: ax}
+	v.Tick.Layout = RotateTickLabels
+	v.layout(50)
+	if v.Tick.Label.Rotation != 0 || !v.stagger {
+		t.Errorf("unexpected vertical layout: got rotation:%v stagger:%t", v.Tick.Label.Rotation, v.stagger)
+	}
+	if fixed := (verticalAxis{Axis: ax}); v.size() <= fixed.size() {
+		t.Errorf("expected wider vertical axis: got:%v fixed:%v", v.size(), fixed.size())
+	}
+}
+
+func TestDenorm(t *testing.T) {
+	for _, test := range []struct {
+		scale    Normalizer
+		min, max float64
+	}{
+		{scale: LinearScale{}, min: -3, max: 5},
+		{scale: LogScale{}, min: 0.1, max: 1000},
+	} {
+		a := Axis{Min: test.min, Max: test.max, Scale: test.scale}
+		for _, x := range []float64{test.min, 0.5, 2, test.max, 2 * test.max} {
+			if got := a.Denorm(a.Norm(x)); math.Abs(got-x) > 1e-12*math.Abs(x) {
+				t.Errorf("unexpected inverse of %T normalization of %v: got:%v", test.scale, x, got)
+			}
+		}
+	}
+
+	a := Axis{Min: 0, Max: 1, Scale: sqrtScale{}}
+	if got := a.Denorm(0.5); !math.IsNaN(got) {
+		t.Errorf("unexpected inverse of scale without Denormalize: got:%v want:NaN", got)
+	}
+}
+
+// sqrtScale is a Normalizer that does not implement Denormalizer.
+type sqrtScale struct{}
+
+func (sqrtScale) Normalize(min, max, x float64) float64 {
+	return (math.Sqrt(x) - math.Sqrt(min)) / (math.Sqrt(max) - math.Sqrt(min))
+}
@@ -30,6 +30,8 @@ import (
 	"github.com/gonum/plot/vg/vgimg"
 	"github.com/gonum/plot/vg/vgpdf"
 	"github.com/gonum/plot/vg/vgsvg"
+	"github.com/gonum/plot/vg/vgterm"
+	"github.com/gonum/plot/vg/vgtex"
 )
 
 var (
@@ -42,7 +44,10 @@ type Plot struct {
 	Title struct {
 		// Text is the text of the plot title.  If
 		// Text is the empty string then the plot
-		// will not have a title.
+		// will not have a title.  The text may
+		// contain markup as described for
+		// draw.TextStyle if the Markup field
+		// of TextStyle is set.
 		Text string
 
 		// Padding is the amount of padding
@@ -122,7 +127,8 @@ func New() (*Plot, error) {
 // If the plotters implements DataRanger then the
 // minimum and maximum values of the X and Y
 // axes are changed if necessary to fit the range of
-// the data.
+// the data.  Bounds of the range that are NaN, such
+// as those of data that are all missing, are ignored.
 //
 // When drawing the plot, Plotters are drawn in the
 // order in which they were added to the plot.
@@ -130,16 +136,34 @@ func (p *Plot) Add(ps ...Plotter) {
 	for _, d := range ps {
 		if x, ok := d.(DataRanger); ok {
 			xmin, xmax, ymin, ymax := x.DataRange()
-			p.X.Min = math.Min(p.X.Min, xmin)
-			p.X.Max = math.Max(p.X.Max, xmax)
-			p.Y.Min = math.Min(p.Y.Min, ymin)
-			p.Y.Max = math.Max(p.Y.Max, ymax)
+			p.X.Min = minIgnoreNaN(p.X.Min, xmin)
+			p.X.Max = maxIgnoreNaN(p.X.Max, xmax)
+			p.Y.Min = minIgnoreNaN(p.Y.Min, ymin)
+			p.Y.Max = maxIgnoreNaN(p.Y.Max, ymax)
 		}
 	}
 
 	p.plotters = append(p.plotters, ps...)
 }
 
+// minIgnoreNaN returns the minimum of a and b,
+// or a if b is NaN.
+func minIgnoreNaN(a, b float64) float64 {
+	if math.IsNaN(b) {
+		return a
+	}
+	return math.Min(a, b)
+}
+
+// maxIgnoreNaN returns the maximum of a and b,
+// or a if b is NaN.
+func maxIgnoreNaN(a, b float64) float64 {
+	if math.IsNaN(b) {
+		return a
+	}
+	return math.Max(a, b)
+}
+
 // Draw draws a plot to a draw.Canvas.
 //
 // Plotters are drawn in the order in which they were
@@ -158,17 +182,13 @@ func (p *Plot) Draw(c draw.Canvas) {
 		c.Max.Y -= p.Title.Padding
 	}
 
-	p.X.sanitizeRange()
-	x := horizontalAxis{p.X}
-	p.Y.sanitizeRange()
-	y := verticalAxis{p.Y}
-
+	x, y := p.axes(c)
 	ywidth := y.size()
-	x.draw(padX(p, c.Crop(ywidth, 0, 0, 0)))
+	x.draw(padX(p, x, c.Crop(ywidth, 0, 0, 0)))
 	xheight := x.size()
-	y.draw(padY(p, c.Crop(0, xheight, 0, 0)))
+	y.draw(padY(p, y, c.Crop(0, xheight, 0, 0)))
 
-	dataC := padY(p, padX(p, c.Crop(ywidth, xheight, 0, 0)))
+	dataC := padY(p, y, padX(p, x, c.Crop(ywidth, xheight, 0, 0)))
 	for _, data := range p.plotters {
 		data.Plot(dataC, p)
 	}
@@ -184,11 +204,22 @@ func (p *Plot) DataCanvas(da draw.Canvas) draw.Canvas {
 		da.Max.Y -= p.Title.Height(p.Title.Text) - p.Title.Font.Extents().Descent
 		da.Max.Y -= p.Title.Padding
 	}
+	x, y := p.axes(da)
+	return padY(p, y, padX(p, x, da.Crop(y.size(), x.size(), 0, 0)))
+}
+
+// axes returns the plot's axes with their tick
+// label layouts resolved for the given draw area.
+func (p *Plot) axes(da draw.Canvas) (horizontalAxis, verticalAxis) {
 	p.X.sanitizeRange()
-	x := horizontalAxis{p.X}
+	x := horizontalAxis{Axis: p.X}
 	p.Y.sanitizeRange()
-	y := verticalAxis{p.Y}
-	return padY(p, padX(p, da.Crop(y.size(), x.size(), 0, 0)))
+	y := verticalAxis{Axis: p.Y}
+
+	size := da.Size()
+	x.layout(size.X - y.size())
+	y.layout(size.Y - x.size())
+	return x, y
 }
 
 // DrawGlyphBoxes draws red outlines around the plot's
@@ -204,10 +235,9 @@ func (p *Plot) DrawGlyphBoxes(c *draw.Canvas) {
 
 // padX returns a draw.Canvas that is padded horizontally
 // so that glyphs will no be clipped.
-func padX(p *Plot, c draw.Canvas) draw.Canvas {
+func padX(p *Plot, xAxis horizontalAxis, c draw.Canvas) draw.Canvas {
 	glyphs := p.GlyphBoxes(p)
 	l := leftMost(&c, glyphs)
-	xAxis := horizontalAxis{p.X}
 	glyphs = append(glyphs, xAxis.GlyphBoxes(p)...)
 	r := rightMost(&c, glyphs)
 
@@ -260,10 +290,9 @@ func leftMost(c *draw.Canvas, boxes []GlyphBox) GlyphBox {
 
 // padY returns a draw.Canvas that is padded vertically
 // so that glyphs will no be clipped.
-func padY(p *Plot, c draw.Canvas) draw.Canvas {
+func padY(p *Plot, yAxis verticalAxis, c draw.Canvas) draw.Canvas {
 	glyphs := p.GlyphBoxes(p)
 	b := bottomMost(&c, glyphs)
-	yAxis := verticalAxis{p.Y}
 	glyphs = append(glyphs, yAxis.GlyphBoxes(p)...)
 	t := topMost(&c, glyphs)
 
@@ -324,6 +353,57 @@ func (p *Plot) Transforms(c *draw.Canvas) (x, y func(float64) vg.Length) {
 	return
 }
 
+// InverseTransforms returns functions to transform
+// from the draw coordinate system of the given draw
+// area to the x and y data coordinate system.  They
+// are the inverses of the functions returned by
+// Transforms.
+func (p *Plot) InverseTransforms(c *draw.Canvas) (x, y func(vg.Length) float64) {
+	x = func(x vg.Length) float64 { return p.X.Denorm(float64((x - c.Min.X) / c.Size().X)) }
+	y = func(y vg.Length) float64 { return p.Y.Denorm(float64((y - c.Min.Y) / c.Size().Y)) }
+	return
+}
+
+// Picker wraps the Pick method.  It should be
+// implemented by Plotters that can report which
+// of their data items is drawn at a point of the
+// canvas, for example to show a tooltip for the
+// item under the mouse pointer.
+type Picker interface {
+	// Pick returns the index of the data item drawn
+	// nearest to the point pt of the draw.Canvas c,
+	// which is the canvas passed to the Plot method,
+	// and the distance between the item and pt.  The
+	// returned ok is false if no item is within the
+	// distance tol of pt.
+	Pick(c draw.Canvas, plt *Plot, pt draw.Point, tol vg.Length) (i int, dist vg.Length, ok bool)
+}
+
+// Pick returns the plotter and the index of its data
+// item that is drawn nearest to the point pt when the
+// plot is drawn to the draw.Canvas c.  Only plotters
+// that implement the Picker interface and items that
+// are within the distance tol of pt are considered.
+// When items of several plotters are equally near, the
+// item of the plotter that is drawn last is returned.
+// The returned ok is false if no item is found.
+func (p *Plot) Pick(c draw.Canvas, pt draw.Point, tol vg.Length) (pl Plotter, i int, ok bool) {
+	dataC := p.DataCanvas(c)
+	var min vg.Length
+	for _, d := range p.plotters {
+		pk, isPicker := d.(Picker)
+		if !isPicker {
+			continue
+		}
+		j, dist, hit := pk.Pick(dataC, p, pt, tol)
+		if !hit || (ok && dist > min) {
+			continue
+		}
+		pl, i, min, ok = d, j, dist, true
+	}
+	return pl, i, ok
+}
+
 // GlyphBoxer wraps the GlyphBoxes method.
 // It should be implemented by things that meet
 // the Plotter interface that draw glyphs so that
@@ -375,6 +455,9 @@ func (p *Plot) GlyphBoxes(*Plot) (boxes []GlyphBox) {
 			continue
 		}
 		for _, b := range gb.GlyphBoxes(p) {
+			if math.IsNaN(b.X) || math.IsNaN(b.Y) {
+				continue
+			}
 			if b.Size().X > 0 && (b.X < 0 || b.X > 1) {
 				continue
 			}
@@ -393,7 +476,8 @@ func (p *Plot) GlyphBoxes(*Plot) (boxes []GlyphBox) {
 // e.g., the x value 0 is centered above the first name and
 // 1 is above the second name, etc.  Labels for x values
 // that do not end up in range of the X axis will not have
-// tick marks.
+// tick marks.  Names that overlap can be rotated by
+// setting p.X.Tick.Layout to RotateTickLabels.
 func (p *Plot) NominalX(names ...string) {
 	p.X.Tick.Width = 0
 	p.X.Tick.Length = 0
@@ -444,31 +528,51 @@ func (p *Plot) NominalY(names ...string) {
 //
 // Supported formats are:
 //
-//  eps, jpg|jpeg, pdf, png, svg, and tif|tiff.
+//  ansi, eps, jpg|jpeg, kitty, pdf, png, sixel, svg, tex, tif|tiff, and txt.
+//
+// The ansi and txt formats draw the plot as text for display
+// on a terminal, with and without color escape sequences.
+// The kitty and sixel formats write a raster image for display
+// on terminals supporting the Kitty graphics protocol or Sixel.
 func (p *Plot) WriterTo(w, h vg.Length, format string) (io.WriterTo, error) {
 	var c interface {
 		vg.CanvasSizer
 		io.WriterTo
 	}
 	switch format {
+	case "ansi":
+		c = vgterm.New(w, h)
+
 	case "eps":
 		c = vgeps.New(w, h)
 
 	case "jpg", "jpeg":
 		c = vgimg.JpegCanvas{Canvas: vgimg.New(w, h)}
 
+	case "kitty":
+		c = vgimg.KittyCanvas{Canvas: vgimg.New(w, h)}
+
 	case "pdf":
 		c = vgpdf.New(w, h)
 
 	case "png":
 		c = vgimg.PngCanvas{Canvas: vgimg.New(w, h)}
 
+	case "sixel":
+		c = vgimg.SixelCanvas{Canvas: vgimg.New(w, h)}
+
 	case "svg":
 		c = vgsvg.New(w, h)
 
+	case "tex":
+		c = vgtex.NewWith(w, h, vgtex.Standalone())
+
 	case "tif", "tiff":
 		c = vgimg.TiffCanvas{Canvas: vgimg.New(w, h)}
 
+	case "txt":
+		c = vgterm.NewWith(w, h, vgterm.UseColors(vgterm.NoColor))
+
 	default:
 		return nil, fmt.Errorf("unsupported format: %q", format)
 	}
@@ -482,7 +586,8 @@ func (p *Plot) WriterTo(w, h vg.Length, format string) (io.WriterTo, error) {
 //
 // Supported extensions are:
 //
-//  .eps, .jpg, .jpeg, .pdf, .png, .svg, .tif and .tiff.
+//  .ansi, .eps, .jpg, .jpeg, .kitty, .pdf, .png, .sixel, .svg, .tex,
+//  .tif, .tiff and .txt.
 func (p *Plot) Save(w, h vg.Length, file string) (err error) {
 	f, err := os.Create(file)
 	if err != nil {
@@ -8,7 +8,9 @@ import (
 	"bytes"
 	"fmt"
 	"image/color"
+	"math"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/gonum/plot"
@@ -70,6 +72,11 @@ func TestLegendAlignment(t *testing.T) {
 			Width: 1,
 		},
 		&recorder.SetLineDash{},
+		&recorder.SetLineCap{},
+		&recorder.SetLineJoin{},
+		&recorder.SetMiterLimit{
+			Limit: vg.DefaultMiterLimit,
+		},
 		&recorder.Stroke{
 			Path: vg.Path{
 				{Type: vg.MoveComp, X: 80, Y: 30},
@@ -106,6 +113,11 @@ func TestLegendAlignment(t *testing.T) {
 			Width: 1,
 		},
 		&recorder.SetLineDash{},
+		&recorder.SetLineCap{},
+		&recorder.SetLineJoin{},
+		&recorder.SetMiterLimit{
+			Limit: vg.DefaultMiterLimit,
+		},
 		&recorder.Stroke{
 			Path: vg.Path{
 				{Type: vg.MoveComp, X: 80, Y: 20},
@@ -144,6 +156,11 @@ func TestLegendAlignment(t *testing.T) {
 			Width: 1,
 		},
 		&recorder.SetLineDash{},
+		&recorder.SetLineCap{},
+		&recorder.SetLineJoin{},
+		&recorder.SetMiterLimit{
+			Limit: vg.DefaultMiterLimit,
+		},
 		&recorder.Stroke{
 			Path: vg.Path{
 				{Type: vg.MoveComp, X: 80, Y: 10},
@@ -180,6 +197,11 @@ func TestLegendAlignment(t *testing.T) {
 			Width: 1,
 		},
 		&recorder.SetLineDash{},
+		&recorder.SetLineCap{},
+		&recorder.SetLineJoin{},
+		&recorder.SetMiterLimit{
+			Limit: vg.DefaultMiterLimit,
+		},
 		&recorder.Stroke{
 			Path: vg.Path{
 				{Type: vg.MoveComp, X: 80, Y: 0},
@@ -211,3 +233,135 @@ func formatActions(actions []recorder.Action) string {
 	}
 	return buf.String()
 }
+
+func TestInverseTransforms(t *testing.T) {
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("error creating plot: %v", err)
+	}
+	p.X.Min, p.X.Max = -1, 3
+	p.Y.Min, p.Y.Max = 1, 1000
+	p.Y.Scale = plot.LogScale{}
+
+	c := draw.NewCanvas(recorder.New(72), 200, 100)
+	c = c.Crop(20, 10, -5, -5)
+	trX, trY := p.Transforms(&c)
+	invX, invY := p.InverseTransforms(&c)
+	for _, v := range []float64{1, 2.5, 10, 999} {
+		if got := invX(trX(v)); math.Abs(got-v) > 1e-9 {
+			t.Errorf("unexpected x round trip of %v: got:%v", v, got)
+		}
+		if got := invY(trY(v)); math.Abs(got-v) > 1e-9*v {
+			t.Errorf("unexpected y round trip of %v: got:%v", v, got)
+		}
+	}
+	if got := invX(c.Min.X); got != -1 {
+		t.Errorf("unexpected x value at left of canvas: got:%v want:-1", got)
+	}
+}
+
+func TestPick(t *testing.T) {
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("error creating plot: %v", err)
+	}
+	below, err := plotter.NewScatter(plotter.XYs{{0, 0}, {1, 1}, {2, 2}})
+	if err != nil {
+		t.Fatalf("error creating scatter: %v", err)
+	}
+	above, err := plotter.NewScatter(plotter.XYs{{2, 2}, {2, 0}})
+	if err != nil {
+		t.Fatalf("error creating scatter: %v", err)
+	}
+	l, err := plotter.NewLine(plotter.XYs{{0, 2}, {2, 0}})
+	if err != nil {
+		t.Fatalf("error creating line: %v", err)
+	}
+	p.Add(plotter.NewGrid(), below, above, l)
+
+	c := draw.NewCanvas(recorder.New(72), 200, 200)
+	dataC := p.DataCanvas(c)
+	trX, trY := p.Transforms(&dataC)
+	for _, test := range []struct {
+		x, y    float64
+		dx      vg.Length
+		plotter plot.Plotter
+		index   int
+		ok      bool
+	}{
+		{x: 1, y: 1, dx: 2, plotter: below, index: 1, ok: true},
+		{x: 2, y: 2, plotter: above, index: 0, ok: true},
+		{x: 0, y: 2, dx: -3, plotter: l, index: 0, ok: true},
+		{x: 1, y: 0, ok: false},
+		{x: 1, y: 1, dx: 20, ok: false},
+	} {
+		pt := draw.Point{X: trX(test.x) + test.dx, Y: trY(test.y)}
+		pl, i, ok := p.Pick(c, pt, 5)
+		if ok != test.ok {
+			t.Errorf("unexpected pick result at (%v,%v)+%v: got:%t want:%t", test.x, test.y, test.dx, ok, test.ok)
+			continue
+		}
+		if ok && (pl != test.plotter || i != test.index) {
+			t.Errorf("unexpected pick at (%v,%v)+%v: got:%p[%d] want:%p[%d]", test.x, test.y, test.dx, pl, i, test.plotter, test.index)
+		}
+	}
+}
+
+// nanRanger is a plotter whose data range is NaN.
+type nanRanger struct{}
+
+func (nanRanger) Plot(draw.Canvas, *plot.Plot) {}
+
+func (nanRanger) DataRange() (xmin, xmax, ymin, ymax float64) {
+	return math.NaN(), math.NaN(), math.NaN(), math.NaN()
+}
+
+func (nanRanger) GlyphBoxes(*plot.Plot) []plot.GlyphBox {
+	return []plot.GlyphBox{{X: math.NaN(), Y: 0.5, Rectangle: draw.Rectangle{Max: draw.Point{X: 10, Y: 10}}}}
+}
+
+func TestAddMissing(t *testing.T) {
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("error creating plot: %v", err)
+	}
+	l, err := plotter.NewLine(plotter.XYs{{0, 1}, {1, math.NaN()}, {2, 3}})
+	if err != nil {
+		t.Fatalf("error creating line: %v", err)
+	}
+	p.Add(l, nanRanger{})
+	if p.X.Min != 0 || p.X.Max != 2 || p.Y.Min != 1 || p.Y.Max != 3 {
+		t.Errorf("unexpected axis ranges: got:[%v, %v]x[%v, %v] want:[0, 2]x[1, 3]", p.X.Min, p.X.Max, p.Y.Min, p.Y.Max)
+	}
+	if boxes := p.GlyphBoxes(p); len(boxes) != 0 {
+		t.Errorf("unexpected glyph boxes: got:%v", boxes)
+	}
+}
+
+func TestTexTitle(t *testing.T) {
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const title = `$\alpha^{2}_{i}$`
+	p.Title.Text = title
+	p.Title.Markup = true
+
+	w, err := p.WriterTo(4*vg.Inch, 3*vg.Inch, "tex")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err := w.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var texts []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasPrefix(line, `\pgftext`) && strings.Contains(line, `\alpha`) {
+			texts = append(texts, line)
+		}
+	}
+	if len(texts) != 1 || !strings.HasSuffix(texts[0], title+"}") {
+		t.Errorf("unexpected title text: got:%q want a single \\pgftext ending in %q", texts, title+"}")
+	}
+}
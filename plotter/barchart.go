@@ -6,6 +6,7 @@ package plotter
 
 import (
 	"errors"
+	"fmt"
 	"image/color"
 	"math"
 
@@ -14,6 +15,27 @@ import (
 	"github.com/gonum/plot/vg/draw"
 )
 
+// BarLabelPosition is the position of the value
+// labels of a bar chart.
+type BarLabelPosition int
+
+const (
+	// NoBarLabels draws no value labels.
+	NoBarLabels BarLabelPosition = iota
+
+	// LabelsAbove draws each label just beyond
+	// the end of its bar.
+	LabelsAbove
+
+	// LabelsInside draws each label just inside
+	// the end of its bar.
+	LabelsInside
+)
+
+// barLabelPad is the distance between a
+// value label and the end of its bar.
+var barLabelPad = vg.Points(2)
+
 type BarChart struct {
 	Values
 
@@ -37,6 +59,42 @@ type BarChart struct {
 	// bar charts.
 	XMin float64
 
+	// Horizontal specifies whether the bars extend
+	// along the x axis rather than the y axis.  The
+	// bars of a horizontal bar chart are located
+	// along the y axis, so that XMin and Offset
+	// apply to the y axis.
+	Horizontal bool
+
+	// Errors are the low and high errors of the
+	// values, drawn as error bars at the ends of the
+	// bars.  Errors are taken as absolute values and
+	// each bar extends from its end less its low error
+	// to its end plus its high error.  If Errors is
+	// nil then no error bars are drawn.
+	Errors Errors
+
+	// ErrorStyle is the line style of the error bars.
+	ErrorStyle draw.LineStyle
+
+	// CapWidth is the width of the caps of the
+	// error bars.
+	CapWidth vg.Length
+
+	// ValueLabels is the position of the labels
+	// of the values of the bars.
+	ValueLabels BarLabelPosition
+
+	// LabelFormat is the fmt format of the value
+	// labels.
+	LabelFormat string
+
+	// LabelStyle is the text style of the value labels.
+	// If its font is not set, the default font is
+	// loaded when the labels are measured or drawn,
+	// with the size of the font if it is set.
+	LabelStyle draw.TextStyle
+
 	// stackedOn is the bar chart upon which
 	// this bar chart is stacked.
 	stackedOn *BarChart
@@ -44,38 +102,78 @@ type BarChart struct {
 
 // NewBarChart returns a new bar chart with a single bar for each value.
 // The bars heights correspond to the values and their x locations correspond
-// to the index of their value in the Valuer.
+// to the index of their value in the Valuer.  Missing values
+// are kept, and no bar is drawn for them.  They can be filled
+// in first with CopyValuesMissing and MissingInterpolate.
 func NewBarChart(vs Valuer, width vg.Length) (*BarChart, error) {
 	if width <= 0 {
 		return nil, errors.New("Width parameter was not positive")
 	}
-	values, err := CopyValues(vs)
+	values, err := CopyValuesMissing(vs, MissingBreak)
 	if err != nil {
 		return nil, err
 	}
 	return &BarChart{
-		Values:    values,
-		Width:     width,
-		Color:     color.Black,
-		LineStyle: DefaultLineStyle,
+		Values:      values,
+		Width:       width,
+		Color:       color.Black,
+		LineStyle:   DefaultLineStyle,
+		ErrorStyle:  DefaultLineStyle,
+		CapWidth:    DefaultCapWidth,
+		LabelFormat: "%g",
 	}, nil
 }
 
-// BarHeight returns the maximum y value of the
+// labelStyle returns the text style of the value
+// labels, with the default font if no font is set,
+// and whether the font could be loaded.
+func (b *BarChart) labelStyle() (draw.TextStyle, bool) {
+	sty := b.LabelStyle
+	if sty.Font.Font() != nil {
+		return sty, true
+	}
+	size := sty.Font.Size
+	if size == 0 {
+		size = DefaultFontSize
+	}
+	fnt, err := vg.MakeFont(DefaultFont, size)
+	if err != nil {
+		return sty, false
+	}
+	sty.Font = fnt
+	return sty, true
+}
+
+// BarHeight returns the value at the end of the
 // ith bar, taking into account any bars upon
-// which it is stacked.
+// which it is stacked.  Positive values are
+// stacked upon the positive values of the bars
+// beneath them and negative values upon the
+// negative values, below zero.
 func (b *BarChart) BarHeight(i int) float64 {
-	ht := 0.0
 	if b == nil {
 		return 0
 	}
+	var v float64
 	if i >= 0 && i < len(b.Values) {
-		ht += b.Values[i]
+		v = b.Values[i]
+	}
+	return b.stackedOn.stackBase(i, v >= 0) + v
+}
+
+// stackBase returns the sum of the non-negative
+// values, if pos is true, or of the negative
+// values otherwise, of the ith bars of the bar
+// chart and those upon which it is stacked.
+func (b *BarChart) stackBase(i int, pos bool) float64 {
+	if b == nil {
+		return 0
 	}
-	if b.stackedOn != nil {
-		ht += b.stackedOn.BarHeight(i)
+	var v float64
+	if i >= 0 && i < len(b.Values) && !math.IsNaN(b.Values[i]) && (b.Values[i] >= 0) == pos {
+		v = b.Values[i]
 	}
-	return ht
+	return v + b.stackedOn.stackBase(i, pos)
 }
 
 // StackOn stacks a bar chart on top of another,
@@ -87,35 +185,185 @@ func (b *BarChart) StackOn(on *BarChart) {
 	b.stackedOn = on
 }
 
+// StackBars stacks each of the bar charts
+// on the bar chart before it.
+func StackBars(bs ...*BarChart) {
+	for i := 1; i < len(bs); i++ {
+		bs[i].StackOn(bs[i-1])
+	}
+}
+
+// StackBarsPercent scales the values of the bar
+// charts so that the magnitudes of the values at
+// each location sum to 100, and stacks each bar
+// chart on the bar chart before it, making a 100%
+// stacked bar chart.  Locations at which all
+// values are zero are left unchanged.
+func StackBarsPercent(bs ...*BarChart) {
+	var n int
+	for _, b := range bs {
+		if len(b.Values) > n {
+			n = len(b.Values)
+		}
+	}
+	for i := 0; i < n; i++ {
+		var sum float64
+		for _, b := range bs {
+			if i < len(b.Values) && !math.IsNaN(b.Values[i]) {
+				sum += math.Abs(b.Values[i])
+			}
+		}
+		if sum == 0 {
+			continue
+		}
+		for _, b := range bs {
+			if i < len(b.Values) {
+				b.Values[i] *= 100 / sum
+			}
+		}
+	}
+	StackBars(bs...)
+}
+
+// GroupBars lays out the bar charts side by side at
+// each location, in order, setting their widths to
+// divide width equally and their offsets to center
+// each group of bars on its location.  Bar charts
+// should be stacked upon grouped bar charts after
+// grouping.
+func GroupBars(width vg.Length, bs ...*BarChart) {
+	w := width / vg.Length(len(bs))
+	for i, b := range bs {
+		b.Width = w
+		b.Offset = (vg.Length(i) - vg.Length(len(bs)-1)/2) * w
+	}
+}
+
+// bar returns the corners of the ith bar on the
+// canvas, the first at the base of the bar and
+// the second at its end, and whether the location
+// of the bar is within the canvas and its value is
+// not missing.
+func (b *BarChart) bar(c *draw.Canvas, plt *plot.Plot, i int) (base, end draw.Point, ok bool) {
+	if math.IsNaN(b.Values[i]) {
+		return draw.Point{}, draw.Point{}, false
+	}
+	trX, trY := plt.Transforms(c)
+	trLoc, trVal, contains := trX, trY, c.ContainsX
+	if b.Horizontal {
+		trLoc, trVal, contains = trY, trX, c.ContainsY
+	}
+	loc := trLoc(b.XMin + float64(i))
+	if !contains(loc) {
+		return draw.Point{}, draw.Point{}, false
+	}
+	lo := loc - b.Width/2 + b.Offset
+	hi := lo + b.Width
+	bottom := b.stackedOn.stackBase(i, b.Values[i] >= 0)
+	vlo := trVal(bottom)
+	vhi := trVal(bottom + b.Values[i])
+	if b.Horizontal {
+		return draw.Point{vlo, lo}, draw.Point{vhi, hi}, true
+	}
+	return draw.Point{lo, vlo}, draw.Point{hi, vhi}, true
+}
+
 // Plot implements the plot.Plotter interface.
 func (b *BarChart) Plot(c draw.Canvas, plt *plot.Plot) {
 	trX, trY := plt.Transforms(&c)
+	clipLines := c.ClipLinesY
+	if b.Horizontal {
+		clipLines = c.ClipLinesX
+	}
 
-	for i, ht := range b.Values {
-		x := b.XMin + float64(i)
-		xmin := trX(float64(x))
-		if !c.ContainsX(xmin) {
+	for i := range b.Values {
+		base, end, ok := b.bar(&c, plt, i)
+		if !ok {
 			continue
 		}
-		xmin = xmin - b.Width/2 + b.Offset
-		xmax := xmin + b.Width
-		bottom := b.stackedOn.BarHeight(i)
-		ymin := trY(bottom)
-		ymax := trY(bottom + ht)
+		drawBar(&c, base, end, b.Horizontal, b.Color, b.LineStyle)
+	}
+
+	if b.Errors != nil {
+		for i := range b.Values {
+			base, end, ok := b.bar(&c, plt, i)
+			if !ok || i >= len(b.Errors) {
+				continue
+			}
+			top := b.BarHeight(i)
+			lo := top - math.Abs(b.Errors[i].Low)
+			hi := top + math.Abs(b.Errors[i].High)
+			cap := b.CapWidth / 2
+			var lines [][]draw.Point
+			if b.Horizontal {
+				y := (base.Y + end.Y) / 2
+				xlo, xhi := trX(lo), trX(hi)
+				lines = clipLines([]draw.Point{{xlo, y}, {xhi, y}},
+					[]draw.Point{{xlo, y - cap}, {xlo, y + cap}},
+					[]draw.Point{{xhi, y - cap}, {xhi, y + cap}})
+			} else {
+				x := (base.X + end.X) / 2
+				ylo, yhi := trY(lo), trY(hi)
+				lines = clipLines([]draw.Point{{x, ylo}, {x, yhi}},
+					[]draw.Point{{x - cap, ylo}, {x + cap, ylo}},
+					[]draw.Point{{x - cap, yhi}, {x + cap, yhi}})
+			}
+			c.StrokeLines(b.ErrorStyle, lines...)
+		}
+	}
 
-		pts := []draw.Point{
-			{xmin, ymin},
-			{xmin, ymax},
-			{xmax, ymax},
-			{xmax, ymin},
+	if sty, ok := b.labelStyle(); ok && b.ValueLabels != NoBarLabels {
+		for i, v := range b.Values {
+			base, end, ok := b.bar(&c, plt, i)
+			if !ok || (b.Horizontal && !c.ContainsX(end.X)) || (!b.Horizontal && !c.ContainsY(end.Y)) {
+				continue
+			}
+			x, y, xalign, yalign := b.labelPosition(base, end)
+			txt := fmt.Sprintf(b.LabelFormat, v)
+			c.FillText(sty, x, y, xalign, yalign, txt)
 		}
-		poly := c.ClipPolygonY(pts)
-		c.FillPolygon(b.Color, poly)
+	}
+}
+
+// drawBar fills and outlines the bar with the opposite
+// corners base and end, clipping it along the y axis,
+// or along the x axis if horiz is true.
+func drawBar(c *draw.Canvas, base, end draw.Point, horiz bool, fill color.Color, line draw.LineStyle) {
+	clipLines, clipPolygon := c.ClipLinesY, c.ClipPolygonY
+	if horiz {
+		clipLines, clipPolygon = c.ClipLinesX, c.ClipPolygonX
+	}
+	pts := []draw.Point{
+		{base.X, base.Y},
+		{base.X, end.Y},
+		{end.X, end.Y},
+		{end.X, base.Y},
+	}
+	poly := clipPolygon(pts)
+	c.FillPolygon(fill, poly)
+
+	pts = append(pts, draw.Point{base.X, base.Y})
+	outline := clipLines(pts)
+	c.StrokeLines(line, outline...)
+}
 
-		pts = append(pts, draw.Point{xmin, ymin})
-		outline := c.ClipLinesY(pts)
-		c.StrokeLines(b.LineStyle, outline...)
+// labelPosition returns the position and alignment of
+// the value label of the bar with the given corners.
+func (b *BarChart) labelPosition(base, end draw.Point) (x, y vg.Length, xalign, yalign float64) {
+	pad, align := barLabelPad, 0.0
+	if b.ValueLabels == LabelsInside {
+		pad, align = -barLabelPad, -1
 	}
+	if b.Horizontal {
+		if end.X < base.X {
+			pad, align = -pad, -1-align
+		}
+		return end.X + pad, (base.Y + end.Y) / 2, align, -0.5
+	}
+	if end.Y < base.Y {
+		pad, align = -pad, -1-align
+	}
+	return (base.X + end.X) / 2, end.Y + pad, -0.5, align
 }
 
 // DataRange implements the plot.DataRanger interface.
@@ -126,29 +374,130 @@ func (b *BarChart) DataRange() (xmin, xmax, ymin, ymax float64) {
 	ymin = math.Inf(1)
 	ymax = math.Inf(-1)
 	for i, y := range b.Values {
-		ybot := b.stackedOn.BarHeight(i)
+		if math.IsNaN(y) {
+			continue
+		}
+		ybot := b.stackedOn.stackBase(i, y >= 0)
 		ytop := ybot + y
 		ymin = math.Min(ymin, math.Min(ybot, ytop))
 		ymax = math.Max(ymax, math.Max(ybot, ytop))
+		if i < len(b.Errors) {
+			ymin = math.Min(ymin, ytop-math.Abs(b.Errors[i].Low))
+			ymax = math.Max(ymax, ytop+math.Abs(b.Errors[i].High))
+		}
+	}
+	if b.Horizontal {
+		return ymin, ymax, xmin, xmax
 	}
 	return
 }
 
+// Pick returns the index of the bar nearest to pt,
+// implementing the plot.Picker interface.
+func (b *BarChart) Pick(c draw.Canvas, plt *plot.Plot, pt draw.Point, tol vg.Length) (int, vg.Length, bool) {
+	pk := picker{tol: tol}
+	for i := range b.Values {
+		base, end, ok := b.bar(&c, plt, i)
+		if !ok {
+			continue
+		}
+		pk.add(i, rectDistance(base, end, pt))
+	}
+	return pk.result()
+}
+
 // GlyphBoxes implements the GlyphBoxer interface.
+// There is a box for the width of each bar and,
+// for labels above the bars, a box for each label.
 func (b *BarChart) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
 	boxes := make([]plot.GlyphBox, len(b.Values))
 	for i := range b.Values {
 		x := b.XMin + float64(i)
-		boxes[i].X = plt.X.Norm(x)
 		boxes[i].Rectangle = draw.Rectangle{
 			Min: draw.Point{X: b.Offset - b.Width/2},
 			Max: draw.Point{X: b.Offset + b.Width/2},
 		}
+		if b.Horizontal {
+			boxes[i].Y = plt.Y.Norm(x)
+			boxes[i].Rectangle = transposeRect(boxes[i].Rectangle)
+			continue
+		}
+		boxes[i].X = plt.X.Norm(x)
+	}
+	if b.ValueLabels != LabelsAbove {
+		return boxes
+	}
+	sty, ok := b.labelStyle()
+	if !ok {
+		return boxes
+	}
+	for i, v := range b.Values {
+		if math.IsNaN(v) {
+			continue
+		}
+		txt := fmt.Sprintf(b.LabelFormat, v)
+		w, h := sty.Width(txt), sty.Height(txt)
+		r := draw.Rectangle{
+			Min: draw.Point{X: b.Offset - w/2, Y: barLabelPad},
+			Max: draw.Point{X: b.Offset + w/2, Y: barLabelPad + h},
+		}
+		if b.Horizontal {
+			r = draw.Rectangle{
+				Min: draw.Point{X: barLabelPad, Y: b.Offset - h/2},
+				Max: draw.Point{X: barLabelPad + w, Y: b.Offset + h/2},
+			}
+		}
+		top := b.BarHeight(i)
+		if v < 0 {
+			if b.Horizontal {
+				r.Min.X, r.Max.X = -r.Max.X, -r.Min.X
+			} else {
+				r.Min.Y, r.Max.Y = -r.Max.Y, -r.Min.Y
+			}
+		}
+		box := plot.GlyphBox{
+			X:         plt.X.Norm(b.XMin + float64(i)),
+			Y:         plt.Y.Norm(top),
+			Rectangle: r,
+		}
+		if b.Horizontal {
+			box.X, box.Y = plt.X.Norm(top), plt.Y.Norm(b.XMin+float64(i))
+		}
+		boxes = append(boxes, box)
 	}
 	return boxes
 }
 
+// transposeRect returns the rectangle with its
+// x and y coordinates exchanged.
+func transposeRect(r draw.Rectangle) draw.Rectangle {
+	return draw.Rectangle{
+		Min: draw.Point{r.Min.Y, r.Min.X},
+		Max: draw.Point{r.Max.Y, r.Max.X},
+	}
+}
+
+// Thumbnail draws a bar of the bar chart's color,
+// implementing the plot.Thumbnailer interface.
 func (b *BarChart) Thumbnail(c *draw.Canvas) {
+	barThumbnail(c, b.Color, b.LineStyle)
+}
+
+// barThumbnailer is a plot.Thumbnailer
+// that draws a bar.
+type barThumbnailer struct {
+	fill color.Color
+	line draw.LineStyle
+}
+
+// Thumbnail implements the plot.Thumbnailer interface.
+func (b barThumbnailer) Thumbnail(c *draw.Canvas) {
+	barThumbnail(c, b.fill, b.line)
+}
+
+// barThumbnail fills and outlines the canvas
+// as the thumbnail of a bar.
+func barThumbnail(c *draw.Canvas, fill color.Color, line draw.LineStyle) {
 	pts := []draw.Point{
 		{c.Min.X, c.Min.Y},
 		{c.Min.X, c.Max.Y},
@@ -156,9 +505,9 @@ func (b *BarChart) Thumbnail(c *draw.Canvas) {
 		{c.Max.X, c.Min.Y},
 	}
 	poly := c.ClipPolygonY(pts)
-	c.FillPolygon(b.Color, poly)
+	c.FillPolygon(fill, poly)
 
 	pts = append(pts, draw.Point{c.Min.X, c.Min.Y})
 	outline := c.ClipLinesY(pts)
-	c.StrokeLines(b.LineStyle, outline...)
+	c.StrokeLines(line, outline...)
 }
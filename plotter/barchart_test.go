@@ -0,0 +1,166 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/recorder"
+)
+
+func newBars(t *testing.T, vs ...Values) []*BarChart {
+	bs := make([]*BarChart, len(vs))
+	for i, v := range vs {
+		b, err := NewBarChart(v, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		bs[i] = b
+	}
+	return bs
+}
+
+func TestStackBarsNegative(t *testing.T) {
+	bs := newBars(t, Values{1, -1}, Values{-2, 2}, Values{3, -3})
+	StackBars(bs...)
+	for _, test := range []struct {
+		bar, i int
+		want   float64
+	}{
+		{bar: 0, i: 0, want: 1},
+		{bar: 1, i: 0, want: -2},
+		{bar: 2, i: 0, want: 4},
+		{bar: 1, i: 1, want: 2},
+		{bar: 2, i: 1, want: -4},
+	} {
+		if got := bs[test.bar].BarHeight(test.i); got != test.want {
+			t.Errorf("unexpected height of bar %d of chart %d: got:%v want:%v", test.i, test.bar, got, test.want)
+		}
+	}
+	_, _, ymin, ymax := bs[2].DataRange()
+	if ymin != -4 || ymax != 4 {
+		t.Errorf("unexpected y range: got:[%v,%v] want:[-4,4]", ymin, ymax)
+	}
+}
+
+func TestStackBarsPercent(t *testing.T) {
+	bs := newBars(t, Values{1, 0, 2}, Values{3, 0, -2})
+	StackBarsPercent(bs...)
+	want := [][]float64{{25, 0, 50}, {75, 0, -50}}
+	for i, b := range bs {
+		for j, v := range b.Values {
+			if v != want[i][j] {
+				t.Errorf("unexpected value %d of chart %d: got:%v want:%v", j, i, v, want[i][j])
+			}
+		}
+	}
+	if bs[1].BarHeight(0) != 100 {
+		t.Errorf("unexpected stacked height: got:%v want:100", bs[1].BarHeight(0))
+	}
+}
+
+func TestGroupBars(t *testing.T) {
+	bs := newBars(t, Values{1}, Values{2}, Values{3})
+	GroupBars(30, bs...)
+	for i, want := range []vg.Length{-10, 0, 10} {
+		if bs[i].Width != 10 || bs[i].Offset != want {
+			t.Errorf("unexpected layout of chart %d: got width:%v offset:%v want width:10 offset:%v", i, bs[i].Width, bs[i].Offset, want)
+		}
+	}
+}
+
+func TestHorizontalBarChart(t *testing.T) {
+	b := newBars(t, Values{1, 3, -2})[0]
+	b.Horizontal = true
+	b.Errors = Errors{{Low: 0.5, High: 0.5}, {Low: 1, High: 1}, {Low: 1, High: 1}}
+	xmin, xmax, ymin, ymax := b.DataRange()
+	if xmin != -3 || xmax != 4 || ymin != 0 || ymax != 2 {
+		t.Errorf("unexpected data range: got:%v,%v,%v,%v want:-3,4,0,2", xmin, xmax, ymin, ymax)
+	}
+
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.Add(b)
+	c := p.DataCanvas(draw.NewCanvas(recorder.New(72), 300, 300))
+	trX, trY := p.Transforms(&c)
+	if i, _, ok := b.Pick(c, p, draw.Point{trX(2), trY(1)}, 0); !ok || i != 1 {
+		t.Errorf("unexpected pick inside bar: got:%d,%t want:1,true", i, ok)
+	}
+	if _, _, ok := b.Pick(c, p, draw.Point{trX(2), trY(2)}, 0); ok {
+		t.Error("unexpected pick beyond the end of a bar")
+	}
+}
+
+func TestBarValueLabels(t *testing.T) {
+	for _, test := range []struct {
+		pos    BarLabelPosition
+		labels int
+		boxes  int
+	}{
+		{pos: NoBarLabels, labels: 0, boxes: 3},
+		{pos: LabelsAbove, labels: 3, boxes: 6},
+		{pos: LabelsInside, labels: 3, boxes: 3},
+	} {
+		b := newBars(t, Values{1, -2, 3})[0]
+		b.ValueLabels = test.pos
+		p, err := plot.New()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		p.Add(b)
+		r, c := drawOn(p, b)
+
+		_, trY := p.Transforms(&c)
+		var labels int
+		for _, a := range r.Actions {
+			a, ok := a.(*recorder.FillString)
+			if !ok {
+				continue
+			}
+			v, err := strconv.ParseFloat(a.String, 64)
+			if err != nil {
+				t.Fatalf("unexpected label %q: %v", a.String, err)
+			}
+			labels++
+			wantAbove := (test.pos == LabelsAbove) == (v >= 0)
+			if above := a.Y > trY(v); above != wantAbove {
+				t.Errorf("label %q on the wrong side of its bar end for position %d", a.String, test.pos)
+			}
+		}
+		if labels != test.labels {
+			t.Errorf("unexpected number of labels for position %d: got:%d want:%d", test.pos, labels, test.labels)
+		}
+		if boxes := len(b.GlyphBoxes(p)); boxes != test.boxes {
+			t.Errorf("unexpected number of glyph boxes for position %d: got:%d want:%d", test.pos, boxes, test.boxes)
+		}
+	}
+}
+
+func TestBarLabelFont(t *testing.T) {
+	b := newBars(t, Values{1, 2})[0]
+	if b.LabelStyle.Font.Font() != nil {
+		t.Error("unexpected font loaded by NewBarChart")
+	}
+	b.ValueLabels = LabelsAbove
+	for _, size := range []vg.Length{0, 20} {
+		b.LabelStyle.Font.Size = size
+		want := size
+		if want == 0 {
+			want = DefaultFontSize
+		}
+		r := drawPlotter(t, b)
+		for _, a := range r.Actions {
+			if a, ok := a.(*recorder.FillString); ok && (a.Font != DefaultFont || a.Size != want) {
+				t.Errorf("unexpected label font: got:%s %v want:%s %v", a.Font, a.Size, DefaultFont, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Binner wraps the Edges method.  Some standard
+// implementations choose the number of equal
+// width bins from the samples by a rule, while
+// others use the bins given by the user.
+type Binner interface {
+	// Edges returns the increasing edges of the bins
+	// of a histogram of the weighted samples in xys,
+	// where each y value is the weight of the
+	// corresponding x value.
+	Edges(xys XYer) ([]float64, error)
+}
+
+// BinCount is a Binner that returns the given number
+// of bins of equal width spanning the range of the
+// samples.  If the count is not positive then the
+// square root of the total weight of the samples
+// is used.
+type BinCount int
+
+// Edges implements the Binner interface.
+func (n BinCount) Edges(xys XYer) ([]float64, error) {
+	s, err := newSamples(xys)
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		m := 0.0
+		for _, w := range s.ws {
+			m += math.Max(w, 1.0)
+		}
+		n = BinCount(math.Ceil(math.Sqrt(m)))
+	}
+	return s.equalEdges(int(n)), nil
+}
+
+// BinEdges is a Binner that returns the given bin
+// edges, which must be finite and increasing.  The
+// bins need not be of equal width.
+type BinEdges []float64
+
+// Edges implements the Binner interface.
+func (e BinEdges) Edges(XYer) ([]float64, error) {
+	if err := checkEdges(e); err != nil {
+		return nil, err
+	}
+	return append([]float64(nil), e...), nil
+}
+
+// LogBins is a Binner that returns the given number
+// of bins spanning the range of the samples, whose
+// widths are equal on a logarithmic scale.  The
+// samples must be positive.  A decade centered on
+// the samples is used if they all have the same
+// value.
+type LogBins int
+
+// Edges implements the Binner interface.
+func (n LogBins) Edges(xys XYer) ([]float64, error) {
+	if n <= 0 {
+		return nil, errors.New("Non-positive number of bins")
+	}
+	s, err := newSamples(xys)
+	if err != nil {
+		return nil, err
+	}
+	if s.min() <= 0 {
+		return nil, errors.New("Non-positive value for logarithmic bins")
+	}
+	lo, hi := math.Log(s.min()), math.Log(s.max())
+	if lo == hi {
+		lo -= math.Ln10 / 2
+		hi += math.Ln10 / 2
+	}
+	e := make([]float64, n+1)
+	for i := range e {
+		e[i] = math.Exp(lo + float64(i)*(hi-lo)/float64(n))
+	}
+	if s.min() < s.max() {
+		// Make the outer edges exact.
+		e[0], e[n] = s.min(), s.max()
+	}
+	return e, nil
+}
+
+// SturgesBins is a Binner that returns ⌈log₂ n⌉+1
+// bins of equal width spanning the range of the
+// samples, where n is the total weight of the
+// samples.  The rule assumes that the samples are
+// approximately normally distributed.
+type SturgesBins struct{}
+
+// Edges implements the Binner interface.
+func (SturgesBins) Edges(xys XYer) ([]float64, error) {
+	s, err := newSamples(xys)
+	if err != nil {
+		return nil, err
+	}
+	return s.equalEdges(sturges(s.total())), nil
+}
+
+// ScottBins is a Binner that returns bins of equal
+// width spanning the range of the samples with
+// the width 3.49σn^(-1/3), where σ is the standard
+// deviation and n is the total weight of the
+// samples.  Sturges' rule is used if the standard
+// deviation is zero, and there are never more bins
+// than the total weight of the samples, so that an
+// outlier cannot make the number of bins huge.
+type ScottBins struct{}
+
+// Edges implements the Binner interface.
+func (ScottBins) Edges(xys XYer) ([]float64, error) {
+	s, err := newSamples(xys)
+	if err != nil {
+		return nil, err
+	}
+	return s.widthEdges(3.49 * s.stdDev() * math.Cbrt(1/s.total())), nil
+}
+
+// FreedmanDiaconisBins is a Binner that returns bins
+// of equal width spanning the range of the samples
+// with the width 2·IQR·n^(-1/3), where IQR is the
+// interquartile range and n is the total weight of
+// the samples.  The rule is less sensitive to
+// outliers than Scott's rule, although an outlier
+// can still make the bins narrow compared with the
+// range, so there are never more bins than the total
+// weight of the samples.  Sturges' rule is used if
+// the interquartile range is zero.
+type FreedmanDiaconisBins struct{}
+
+// Edges implements the Binner interface.
+func (FreedmanDiaconisBins) Edges(xys XYer) ([]float64, error) {
+	s, err := newSamples(xys)
+	if err != nil {
+		return nil, err
+	}
+	quantile := quantileFunc(s.xs, s.ws, NearestRank)
+	iqr := quantile(0.75) - quantile(0.25)
+	return s.widthEdges(2 * iqr * math.Cbrt(1/s.total())), nil
+}
+
+// DoaneBins is a Binner that returns bins of equal
+// width spanning the range of the samples, using
+// Doane's modification of Sturges' rule which adds
+// bins for skewed samples.
+type DoaneBins struct{}
+
+// Edges implements the Binner interface.
+func (DoaneBins) Edges(xys XYer) ([]float64, error) {
+	s, err := newSamples(xys)
+	if err != nil {
+		return nil, err
+	}
+	n := s.total()
+	if n <= 2 {
+		return s.equalEdges(sturges(n)), nil
+	}
+	sg := math.Sqrt(6 * (n - 2) / ((n + 1) * (n + 3)))
+	k := 1 + math.Log2(n) + math.Log2(1+math.Abs(s.skewness())/sg)
+	if math.IsNaN(k) {
+		return s.equalEdges(sturges(n)), nil
+	}
+	return s.equalEdges(int(math.Ceil(k))), nil
+}
+
+// sturges returns the number of bins given by
+// Sturges' rule for the total weight n.
+func sturges(n float64) int {
+	if n < 1 {
+		return 1
+	}
+	return int(math.Ceil(math.Log2(n))) + 1
+}
+
+// samples holds weighted samples sorted by value.
+type samples struct {
+	xs, ws []float64
+}
+
+// newSamples returns the samples of xys, or an
+// error if there are no samples or if one of the
+// values or weights is NaN or infinite.
+func newSamples(xys XYer) (*samples, error) {
+	n := xys.Len()
+	if n == 0 {
+		return nil, ErrNoData
+	}
+	s := &samples{xs: make([]float64, n), ws: make([]float64, n)}
+	for i := range s.xs {
+		s.xs[i], s.ws[i] = xys.XY(i)
+		if err := CheckFloats(s.xs[i], s.ws[i]); err != nil {
+			return nil, err
+		}
+	}
+	sort.Sort(s)
+	return s, nil
+}
+
+func (s *samples) Len() int           { return len(s.xs) }
+func (s *samples) Less(i, j int) bool { return s.xs[i] < s.xs[j] }
+func (s *samples) Swap(i, j int) {
+	s.xs[i], s.xs[j] = s.xs[j], s.xs[i]
+	s.ws[i], s.ws[j] = s.ws[j], s.ws[i]
+}
+
+func (s *samples) min() float64 { return s.xs[0] }
+func (s *samples) max() float64 { return s.xs[len(s.xs)-1] }
+
+// total returns the total weight of the samples.
+func (s *samples) total() float64 {
+	var n float64
+	for _, w := range s.ws {
+		n += w
+	}
+	return n
+}
+
+// mean returns the weighted mean of the samples.
+func (s *samples) mean() float64 {
+	var m float64
+	for i, x := range s.xs {
+		m += s.ws[i] * x
+	}
+	return m / s.total()
+}
+
+// moment returns the weighted kth central
+// moment of the samples.
+func (s *samples) moment(k float64) float64 {
+	m := s.mean()
+	var sum float64
+	for i, x := range s.xs {
+		sum += s.ws[i] * math.Pow(x-m, k)
+	}
+	return sum / s.total()
+}
+
+// stdDev returns the weighted standard deviation
+// of the samples, treating the weights as
+// frequencies.
+func (s *samples) stdDev() float64 {
+	n := s.total()
+	if n <= 1 {
+		return 0
+	}
+	return math.Sqrt(s.moment(2) * n / (n - 1))
+}
+
+// skewness returns the weighted sample
+// skewness of the samples.
+func (s *samples) skewness() float64 {
+	return s.moment(3) / math.Pow(s.moment(2), 1.5)
+}
+
+// equalEdges returns the edges of n bins of equal
+// width spanning the range of the samples.  A range
+// of unit width centered on the samples is used if
+// they all have the same value.
+func (s *samples) equalEdges(n int) []float64 {
+	if n < 1 {
+		n = 1
+	}
+	min, max := s.min(), s.max()
+	if min == max {
+		min -= 0.5
+		max += 0.5
+	}
+	w := (max - min) / float64(n)
+	e := make([]float64, n+1)
+	for i := range e {
+		e[i] = min + float64(i)*w
+	}
+	e[n] = max
+	return e
+}
+
+// widthEdges returns the edges of bins of the given
+// width spanning the range of the samples, with wider
+// bins if there would otherwise be more bins than the
+// total weight of the samples.  Sturges' rule is used
+// if the width is not positive.
+func (s *samples) widthEdges(w float64) []float64 {
+	n := s.total()
+	if !(w > 0) {
+		return s.equalEdges(sturges(n))
+	}
+	return s.equalEdges(int(math.Min(math.Ceil((s.max()-s.min())/w), math.Ceil(n))))
+}
+
+// checkEdges returns an error if e are not
+// the edges of at least one bin.
+func checkEdges(e []float64) error {
+	if len(e) < 2 {
+		return errors.New("Fewer than two bin edges")
+	}
+	for i, x := range e {
+		if err := CheckFloats(x); err != nil {
+			return err
+		}
+		if i > 0 && x <= e[i-1] {
+			return errors.New("Bin edges are not increasing")
+		}
+	}
+	return nil
+}
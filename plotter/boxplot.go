@@ -6,6 +6,7 @@ package plotter
 
 import (
 	"errors"
+	"image/color"
 	"math"
 	"sort"
 
@@ -21,6 +22,10 @@ type fiveStatPlot struct {
 	// create this box plot.
 	Values
 
+	// Weights is a copy of the weights of the values,
+	// or nil if the values are not weighted.
+	Weights Values
+
 	// Location is the location of the box along its axis.
 	Location float64
 
@@ -40,6 +45,13 @@ type fiveStatPlot struct {
 	// Min and Max are the extreme values of the data.
 	Min, Max float64
 
+	// NotchLow and NotchHigh are the bounds of the
+	// approximate 95% confidence interval of the
+	// median, median ± 1.57·IQR/√n, that is shown
+	// by the notches of a notched plot.  For weighted
+	// values n is the effective sample size.
+	NotchLow, NotchHigh float64
+
 	// Outside are the indices of Vs for the outside points.
 	Outside []int
 }
@@ -73,6 +85,17 @@ type BoxPlot struct {
 	// WhiskerStyle is the line style used to draw the
 	// whiskers.
 	WhiskerStyle draw.LineStyle
+
+	// Notched specifies whether the box is notched
+	// at NotchLow and NotchHigh to show the
+	// confidence interval of the median.  Where the
+	// notches of two boxes do not overlap, their
+	// medians differ significantly.
+	Notched bool
+
+	// Points is an overlay of the values drawn
+	// over or beside the box.
+	Points DataPoints
 }
 
 // NewBoxPlot returns a new BoxPlot that represents
@@ -81,22 +104,27 @@ type BoxPlot struct {
 // plots is ``Exploratory Data Analysis.''
 //
 // An error is returned if the boxplot is created with
-// no values.
+// no values.  Missing values, which are NaN, are
+// ignored along with their weights.  Values can be
+// checked for missing values first with
+// CopyValuesMissing and MissingError.
 //
 // The fence values are 1.5x the interquartile before
 // the first quartile and after the third quartile.  Any
 // value that is outside of the fences are drawn as
 // Outside points.  The adjacent values (to which the
 // whiskers stretch) are the minimum and maximum
-// values that are not outside the fences.
-func NewBoxPlot(w vg.Length, loc float64, values Valuer) (*BoxPlot, error) {
+// values that are not outside the fences.  The
+// computation of the statistics may be changed by
+// the options.
+func NewBoxPlot(w vg.Length, loc float64, values Valuer, opts ...StatOption) (*BoxPlot, error) {
 	if w < 0 {
 		return nil, errors.New("Negative boxplot width")
 	}
 
 	b := new(BoxPlot)
 	var err error
-	if b.fiveStatPlot, err = newFiveStat(w, loc, values); err != nil {
+	if b.fiveStatPlot, err = newFiveStat(w, loc, values, opts); err != nil {
 		return nil, err
 	}
 
@@ -110,6 +138,10 @@ func NewBoxPlot(w vg.Length, loc float64, values Valuer) (*BoxPlot, error) {
 		Width:  vg.Points(0.5),
 		Dashes: []vg.Length{vg.Points(4), vg.Points(2)},
 	}
+	b.Points = DataPoints{
+		Spread:     w / 4,
+		GlyphStyle: draw.GlyphStyle{Color: color.Black, Radius: vg.Points(1.5), Shape: draw.CircleGlyph{}},
+	}
 
 	if len(b.Values) == 0 {
 		b.Width = 0
@@ -122,33 +154,75 @@ func NewBoxPlot(w vg.Length, loc float64, values Valuer) (*BoxPlot, error) {
 	return b, nil
 }
 
-func newFiveStat(w vg.Length, loc float64, values Valuer) (fiveStatPlot, error) {
+// dropMissing returns the values that are not
+// missing and their weights, if there are weights.
+func dropMissing(vs, ws Values) (Values, Values) {
+	var keptV, keptW Values
+	for i, v := range vs {
+		if math.IsNaN(v) {
+			continue
+		}
+		keptV = append(keptV, v)
+		if ws != nil {
+			keptW = append(keptW, ws[i])
+		}
+	}
+	return keptV, keptW
+}
+
+func newFiveStat(w vg.Length, loc float64, values Valuer, opts []StatOption) (fiveStatPlot, error) {
+	cfg := statConfig{whiskers: IQRWhiskers(1.5)}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	var b fiveStatPlot
 	b.Location = loc
 
 	var err error
-	if b.Values, err = CopyValues(values); err != nil {
+	if b.Values, err = CopyValuesMissing(values, MissingBreak); err != nil {
 		return fiveStatPlot{}, err
 	}
+	if cfg.weights != nil {
+		if b.Weights, err = checkWeights(cfg.weights, len(b.Values)); err != nil {
+			return fiveStatPlot{}, err
+		}
+	}
+	if b.Values, b.Weights = dropMissing(b.Values, b.Weights); len(b.Values) == 0 {
+		return fiveStatPlot{}, ErrNoData
+	}
 
 	sorted := make(Values, len(b.Values))
 	copy(sorted, b.Values)
 	sort.Float64s(sorted)
 
-	if len(sorted) == 1 {
+	def := cfg.quantile
+	if def == TukeyHinges {
+		def = HazenQuantiles
+	}
+	quantile := quantileFunc(b.Values, b.Weights, def)
+	switch {
+	case len(sorted) == 1:
 		b.Median = sorted[0]
 		b.Quartile1 = sorted[0]
 		b.Quartile3 = sorted[0]
-	} else {
+	case cfg.quantile == TukeyHinges && b.Weights == nil:
 		b.Median = median(sorted)
 		b.Quartile1 = median(sorted[:len(sorted)/2])
 		b.Quartile3 = median(sorted[len(sorted)/2:])
+	default:
+		b.Median = quantile(0.5)
+		b.Quartile1 = quantile(0.25)
+		b.Quartile3 = quantile(0.75)
 	}
 	b.Min = sorted[0]
 	b.Max = sorted[len(sorted)-1]
 
-	low := b.Quartile1 - 1.5*(b.Quartile3-b.Quartile1)
-	high := b.Quartile3 + 1.5*(b.Quartile3-b.Quartile1)
+	notch := 1.57 * (b.Quartile3 - b.Quartile1) / math.Sqrt(b.effectiveSize())
+	b.NotchLow = b.Median - notch
+	b.NotchHigh = b.Median + notch
+
+	low, high := cfg.whiskers.Fences(b.Quartile1, b.Quartile3, quantile)
 	b.AdjLow = math.Inf(1)
 	b.AdjHigh = math.Inf(-1)
 	for i, v := range b.Values {
@@ -167,6 +241,110 @@ func newFiveStat(w vg.Length, loc float64, values Valuer) (fiveStatPlot, error)
 	return b, nil
 }
 
+// effectiveSize returns the number of values, or
+// for weighted values the Kish effective sample
+// size, (Σw)²/Σw².
+func (b *fiveStatPlot) effectiveSize() float64 {
+	if b.Weights == nil {
+		return float64(len(b.Values))
+	}
+	var sum, sumSq float64
+	for _, w := range b.Weights {
+		sum += w
+		sumSq += w * w
+	}
+	return sum * sum / sumSq
+}
+
+// outline returns the outline of the box and the
+// median line of a vertical box plot centered at x,
+// notched if b.Notched is true.  The notches are
+// limited to the box.
+func (b *BoxPlot) outline(x, q1, med, q3, nLow, nHigh vg.Length) (box, medLine []draw.Point) {
+	left, right := x-b.Width/2, x+b.Width/2
+	if !b.Notched {
+		box = []draw.Point{
+			{left, q1},
+			{left, q3},
+			{right, q3},
+			{right, q1},
+			{left - b.BoxStyle.Width/2, q1},
+		}
+		return box, []draw.Point{{left, med}, {right, med}}
+	}
+	nLow = clampBetween(nLow, q1, med)
+	nHigh = clampBetween(nHigh, med, q3)
+	indent := b.Width / 4
+	box = []draw.Point{
+		{left, q1},
+		{left, nLow},
+		{left + indent, med},
+		{left, nHigh},
+		{left, q3},
+		{right, q3},
+		{right, nHigh},
+		{right - indent, med},
+		{right, nLow},
+		{right, q1},
+		{left - b.BoxStyle.Width/2, q1},
+	}
+	return box, []draw.Point{{left + indent, med}, {right - indent, med}}
+}
+
+// clampBetween returns v limited to the
+// interval between a and b, in either order.
+func clampBetween(v, a, b vg.Length) vg.Length {
+	if a > b {
+		a, b = b, a
+	}
+	switch {
+	case v < a:
+		return a
+	case v > b:
+		return b
+	}
+	return v
+}
+
+// transpose returns the points with their
+// x and y coordinates exchanged.
+func transpose(pts []draw.Point) []draw.Point {
+	t := make([]draw.Point, len(pts))
+	for i, p := range pts {
+		t[i] = draw.Point{p.Y, p.X}
+	}
+	return t
+}
+
+// ScaleBoxWidths sets the widths of the box plots in
+// proportion to the square roots of their sample sizes,
+// following McGill, Tukey and Larsen (1978), so that
+// the box of the largest sample has the width max.
+// The cap widths are scaled with the widths.  The
+// sample size of weighted values is their total weight.
+func ScaleBoxWidths(max vg.Length, boxes ...*BoxPlot) {
+	sizes := make([]float64, len(boxes))
+	var largest float64
+	for i, b := range boxes {
+		sizes[i] = float64(len(b.Values))
+		if b.Weights != nil {
+			sizes[i] = 0
+			for _, w := range b.Weights {
+				sizes[i] += w
+			}
+		}
+		largest = math.Max(largest, sizes[i])
+	}
+	for i, b := range boxes {
+		capRatio := 0.75
+		if b.Width != 0 {
+			capRatio = float64(b.CapWidth / b.Width)
+		}
+		b.Width = max * vg.Length(math.Sqrt(sizes[i]/largest))
+		b.CapWidth = b.Width * vg.Length(capRatio)
+	}
+}
+
 // median returns the median value from a
 // sorted Values.
 func median(vs Values) float64 {
@@ -195,20 +373,9 @@ func (b *BoxPlot) Plot(c draw.Canvas, plt *plot.Plot) {
 	aLow := trY(b.AdjLow)
 	aHigh := trY(b.AdjHigh)
 
-	box := c.ClipLinesY([]draw.Point{
-		{x - b.Width/2, q1},
-		{x - b.Width/2, q3},
-		{x + b.Width/2, q3},
-		{x + b.Width/2, q1},
-		{x - b.Width/2 - b.BoxStyle.Width/2, q1},
-	})
-	c.StrokeLines(b.BoxStyle, box...)
-
-	medLine := c.ClipLinesY([]draw.Point{
-		{x - b.Width/2, med},
-		{x + b.Width/2, med},
-	})
-	c.StrokeLines(b.MedianStyle, medLine...)
+	box, medLine := b.outline(x, q1, med, q3, trY(b.NotchLow), trY(b.NotchHigh))
+	c.StrokeLines(b.BoxStyle, c.ClipLinesY(box)...)
+	c.StrokeLines(b.MedianStyle, c.ClipLinesY(medLine)...)
 
 	cap := b.CapWidth / 2
 	whisks := c.ClipLinesY([]draw.Point{{x, q3}, {x, aHigh}},
@@ -217,6 +384,12 @@ func (b *BoxPlot) Plot(c draw.Canvas, plt *plot.Plot) {
 		[]draw.Point{{x - cap, aLow}, {x + cap, aLow}})
 	c.StrokeLines(b.WhiskerStyle, whisks...)
 
+	ys := make([]vg.Length, len(b.Values))
+	for i, v := range b.Values {
+		ys[i] = trY(v)
+	}
+	b.Points.plot(&c, ys, x, b.Width/2+b.Points.Radius*2, false)
+
 	for _, out := range b.Outside {
 		y := trY(b.Value(out))
 		if c.ContainsY(y) {
@@ -233,7 +406,8 @@ func (b *BoxPlot) DataRange() (float64, float64, float64, float64) {
 }
 
 // GlyphBoxes returns a slice of GlyphBoxes for the
-// points and for the median line of the boxplot,
+// points, for the median line of the boxplot and
+// for the data points drawn beside it, if any,
 // implementing the plot.GlyphBoxer interface
 func (b *BoxPlot) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
 	bs := make([]plot.GlyphBox, len(b.Outside)+1)
@@ -248,6 +422,13 @@ func (b *BoxPlot) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
 		Min: draw.Point{X: b.Offset - (b.Width/2 + b.BoxStyle.Width/2)},
 		Max: draw.Point{X: b.Offset + (b.Width/2 + b.BoxStyle.Width/2)},
 	}
+	if min, max, ok := b.Points.besideExtent(b.Width/2 + b.Points.Radius*2); ok {
+		bs = append(bs, plot.GlyphBox{
+			X:         plt.X.Norm(b.Location),
+			Y:         plt.Y.Norm(b.Median),
+			Rectangle: draw.Rectangle{Min: draw.Point{X: b.Offset + min}, Max: draw.Point{X: b.Offset + max}},
+		})
+	}
 	return bs
 }
 
@@ -294,8 +475,8 @@ type HorizBoxPlot struct{ *BoxPlot }
 // MakeHorizBoxPlot returns a HorizBoxPlot,
 // plotting the values in a horizontal box plot
 // centered along a fixed location of the y axis.
-func MakeHorizBoxPlot(w vg.Length, loc float64, vs Valuer) (HorizBoxPlot, error) {
-	b, err := NewBoxPlot(w, loc, vs)
+func MakeHorizBoxPlot(w vg.Length, loc float64, vs Valuer, opts ...StatOption) (HorizBoxPlot, error) {
+	b, err := NewBoxPlot(w, loc, vs, opts...)
 	return HorizBoxPlot{b}, err
 }
 
@@ -313,20 +494,9 @@ func (b HorizBoxPlot) Plot(c draw.Canvas, plt *plot.Plot) {
 	aLow := trX(b.AdjLow)
 	aHigh := trX(b.AdjHigh)
 
-	box := c.ClipLinesX([]draw.Point{
-		{q1, y - b.Width/2},
-		{q3, y - b.Width/2},
-		{q3, y + b.Width/2},
-		{q1, y + b.Width/2},
-		{q1, y - b.Width/2 - b.BoxStyle.Width/2},
-	})
-	c.StrokeLines(b.BoxStyle, box...)
-
-	medLine := c.ClipLinesX([]draw.Point{
-		{med, y - b.Width/2},
-		{med, y + b.Width/2},
-	})
-	c.StrokeLines(b.MedianStyle, medLine...)
+	box, medLine := b.outline(y, q1, med, q3, trX(b.NotchLow), trX(b.NotchHigh))
+	c.StrokeLines(b.BoxStyle, c.ClipLinesX(transpose(box))...)
+	c.StrokeLines(b.MedianStyle, c.ClipLinesX(transpose(medLine))...)
 
 	cap := b.CapWidth / 2
 	whisks := c.ClipLinesX([]draw.Point{{q3, y}, {aHigh, y}},
@@ -335,6 +505,12 @@ func (b HorizBoxPlot) Plot(c draw.Canvas, plt *plot.Plot) {
 		[]draw.Point{{aLow, y - cap}, {aLow, y + cap}})
 	c.StrokeLines(b.WhiskerStyle, whisks...)
 
+	xs := make([]vg.Length, len(b.Values))
+	for i, v := range b.Values {
+		xs[i] = trX(v)
+	}
+	b.Points.plot(&c, xs, y, b.Width/2+b.Points.Radius*2, true)
+
 	for _, out := range b.Outside {
 		x := trX(b.Value(out))
 		if c.ContainsX(x) {
@@ -351,7 +527,8 @@ func (b HorizBoxPlot) DataRange() (float64, float64, float64, float64) {
 }
 
 // GlyphBoxes returns a slice of GlyphBoxes for the
-// points and for the median line of the boxplot,
+// points, for the median line of the boxplot and
+// for the data points drawn beside it, if any,
 // implementing the plot.GlyphBoxer interface
 func (b HorizBoxPlot) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
 	bs := make([]plot.GlyphBox, len(b.Outside)+1)
@@ -366,6 +543,13 @@ func (b HorizBoxPlot) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
 		Min: draw.Point{Y: b.Offset - (b.Width/2 + b.BoxStyle.Width/2)},
 		Max: draw.Point{Y: b.Offset + (b.Width/2 + b.BoxStyle.Width/2)},
 	}
+	if min, max, ok := b.Points.besideExtent(b.Width/2 + b.Points.Radius*2); ok {
+		bs = append(bs, plot.GlyphBox{
+			X:         plt.X.Norm(b.Median),
+			Y:         plt.Y.Norm(b.Location),
+			Rectangle: draw.Rectangle{Min: draw.Point{Y: b.Offset + min}, Max: draw.Point{Y: b.Offset + max}},
+		})
+	}
 	return bs
 }
 
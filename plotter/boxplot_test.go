@@ -0,0 +1,238 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/recorder"
+)
+
+func TestQuantileDefs(t *testing.T) {
+	for _, test := range []struct {
+		def  QuantileDef
+		want float64
+	}{
+		{def: TukeyHinges, want: 1.5},
+		{def: NearestRank, want: 1},
+		{def: LinearQuantiles, want: 1.75},
+		{def: HazenQuantiles, want: 1.5},
+		{def: MedianUnbiasedQuantiles, want: 17.0 / 12},
+	} {
+		b, err := NewBoxPlot(10, 0, Values{4, 2, 1, 3}, WithQuantiles(test.def))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if math.Abs(b.Quartile1-test.want) > 1e-12 {
+			t.Errorf("unexpected first quartile for definition %d: got:%v want:%v", test.def, b.Quartile1, test.want)
+		}
+	}
+}
+
+func TestWeightedBoxPlot(t *testing.T) {
+	weighted, err := NewBoxPlot(10, 0, Values{3, 1, 2}, WithWeights(Values{1, 1, 2}), WithQuantiles(NearestRank))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repeated, err := NewBoxPlot(10, 0, Values{3, 1, 2, 2}, WithQuantiles(NearestRank))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if weighted.Median != repeated.Median || weighted.Quartile1 != repeated.Quartile1 || weighted.Quartile3 != repeated.Quartile3 {
+		t.Errorf("weighted quartiles differ from repeated values: got:%v,%v,%v want:%v,%v,%v",
+			weighted.Quartile1, weighted.Median, weighted.Quartile3,
+			repeated.Quartile1, repeated.Median, repeated.Quartile3)
+	}
+
+	for _, test := range []struct {
+		name string
+		ws   Values
+	}{
+		{name: "too few weights", ws: Values{1, 1}},
+		{name: "negative weight", ws: Values{1, -1, 1}},
+		{name: "zero weights", ws: Values{0, 0, 0}},
+	} {
+		if _, err := NewBoxPlot(10, 0, Values{1, 2, 3}, WithWeights(test.ws)); err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
+
+func TestWhiskerRules(t *testing.T) {
+	vs := Values{-50, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 50}
+	for _, test := range []struct {
+		rule    WhiskerRule
+		low     float64
+		high    float64
+		outside []int
+	}{
+		{rule: nil, low: 1, high: 10, outside: []int{0, 11}},
+		{rule: IQRWhiskers(10), low: -50, high: 50, outside: nil},
+		{rule: PercentileWhiskers{Low: 0.2, High: 0.8}, low: 2, high: 9, outside: []int{0, 1, 10, 11}},
+		{rule: MinMaxWhiskers{}, low: -50, high: 50, outside: nil},
+	} {
+		var opts []StatOption
+		if test.rule != nil {
+			opts = append(opts, WithWhiskers(test.rule))
+		}
+		b, err := NewQuartPlot(0, vs, opts...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if b.AdjLow != test.low || b.AdjHigh != test.high {
+			t.Errorf("unexpected adjacent values for %#v: got:[%v,%v] want:[%v,%v]", test.rule, b.AdjLow, b.AdjHigh, test.low, test.high)
+		}
+		if !reflect.DeepEqual(b.Outside, test.outside) {
+			t.Errorf("unexpected outside points for %#v: got:%v want:%v", test.rule, b.Outside, test.outside)
+		}
+	}
+}
+
+func TestNotches(t *testing.T) {
+	vs := make(Values, 100)
+	for i := range vs {
+		vs[i] = float64(i)
+	}
+	b, err := NewBoxPlot(20, 0, vs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	notch := 1.57 * 50 / 10
+	if math.Abs(b.NotchLow-(49.5-notch)) > 1e-12 || math.Abs(b.NotchHigh-(49.5+notch)) > 1e-12 {
+		t.Errorf("unexpected notches: got:[%v,%v] want:[%v,%v]", b.NotchLow, b.NotchHigh, 49.5-notch, 49.5+notch)
+	}
+
+	b.Notched = true
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.Add(b)
+	r := recorder.New(72)
+	c := p.DataCanvas(draw.NewCanvas(r, 100, 100))
+	r.Reset()
+	b.Plot(c, p)
+	var box *recorder.Stroke
+	for _, a := range r.Actions {
+		if s, ok := a.(*recorder.Stroke); ok {
+			box = s
+			break
+		}
+	}
+	if box == nil {
+		t.Fatal("notched box was not drawn")
+	}
+	if len(box.Path) != 11 {
+		t.Errorf("unexpected number of points in notched box: got:%d want:11", len(box.Path))
+	}
+}
+
+func TestScaleBoxWidths(t *testing.T) {
+	small, err := NewBoxPlot(10, 0, Values{1, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	large, err := NewBoxPlot(10, 1, Values{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ScaleBoxWidths(20, small, large)
+	if small.Width != 10 || large.Width != 20 {
+		t.Errorf("unexpected widths: got:%v,%v want:10,20", small.Width, large.Width)
+	}
+	if small.CapWidth != 7.5 {
+		t.Errorf("unexpected cap width: got:%v want:7.5", small.CapWidth)
+	}
+}
+
+func TestSwarm(t *testing.T) {
+	along := []vg.Length{0, 1, 2, 3, 4, 20, 2, 2}
+	const r = 2
+	for _, oneSided := range []bool{false, true} {
+		offs := swarm(along, r, oneSided)
+		if offs[5] != 0 {
+			t.Errorf("unexpected offset of isolated point: got:%v want:0", offs[5])
+		}
+		for i := range along {
+			if oneSided && offs[i] < 0 {
+				t.Errorf("negative offset %v of one-sided swarm", offs[i])
+			}
+			for j := i + 1; j < len(along); j++ {
+				dx := float64(offs[i] - offs[j])
+				dy := float64(along[i] - along[j])
+				if math.Hypot(dx, dy) < 2*r-1e-9 {
+					t.Errorf("points %d and %d overlap with oneSided=%t", i, j, oneSided)
+				}
+			}
+		}
+	}
+}
+
+func TestBesideGlyphBoxes(t *testing.T) {
+	vs := Values{1, 2, 3, 4, 5}
+	box, err := NewBoxPlot(20, 0, vs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hbox, err := MakeHorizBoxPlot(20, 0, vs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	quart, err := NewQuartPlot(0, vs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hquart, err := MakeHorizQuartPlot(0, vs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, test := range []struct {
+		name   string
+		points *DataPoints
+		boxer  plot.GlyphBoxer
+		horiz  bool
+		beside vg.Length
+	}{
+		{name: "box plot", points: &box.Points, boxer: box, beside: box.Width/2 + box.Points.Radius*2},
+		{name: "horizontal box plot", points: &hbox.Points, boxer: hbox, horiz: true, beside: hbox.Width/2 + hbox.Points.Radius*2},
+		{name: "quartile plot", points: &quart.Points, boxer: quart, beside: quart.MedianStyle.Radius + quart.Points.Radius*2},
+		{name: "horizontal quartile plot", points: &hquart.Points, boxer: hquart, horiz: true, beside: hquart.MedianStyle.Radius + hquart.Points.Radius*2},
+	} {
+		p, err := plot.New()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		extent := func() vg.Length {
+			var max vg.Length
+			for _, b := range test.boxer.GlyphBoxes(p) {
+				m := b.Max.X
+				if test.horiz {
+					m = b.Max.Y
+				}
+				if m > max {
+					max = m
+				}
+			}
+			return max
+		}
+		plain := extent()
+		test.points.Layout = SwarmPoints
+		test.points.Beside = true
+		test.points.Spread = 10
+		want := test.beside + test.points.Spread + test.points.Radius
+		if got := extent(); got != want || got <= plain {
+			t.Errorf("unexpected extent of %s with points beside: got:%v want:%v", test.name, got, want)
+		}
+		test.points.Beside = false
+		if got := extent(); got != plain {
+			t.Errorf("unexpected extent of %s with points over it: got:%v want:%v", test.name, got, plain)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// StatOption is an option for the computation of the
+// statistics of box plots and quartile plots.
+type StatOption func(*statConfig)
+
+// statConfig holds the options for computing
+// the statistics of a fiveStatPlot.
+type statConfig struct {
+	weights  Valuer
+	quantile QuantileDef
+	whiskers WhiskerRule
+}
+
+// WithWeights returns a StatOption that weights
+// the values by the corresponding weights, which
+// must not be negative.  The number of weights
+// must match the number of values.
+func WithWeights(ws Valuer) StatOption {
+	return func(c *statConfig) { c.weights = ws }
+}
+
+// WithQuantiles returns a StatOption that computes
+// the median and quartiles with the quantile
+// definition d.
+func WithQuantiles(d QuantileDef) StatOption {
+	return func(c *statConfig) { c.quantile = d }
+}
+
+// WithWhiskers returns a StatOption that places
+// the fences of the plot by the rule r.
+func WithWhiskers(r WhiskerRule) StatOption {
+	return func(c *statConfig) { c.whiskers = r }
+}
+
+// QuantileDef is a definition of sample quantiles.
+// The definitions other than TukeyHinges are
+// those of Hyndman and Fan (1996), generalized
+// to weighted values.
+type QuantileDef int
+
+const (
+	// TukeyHinges gives the median and the medians
+	// of the lower and upper halves of the sorted
+	// values, as used by Tukey's schematic plots.
+	// It is the default.  Other quantiles, and all
+	// quantiles of weighted values, are given by
+	// HazenQuantiles.
+	TukeyHinges QuantileDef = iota
+
+	// NearestRank gives the smallest value at or
+	// below which the requested fraction of the
+	// values lies, without interpolation.
+	NearestRank
+
+	// LinearQuantiles interpolates linearly between
+	// the sorted values placed evenly from zero to
+	// one, as is the default in R and NumPy.
+	LinearQuantiles
+
+	// HazenQuantiles interpolates linearly between
+	// the sorted values placed at the midpoints of
+	// their shares of the cumulative weight.
+	HazenQuantiles
+
+	// MedianUnbiasedQuantiles interpolates linearly
+	// between the sorted values placed so that the
+	// quantiles are approximately median-unbiased
+	// regardless of the distribution.
+	MedianUnbiasedQuantiles
+)
+
+// quantileFunc returns the quantile function of the
+// values weighted by weights, or by one if weights
+// is nil, with the definition d.  It gives the
+// quantiles of box and quartile plots, probability
+// plots and bin rules.
+func quantileFunc(vals, weights Values, d QuantileDef) func(p float64) float64 {
+	n := len(vals)
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Sort(byValue{vals, idx})
+
+	// Scale the weights to sum to n so that the
+	// weighted definitions match the unweighted.
+	w := make([]float64, n)
+	var total float64
+	for i, j := range idx {
+		w[i] = 1
+		if weights != nil {
+			w[i] = weights[j]
+		}
+		total += w[i]
+	}
+	for i := range w {
+		w[i] *= float64(n) / total
+	}
+
+	x := func(i int) float64 { return vals[idx[i]] }
+	if n == 1 {
+		return func(float64) float64 { return x(0) }
+	}
+
+	if d == NearestRank {
+		return func(p float64) float64 {
+			var cum float64
+			for i := range w {
+				cum += w[i]
+				if cum >= p*float64(n) {
+					return x(i)
+				}
+			}
+			return x(n - 1)
+		}
+	}
+
+	var a float64
+	switch d {
+	case LinearQuantiles:
+		a = 1
+	case MedianUnbiasedQuantiles:
+		a = 1.0 / 3
+	default:
+		a = 0.5
+	}
+	pos := make([]float64, n)
+	var cum float64
+	for i := range pos {
+		cum += w[i]
+		pos[i] = (cum - a*w[i]) / (float64(n) + 1 - 2*a)
+	}
+	return func(p float64) float64 {
+		i := sort.SearchFloat64s(pos, p)
+		switch {
+		case i == 0:
+			return x(0)
+		case i == n:
+			return x(n - 1)
+		case pos[i] == pos[i-1]:
+			return x(i)
+		}
+		f := (p - pos[i-1]) / (pos[i] - pos[i-1])
+		return x(i-1) + f*(x(i)-x(i-1))
+	}
+}
+
+// byValue sorts indices by the values they index.
+type byValue struct {
+	vals Values
+	idx  []int
+}
+
+func (b byValue) Len() int           { return len(b.idx) }
+func (b byValue) Less(i, j int) bool { return b.vals[b.idx[i]] < b.vals[b.idx[j]] }
+func (b byValue) Swap(i, j int)      { b.idx[i], b.idx[j] = b.idx[j], b.idx[i] }
+
+// WhiskerRule places the fences of box plots and
+// quartile plots.  Values outside of the fences are
+// drawn as outside points, and the whiskers reach
+// the most extreme values within the fences.
+type WhiskerRule interface {
+	// Fences returns the low and high fences for
+	// values with the first and third quartiles
+	// q1 and q3 and the quantile function quantile.
+	Fences(q1, q3 float64, quantile func(p float64) float64) (low, high float64)
+}
+
+// IQRWhiskers is a WhiskerRule that places the fences
+// at the multiple of the interquartile range before
+// the first quartile and after the third quartile.
+// The default rule, Tukey's, is IQRWhiskers(1.5).
+type IQRWhiskers float64
+
+// Fences implements the WhiskerRule interface.
+func (k IQRWhiskers) Fences(q1, q3 float64, _ func(float64) float64) (low, high float64) {
+	return q1 - float64(k)*(q3-q1), q3 + float64(k)*(q3-q1)
+}
+
+// PercentileWhiskers is a WhiskerRule that places
+// the fences at the quantiles Low and High, such as
+// 0.05 and 0.95.
+type PercentileWhiskers struct {
+	Low, High float64
+}
+
+// Fences implements the WhiskerRule interface.
+func (r PercentileWhiskers) Fences(_, _ float64, quantile func(float64) float64) (low, high float64) {
+	return quantile(r.Low), quantile(r.High)
+}
+
+// MinMaxWhiskers is a WhiskerRule whose whiskers
+// reach the extreme values, leaving no outside
+// points.
+type MinMaxWhiskers struct{}
+
+// Fences implements the WhiskerRule interface.
+func (MinMaxWhiskers) Fences(_, _ float64, _ func(float64) float64) (low, high float64) {
+	return math.Inf(-1), math.Inf(1)
+}
+
+// checkWeights returns a copy of the weights of
+// n values, or an error if there is not a weight
+// for each value or if a weight is negative.
+func checkWeights(ws Valuer, n int) (Values, error) {
+	if ws.Len() != n {
+		return nil, errors.New("Number of weights does not match the number of values")
+	}
+	cpy, err := CopyValues(ws)
+	if err != nil {
+		return nil, err
+	}
+	var total float64
+	for _, w := range cpy {
+		if w < 0 {
+			return nil, errors.New("Negative weight")
+		}
+		total += w
+	}
+	if total == 0 {
+		return nil, errors.New("Weights sum to zero")
+	}
+	return cpy, nil
+}
@@ -0,0 +1,122 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import "math"
+
+// Distribution is a continuous probability distribution,
+// used as the theoretical distribution of quantile-quantile
+// and probability-probability plots.
+type Distribution interface {
+	// CDF returns the probability that a value
+	// of the distribution is at most x.
+	CDF(x float64) float64
+
+	// Quantile returns the value at or below which
+	// the fraction p of the distribution lies.  It
+	// is the inverse of CDF.
+	Quantile(p float64) float64
+}
+
+// NormalDist is the normal distribution with
+// the mean Mu and the standard deviation Sigma.
+type NormalDist struct {
+	Mu, Sigma float64
+}
+
+// CDF implements the Distribution interface.
+func (d NormalDist) CDF(x float64) float64 {
+	return stdNormalCDF((x - d.Mu) / d.Sigma)
+}
+
+// Quantile implements the Distribution interface.
+func (d NormalDist) Quantile(p float64) float64 {
+	return d.Mu + d.Sigma*stdNormalQuantile(p)
+}
+
+// LogNormalDist is the distribution of a value whose
+// logarithm is normally distributed with the mean Mu
+// and the standard deviation Sigma.
+type LogNormalDist struct {
+	Mu, Sigma float64
+}
+
+// CDF implements the Distribution interface.
+func (d LogNormalDist) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return stdNormalCDF((math.Log(x) - d.Mu) / d.Sigma)
+}
+
+// Quantile implements the Distribution interface.
+func (d LogNormalDist) Quantile(p float64) float64 {
+	return math.Exp(d.Mu + d.Sigma*stdNormalQuantile(p))
+}
+
+// UniformDist is the uniform distribution
+// between Min and Max.
+type UniformDist struct {
+	Min, Max float64
+}
+
+// CDF implements the Distribution interface.
+func (d UniformDist) CDF(x float64) float64 {
+	return math.Max(0, math.Min(1, (x-d.Min)/(d.Max-d.Min)))
+}
+
+// Quantile implements the Distribution interface.
+func (d UniformDist) Quantile(p float64) float64 {
+	return d.Min + p*(d.Max-d.Min)
+}
+
+// ExponentialDist is the exponential
+// distribution with the rate Rate.
+type ExponentialDist struct {
+	Rate float64
+}
+
+// CDF implements the Distribution interface.
+func (d ExponentialDist) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return -math.Expm1(-d.Rate * x)
+}
+
+// Quantile implements the Distribution interface.
+func (d ExponentialDist) Quantile(p float64) float64 {
+	return -math.Log1p(-p) / d.Rate
+}
+
+// stdNormalCDF returns the cumulative distribution
+// function of the standard normal distribution.
+func stdNormalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+// stdNormalQuantile returns the quantile of the
+// standard normal distribution at p, found by
+// bisection of its cumulative distribution function.
+func stdNormalQuantile(p float64) float64 {
+	switch {
+	case p <= 0:
+		return math.Inf(-1)
+	case p >= 1:
+		return math.Inf(1)
+	}
+	lo, hi := -40.0, 40.0
+	for {
+		mid := (lo + hi) / 2
+		if mid == lo || mid == hi {
+			return mid
+		}
+		if stdNormalCDF(mid) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+)
+
+// DefaultGuideStyle is the default style of the
+// guide lines of a dot plot, a dotted gray hairline.
+var DefaultGuideStyle = draw.LineStyle{
+	Color:  color.Gray{Y: 0xb0},
+	Width:  vg.Points(0.5),
+	Dashes: []vg.Length{vg.Points(1), vg.Points(2)},
+}
+
+// DotPlot implements the Plotter interface, drawing
+// a Cleveland dot plot: a glyph at the value of each
+// category, on a guide line across the plot.  The
+// location of each value along the x axis is the
+// index of its value in the Valuer, suiting a plot
+// with nominal x values.
+type DotPlot struct {
+	// Values is a copy of the values of the plot.
+	Values
+
+	// Offset is added to the x location of each dot.
+	// When the Offset is zero, the dots are drawn
+	// at their x location.
+	Offset vg.Length
+
+	// XMin is the x location of the first dot.  XMin
+	// can be changed to move groups of dots along
+	// the x axis.
+	XMin float64
+
+	// GlyphStyle is the style of the dots.
+	draw.GlyphStyle
+
+	// GuideStyle is the style of the guide lines.
+	// If its Color is nil then no guide lines are
+	// drawn.
+	GuideStyle draw.LineStyle
+}
+
+// NewDotPlot returns a DotPlot with a dot for each
+// value that uses the default glyph and guide line
+// styles.
+func NewDotPlot(vs Valuer) (*DotPlot, error) {
+	values, err := CopyValues(vs)
+	if err != nil {
+		return nil, err
+	}
+	return &DotPlot{
+		Values:     values,
+		GlyphStyle: DefaultGlyphStyle,
+		GuideStyle: DefaultGuideStyle,
+	}, nil
+}
+
+// Plot draws the DotPlot, implementing the
+// plot.Plotter interface.
+func (d *DotPlot) Plot(c draw.Canvas, plt *plot.Plot) {
+	trX, trY := plt.Transforms(&c)
+	for i, v := range d.Values {
+		x := trX(d.XMin + float64(i))
+		if !c.ContainsX(x) {
+			continue
+		}
+		x += d.Offset
+		if d.GuideStyle.Color != nil {
+			c.StrokeLine2(d.GuideStyle, x, c.Min.Y, x, c.Max.Y)
+		}
+		y := trY(v)
+		if c.ContainsY(y) {
+			c.DrawGlyphNoClip(d.GlyphStyle, draw.Point{x, y})
+		}
+	}
+}
+
+// DataRange returns the minimum and maximum x
+// and y values, implementing the plot.DataRanger
+// interface.
+func (d *DotPlot) DataRange() (xmin, xmax, ymin, ymax float64) {
+	ymin, ymax = Range(d.Values)
+	return d.XMin, d.XMin + float64(len(d.Values)-1), ymin, ymax
+}
+
+// GlyphBoxes returns a slice of GlyphBoxes for the
+// dots, implementing the plot.GlyphBoxer interface.
+func (d *DotPlot) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
+	bs := make([]plot.GlyphBox, len(d.Values))
+	for i, v := range d.Values {
+		bs[i].X = plt.X.Norm(d.XMin + float64(i))
+		bs[i].Y = plt.Y.Norm(v)
+		bs[i].Rectangle = d.GlyphStyle.Rectangle()
+		bs[i].Rectangle.Min.X += d.Offset
+		bs[i].Rectangle.Max.X += d.Offset
+	}
+	return bs
+}
+
+// Pick returns the index of the dot nearest to pt,
+// implementing the plot.Picker interface.
+func (d *DotPlot) Pick(c draw.Canvas, plt *plot.Plot, pt draw.Point, tol vg.Length) (int, vg.Length, bool) {
+	trX, trY := plt.Transforms(&c)
+	pk := picker{tol: tol}
+	for i, v := range d.Values {
+		dx := trX(d.XMin+float64(i)) + d.Offset - pt.X
+		dy := trY(v) - pt.Y
+		pk.add(i, vg.Length(math.Hypot(float64(dx), float64(dy))))
+	}
+	return pk.result()
+}
+
+// Thumbnail the thumbnail for the DotPlot,
+// implementing the plot.Thumbnailer interface.
+func (d *DotPlot) Thumbnail(c *draw.Canvas) {
+	c.DrawGlyph(d.GlyphStyle, c.Center())
+}
+
+// HorizDotPlot is like a regular DotPlot, however,
+// it draws horizontally instead of vertically, as
+// is usual for Cleveland dot plots with long
+// category names.  The location of each value
+// along the y axis is the index of its value.
+type HorizDotPlot struct{ *DotPlot }
+
+// MakeHorizDotPlot returns a HorizDotPlot with a dot
+// for each value that uses the default glyph and
+// guide line styles.
+func MakeHorizDotPlot(vs Valuer) (HorizDotPlot, error) {
+	d, err := NewDotPlot(vs)
+	return HorizDotPlot{d}, err
+}
+
+// Plot draws the HorizDotPlot, implementing the
+// plot.Plotter interface.
+func (d HorizDotPlot) Plot(c draw.Canvas, plt *plot.Plot) {
+	trX, trY := plt.Transforms(&c)
+	for i, v := range d.Values {
+		y := trY(d.XMin + float64(i))
+		if !c.ContainsY(y) {
+			continue
+		}
+		y += d.Offset
+		if d.GuideStyle.Color != nil {
+			c.StrokeLine2(d.GuideStyle, c.Min.X, y, c.Max.X, y)
+		}
+		x := trX(v)
+		if c.ContainsX(x) {
+			c.DrawGlyphNoClip(d.GlyphStyle, draw.Point{x, y})
+		}
+	}
+}
+
+// DataRange returns the minimum and maximum x
+// and y values, implementing the plot.DataRanger
+// interface.
+func (d HorizDotPlot) DataRange() (xmin, xmax, ymin, ymax float64) {
+	xmin, xmax = Range(d.Values)
+	return xmin, xmax, d.XMin, d.XMin + float64(len(d.Values)-1)
+}
+
+// GlyphBoxes returns a slice of GlyphBoxes for the
+// dots, implementing the plot.GlyphBoxer interface.
+func (d HorizDotPlot) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
+	bs := make([]plot.GlyphBox, len(d.Values))
+	for i, v := range d.Values {
+		bs[i].X = plt.X.Norm(v)
+		bs[i].Y = plt.Y.Norm(d.XMin + float64(i))
+		bs[i].Rectangle = d.GlyphStyle.Rectangle()
+		bs[i].Rectangle.Min.Y += d.Offset
+		bs[i].Rectangle.Max.Y += d.Offset
+	}
+	return bs
+}
+
+// Pick returns the index of the dot nearest to pt,
+// implementing the plot.Picker interface.
+func (d HorizDotPlot) Pick(c draw.Canvas, plt *plot.Plot, pt draw.Point, tol vg.Length) (int, vg.Length, bool) {
+	trX, trY := plt.Transforms(&c)
+	pk := picker{tol: tol}
+	for i, v := range d.Values {
+		dx := trX(v) - pt.X
+		dy := trY(d.XMin+float64(i)) + d.Offset - pt.Y
+		pk.add(i, vg.Length(math.Hypot(float64(dx), float64(dy))))
+	}
+	return pk.result()
+}
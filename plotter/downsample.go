@@ -0,0 +1,165 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"math"
+
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+)
+
+// Downsampling is a method of reducing the points of
+// a line to those needed to draw it at the resolution
+// of the canvas on which it is drawn.
+type Downsampling int
+
+const (
+	// NoDownsampling draws every point.
+	NoDownsampling Downsampling = iota
+
+	// MinMaxDownsampling draws, of each run of points
+	// in the same pixel column of the canvas, only the
+	// first, last, lowest and highest points.  A line
+	// with increasing x values is drawn the same as
+	// with all of its points.
+	MinMaxDownsampling
+
+	// LTTBDownsampling draws two points for each pixel
+	// column of the canvas, chosen by the Largest-
+	// Triangle-Three-Buckets algorithm of Steinarsson
+	// (2013) to preserve the visual shape of the line.
+	LTTBDownsampling
+)
+
+// pixelWidth returns the width of a pixel of the canvas.
+func pixelWidth(c draw.Canvas) vg.Length {
+	dpi := c.DPI()
+	if dpi <= 0 {
+		return vg.Points(1)
+	}
+	return vg.Inch / vg.Length(dpi)
+}
+
+// column returns the index of the pixel column of
+// width w, counted from x0, that contains x.
+func column(x, x0, w vg.Length) int {
+	return int(math.Floor(float64((x - x0) / w)))
+}
+
+// transformXYs returns the points of data
+// transformed to the canvas.
+func transformXYs(data XYer, trX, trY func(float64) vg.Length) []draw.Point {
+	ps := make([]draw.Point, data.Len())
+	for i := range ps {
+		x, y := data.XY(i)
+		ps[i].X = trX(x)
+		ps[i].Y = trY(y)
+	}
+	return ps
+}
+
+// minMaxPoints returns the points of data transformed
+// to the canvas, keeping only the first, last, lowest
+// and highest points of each run of points in the
+// same column of width w counted from x0.
+func minMaxPoints(data XYer, trX, trY func(float64) vg.Length, x0, w vg.Length) []draw.Point {
+	var (
+		ps   []draw.Point
+		run  [4]int // The first, lowest, highest and last points.
+		pts  [4]draw.Point
+		col  int
+		open bool
+	)
+	flush := func() {
+		// Sort the four points by index, which
+		// is at most a few swaps.
+		for i := 1; i < len(run); i++ {
+			for j := i; j > 0 && run[j] < run[j-1]; j-- {
+				run[j], run[j-1] = run[j-1], run[j]
+				pts[j], pts[j-1] = pts[j-1], pts[j]
+			}
+		}
+		for i := range run {
+			if i > 0 && run[i] == run[i-1] {
+				continue
+			}
+			ps = append(ps, pts[i])
+		}
+	}
+	for i, n := 0, data.Len(); i < n; i++ {
+		x, y := data.XY(i)
+		p := draw.Point{trX(x), trY(y)}
+		if c := column(p.X, x0, w); !open || c != col {
+			if open {
+				flush()
+			}
+			col, open = c, true
+			run = [4]int{i, i, i, i}
+			pts = [4]draw.Point{p, p, p, p}
+			continue
+		}
+		if p.Y < pts[1].Y {
+			run[1], pts[1] = i, p
+		}
+		if p.Y > pts[2].Y {
+			run[2], pts[2] = i, p
+		}
+		run[3], pts[3] = i, p
+	}
+	if open {
+		flush()
+	}
+	return ps
+}
+
+// lttb returns n of the points chosen by the
+// Largest-Triangle-Three-Buckets algorithm, or
+// the points if there are no more than n of them.
+func lttb(ps []draw.Point, n int) []draw.Point {
+	if n >= len(ps) || n < 3 {
+		return ps
+	}
+	out := make([]draw.Point, 0, n)
+	out = append(out, ps[0])
+
+	// The points between the first and the last
+	// are divided into n-2 buckets, from each of
+	// which the point forming the largest triangle
+	// with the point chosen before it and the mean
+	// of the next bucket is chosen.
+	every := float64(len(ps)-2) / float64(n-2)
+	a := ps[0]
+	for i := 0; i < n-2; i++ {
+		lo := int(float64(i+1)*every) + 1
+		hi := int(float64(i+2)*every) + 1
+		if hi > len(ps) {
+			hi = len(ps)
+		}
+		if lo >= hi {
+			lo = hi - 1
+		}
+		var mean draw.Point
+		for _, p := range ps[lo:hi] {
+			mean.X += p.X
+			mean.Y += p.Y
+		}
+		mean.X /= vg.Length(hi - lo)
+		mean.Y /= vg.Length(hi - lo)
+
+		start := int(float64(i)*every) + 1
+		end := int(float64(i+1)*every) + 1
+		best, maxArea := start, -1.0
+		for j := start; j < end; j++ {
+			area := math.Abs(float64((a.X-mean.X)*(ps[j].Y-a.Y) - (a.X-ps[j].X)*(mean.Y-a.Y)))
+			if area > maxArea {
+				best, maxArea = j, area
+			}
+		}
+		a = ps[best]
+		out = append(out, a)
+	}
+	return append(out, ps[len(ps)-1])
+}
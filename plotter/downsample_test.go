@@ -0,0 +1,225 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/recorder"
+)
+
+// signal returns n samples of a noisy sine wave.
+func signal(n int) XYs {
+	rnd := rand.New(rand.NewSource(1))
+	xys := make(XYs, n)
+	for i := range xys {
+		xys[i].X = float64(i)
+		xys[i].Y = math.Sin(float64(i)/float64(n)*4*math.Pi) + rnd.NormFloat64()*0.1
+	}
+	return xys
+}
+
+// strokedPoints returns the number of points in the
+// paths stroked by the actions recorded by r.
+func strokedPoints(r *recorder.Canvas) int {
+	var n int
+	for _, a := range r.Actions {
+		if s, ok := a.(*recorder.Stroke); ok {
+			n += len(s.Path)
+		}
+	}
+	return n
+}
+
+func TestLTTB(t *testing.T) {
+	ps := make([]draw.Point, 1000)
+	for i := range ps {
+		ps[i] = draw.Point{vg.Length(i), vg.Length(i % 7)}
+	}
+	ps[500].Y = 100
+	for _, n := range []int{3, 10, 100, 999} {
+		got := lttb(ps, n)
+		if len(got) != n {
+			t.Errorf("unexpected number of points for n=%d: got:%d want:%d", n, len(got), n)
+			continue
+		}
+		if got[0] != ps[0] || got[n-1] != ps[len(ps)-1] {
+			t.Errorf("end points not kept for n=%d", n)
+		}
+		var peak bool
+		for _, p := range got {
+			peak = peak || p == ps[500]
+		}
+		if !peak {
+			t.Errorf("peak not kept for n=%d", n)
+		}
+	}
+	if got := lttb(ps[:5], 10); len(got) != 5 {
+		t.Errorf("unexpected number of points for short input: got:%d want:5", len(got))
+	}
+}
+
+func TestMinMaxPoints(t *testing.T) {
+	xys := XYs{
+		{0, 1}, {0.2, 5}, {0.4, -3}, {0.6, 2},
+		{1, 0}, {1.5, 0},
+		{2, 4},
+	}
+	id := func(v float64) vg.Length { return vg.Length(v) }
+	got := minMaxPoints(xys, id, id, 0, 1)
+	want := []draw.Point{
+		{0, 1}, {0.2, 5}, {0.4, -3}, {0.6, 2},
+		{1, 0}, {1.5, 0},
+		{2, 4},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected points: got:%v want:%v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("unexpected point %d: got:%v want:%v", i, got[i], want[i])
+		}
+	}
+
+	xys = append(XYs{{0, 0}, {0.1, 1}, {0.3, 0.5}}, xys...)
+	if got := minMaxPoints(xys, id, id, 0, 1); len(got) != len(want) {
+		t.Errorf("unexpected number of points: got:%d want:%d", len(got), len(want))
+	}
+}
+
+func TestLineDownsampling(t *testing.T) {
+	data := signal(100000)
+	for _, d := range []Downsampling{MinMaxDownsampling, LTTBDownsampling} {
+		l, err := NewLine(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		l.Downsampling = d
+		p, err := plot.New()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		p.Add(l)
+		r := recorder.New(72)
+		c := p.DataCanvas(draw.NewCanvas(r, 300, 200))
+		r.Reset()
+		l.Plot(c, p)
+		n := strokedPoints(r)
+		if max := 4 * int(c.Size().X+1); n == 0 || n > max {
+			t.Errorf("unexpected number of stroked points for downsampling %d: got:%d want at most %d", d, n, max)
+		}
+	}
+}
+
+func TestRefPlotters(t *testing.T) {
+	data := XYs{{0, 0}, {1, 1}, {2, 4}}
+	l, err := NewLineRef(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s, err := NewScatterRef(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data[2].Y = 9
+	if _, _, _, ymax := l.DataRange(); ymax != 9 {
+		t.Errorf("line does not refer to data: got ymax:%v want:9", ymax)
+	}
+	if _, _, _, ymax := s.DataRange(); ymax != 9 {
+		t.Errorf("scatter does not refer to data: got ymax:%v want:9", ymax)
+	}
+
+	if _, err := NewLineRef(XYs{{0, math.Inf(1)}}); err == nil {
+		t.Error("expected error for infinite point")
+	}
+}
+
+func TestScatterMerge(t *testing.T) {
+	data := make(XYs, 1000)
+	for i := range data {
+		data[i].X = float64(i%10) / 1e5
+		data[i].Y = float64(i%10) / 1e5
+	}
+	data = append(data, struct{ X, Y float64 }{1, 1})
+	for _, test := range []struct {
+		merge bool
+		want  int
+	}{
+		{merge: false, want: len(data)},
+		{merge: true, want: 2},
+	} {
+		s, err := NewScatter(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		s.Merge = test.merge
+		p, err := plot.New()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		p.Add(s)
+		r, _ := drawOn(p, s)
+		if paths, _ := strokes(r); len(paths) != test.want {
+			t.Errorf("unexpected number of glyphs for merge=%t: got:%d want:%d", test.merge, len(paths), test.want)
+		}
+		if n := len(s.GlyphBoxes(p)); n != test.want {
+			t.Errorf("unexpected number of glyph boxes for merge=%t: got:%d want:%d", test.merge, n, test.want)
+		}
+	}
+}
+
+func BenchmarkLineNone(b *testing.B)   { lineBench(NoDownsampling, b) }
+func BenchmarkLineMinMax(b *testing.B) { lineBench(MinMaxDownsampling, b) }
+func BenchmarkLineLTTB(b *testing.B)   { lineBench(LTTBDownsampling, b) }
+
+func lineBench(d Downsampling, b *testing.B) {
+	l, err := NewLineRef(signal(1000000))
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	l.Downsampling = d
+	p, err := plot.New()
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	p.Add(l)
+	r := recorder.New(72)
+	c := p.DataCanvas(draw.NewCanvas(r, 600, 400))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Reset()
+		l.Plot(c, p)
+	}
+}
+
+func BenchmarkScatter(b *testing.B)      { scatterBench(false, b) }
+func BenchmarkScatterMerge(b *testing.B) { scatterBench(true, b) }
+
+func scatterBench(merge bool, b *testing.B) {
+	s, err := NewScatterRef(signal(100000))
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	s.Merge = merge
+	p, err := plot.New()
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	p.Add(s)
+	r := recorder.New(72)
+	c := p.DataCanvas(draw.NewCanvas(r, 600, 400))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Reset()
+		s.Plot(c, p)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+)
+
+// ECDF implements the Plotter interface, drawing
+// the empirical cumulative distribution function
+// of a sample as a step line.
+type ECDF struct {
+	// Values is a sorted copy of the sample values.
+	Values
+
+	// LineStyle is the style of the step line.
+	draw.LineStyle
+
+	// Confidence is the confidence level, such as
+	// 0.95, of a band drawn around the step line
+	// that contains the cumulative distribution
+	// function of the sampled distribution.  The
+	// band is given by the Dvoretzky–Kiefer–Wolfowitz
+	// inequality.  If Confidence is zero then no
+	// band is drawn.
+	Confidence float64
+
+	// BandColor is the fill color of the
+	// confidence band.
+	BandColor color.Color
+}
+
+// NewECDF returns an ECDF of the sample values
+// that uses the default line style and does not
+// draw a confidence band.
+func NewECDF(vs Valuer) (*ECDF, error) {
+	values, err := sortedValues(vs)
+	if err != nil {
+		return nil, err
+	}
+	return &ECDF{
+		Values:    values,
+		LineStyle: DefaultLineStyle,
+		BandColor: color.Gray{Y: 0xd0},
+	}, nil
+}
+
+// Plot draws the ECDF, implementing the plot.Plotter
+// interface.
+func (e *ECDF) Plot(c draw.Canvas, plt *plot.Plot) {
+	trX, trY := plt.Transforms(&c)
+
+	if e.Confidence != 0 && e.BandColor != nil {
+		eps := e.bandWidth()
+		upper := e.steps(trX, trY, func(f float64) float64 { return math.Min(f+eps, 1) })
+		lower := e.steps(trX, trY, func(f float64) float64 { return math.Max(f-eps, 0) })
+		band := upper
+		for i := len(lower) - 1; i >= 0; i-- {
+			band = append(band, lower[i])
+		}
+		c.FillPolygon(e.BandColor, c.ClipPolygonXY(band))
+	}
+
+	line := e.steps(trX, trY, func(f float64) float64 { return f })
+	c.StrokeLines(e.LineStyle, c.ClipLinesXY(line)...)
+}
+
+// steps returns the points of a step line through
+// the values of the ECDF transformed by f.
+func (e *ECDF) steps(trX, trY func(float64) vg.Length, f func(float64) float64) []draw.Point {
+	n := float64(len(e.Values))
+	pts := make([]draw.Point, 0, 2*len(e.Values))
+	for i, v := range e.Values {
+		x := trX(v)
+		pts = append(pts,
+			draw.Point{x, trY(f(float64(i) / n))},
+			draw.Point{x, trY(f(float64(i+1) / n))},
+		)
+	}
+	return pts
+}
+
+// bandWidth returns the half width of the
+// confidence band of the ECDF.
+func (e *ECDF) bandWidth() float64 {
+	alpha := 1 - e.Confidence
+	return math.Sqrt(math.Log(2/alpha) / (2 * float64(len(e.Values))))
+}
+
+// DataRange returns the minimum and maximum
+// x and y values, implementing the plot.DataRanger
+// interface.
+func (e *ECDF) DataRange() (xmin, xmax, ymin, ymax float64) {
+	xmin, xmax = Range(e.Values)
+	return xmin, xmax, 0, 1
+}
+
+// Thumbnail the thumbnail for the ECDF,
+// implementing the plot.Thumbnailer interface.
+func (e *ECDF) Thumbnail(c *draw.Canvas) {
+	if e.Confidence != 0 && e.BandColor != nil {
+		pts := []draw.Point{
+			{c.Min.X, c.Min.Y},
+			{c.Min.X, c.Max.Y},
+			{c.Max.X, c.Max.Y},
+			{c.Max.X, c.Min.Y},
+		}
+		c.FillPolygon(e.BandColor, c.ClipPolygonY(pts))
+	}
+	y := c.Center().Y
+	c.StrokeLine2(e.LineStyle, c.Min.X, y, c.Max.X, y)
+}
@@ -0,0 +1,68 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"testing"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/recorder"
+)
+
+func TestECDF(t *testing.T) {
+	e, err := NewECDF(Values{2, 0, 1, 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.X.Padding, p.Y.Padding = 0, 0
+	p.Add(e)
+	if p.Y.Min != 0 || p.Y.Max != 1 {
+		t.Errorf("unexpected y range: got:[%v,%v] want:[0,1]", p.Y.Min, p.Y.Max)
+	}
+
+	for _, test := range []struct {
+		confidence float64
+		fills      int
+	}{
+		{confidence: 0, fills: 0},
+		{confidence: 0.95, fills: 1},
+	} {
+		e.Confidence = test.confidence
+		r := recorder.New(72)
+		c := draw.Canvas{Canvas: r, Rectangle: draw.Rectangle{Max: draw.Point{X: 30, Y: 100}}}
+		e.Plot(c, p)
+
+		var fills int
+		var line vg.Path
+		for _, a := range r.Actions {
+			switch a := a.(type) {
+			case *recorder.Fill:
+				fills++
+			case *recorder.Stroke:
+				line = a.Path
+			}
+		}
+		if fills != test.fills {
+			t.Errorf("unexpected number of bands for confidence %v: got:%d want:%d", test.confidence, fills, test.fills)
+		}
+		want := []struct{ X, Y vg.Length }{
+			{0, 0}, {0, 25}, {10, 25}, {10, 50}, {20, 50}, {20, 75}, {30, 75}, {30, 100},
+		}
+		if len(line) != len(want) {
+			t.Fatalf("unexpected number of step points: got:%d want:%d", len(line), len(want))
+		}
+		for i, pc := range line {
+			if pc.X != want[i].X || pc.Y != want[i].Y {
+				t.Errorf("unexpected step point %d: got:(%v,%v) want:(%v,%v)", i, pc.X, pc.Y, want[i].X, want[i].Y)
+			}
+		}
+	}
+}
@@ -2,74 +2,65 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ignore
-
-// A simple test program to test plotters.
-package main
+package plotter_test
 
 import (
 	"fmt"
 	"image/color"
-	"log"
 	"math"
 	"math/rand"
+	"path/filepath"
+	"testing"
 
 	"github.com/gonum/matrix/mat64"
 
 	"github.com/gonum/plot"
 	"github.com/gonum/plot/palette"
 	"github.com/gonum/plot/plotter"
+	"github.com/gonum/plot/plottest"
 	"github.com/gonum/plot/vg"
 	"github.com/gonum/plot/vg/draw"
 )
 
 var examples = []struct {
-	name string
-	p    *plot.Plot
+	name   string
+	mkplot func() *plot.Plot
 }{
-	{"example_logo", Example_logo()},
-	{"example_functions", Example_functions()},
-	{"example_boxPlots", Example_boxPlots()},
-	{"example_groupedBoxPlots", Example_groupedBoxPlots()},
-	{"example_groupedHorizontalBoxPlots", Example_groupedHorizontalBoxPlots()},
-	{"example_quartPlots", Example_quartPlots()},
-	{"example_groupedQuartPlots", Example_groupedQuartPlots()},
-	{"example_groupedHorizontalQuartPlots", Example_groupedHorizontalQuartPlots()},
-	{"example_verticalBoxPlots", Example_verticalBoxPlots()},
-	{"example_verticalQuartPlots", Example_verticalQuartPlots()},
-	{"example_horizontalBoxPlots", Example_horizontalBoxPlots()},
-	{"example_horizontalQuartPlots", Example_horizontalQuartPlots()},
-	{"example_points", Example_points()},
-	{"example_errBars", Example_errBars()},
-	{"example_bubbles", Example_bubbles()},
-	{"example_histogram", Example_histogram()},
-	{"example_barChart", Example_barChart()},
-	{"example_stackedBarChart", Example_stackedBarChart()},
-	{"example_heatMap", Example_heatMap()},
-}
-
-var formats = []string{
-	".eps",
-	".pdf",
-	".svg",
-	".png",
-	".tiff",
-	".jpg",
-}
-
-func main() {
+	{"example_logo", exampleLogo},
+	{"example_functions", exampleFunctions},
+	{"example_boxPlots", exampleBoxPlots},
+	{"example_groupedBoxPlots", exampleGroupedBoxPlots},
+	{"example_groupedHorizontalBoxPlots", exampleGroupedHorizontalBoxPlots},
+	{"example_quartPlots", exampleQuartPlots},
+	{"example_groupedQuartPlots", exampleGroupedQuartPlots},
+	{"example_groupedHorizontalQuartPlots", exampleGroupedHorizontalQuartPlots},
+	{"example_verticalBoxPlots", exampleVerticalBoxPlots},
+	{"example_verticalQuartPlots", exampleVerticalQuartPlots},
+	{"example_horizontalBoxPlots", exampleHorizontalBoxPlots},
+	{"example_horizontalQuartPlots", exampleHorizontalQuartPlots},
+	{"example_points", examplePoints},
+	{"example_errBars", exampleErrBars},
+	{"example_bubbles", exampleBubbles},
+	{"example_histogram", exampleHistogram},
+	{"example_barChart", exampleBarChart},
+	{"example_stackedBarChart", exampleStackedBarChart},
+	{"example_heatMap", exampleHeatMap},
+}
+
+// TestExamples checks the example plots against golden recordings
+// in testdata. The recordings do not depend on the rasterizer, so
+// they are compared rather than rendered images. Run the tests with
+// -update-goldens to regenerate the golden files after an intended
+// change.
+func TestExamples(t *testing.T) {
 	for _, ex := range examples {
-		for _, f := range formats {
-			err := ex.p.Save(4*vg.Inch, 4*vg.Inch, ex.name+f)
-			if err != nil {
-				log.Fatalf("failed to save %s%s: %v", ex.name, f, err)
-			}
-		}
+		golden := filepath.Join("testdata", ex.name)
+		plottest.CheckRecording(t, ex.mkplot(), 4*vg.Inch, 4*vg.Inch, golden+".vgrec", plottest.DefaultActionTolerance)
 	}
 }
 
 // Draw the plotinum logo.
-func Example_logo() *plot.Plot {
+func exampleLogo() *plot.Plot {
 	p, err := plot.New()
 	if err != nil {
 		panic(err)
@@ -103,8 +94,8 @@ func Example_logo() *plot.Plot {
 	return p
 }
 
-// Example_functions draws some functions.
-func Example_functions() *plot.Plot {
+// exampleFunctions draws some functions.
+func exampleFunctions() *plot.Plot {
 	p, err := plot.New()
 	if err != nil {
 		panic(err)
@@ -139,8 +130,8 @@ func Example_functions() *plot.Plot {
 	return p
 }
 
-// Example_boxPlots draws vertical boxplots.
-func Example_boxPlots() *plot.Plot {
+// exampleBoxPlots draws vertical boxplots.
+func exampleBoxPlots() *plot.Plot {
 	rand.Seed(int64(0))
 	n := 100
 	uniform := make(plotter.Values, n)
@@ -171,8 +162,8 @@ func Example_boxPlots() *plot.Plot {
 	return p
 }
 
-// Example_groupedBoxPlots draws vertical boxplots.
-func Example_groupedBoxPlots() *plot.Plot {
+// exampleGroupedBoxPlots draws vertical boxplots.
+func exampleGroupedBoxPlots() *plot.Plot {
 	rand.Seed(int64(0))
 	n := 100
 	uniform := make(plotter.Values, n)
@@ -207,8 +198,8 @@ func Example_groupedBoxPlots() *plot.Plot {
 	return p
 }
 
-// Example_groupedHorizontalBoxPlots draws vertical boxplots.
-func Example_groupedHorizontalBoxPlots() *plot.Plot {
+// exampleGroupedHorizontalBoxPlots draws vertical boxplots.
+func exampleGroupedHorizontalBoxPlots() *plot.Plot {
 	rand.Seed(int64(0))
 	n := 100
 	uniform := make(plotter.Values, n)
@@ -240,8 +231,8 @@ func Example_groupedHorizontalBoxPlots() *plot.Plot {
 	return p
 }
 
-// Example_quartPlots draws vertical quartile plots.
-func Example_quartPlots() *plot.Plot {
+// exampleQuartPlots draws vertical quartile plots.
+func exampleQuartPlots() *plot.Plot {
 	rand.Seed(int64(0))
 	n := 100
 	uniform := make(plotter.Values, n)
@@ -271,7 +262,7 @@ func Example_quartPlots() *plot.Plot {
 	return p
 }
 
-func Example_groupedQuartPlots() *plot.Plot {
+func exampleGroupedQuartPlots() *plot.Plot {
 	rand.Seed(int64(0))
 	n := 100
 	uniform := make(plotter.Values, n)
@@ -305,7 +296,7 @@ func Example_groupedQuartPlots() *plot.Plot {
 	return p
 }
 
-func Example_groupedHorizontalQuartPlots() *plot.Plot {
+func exampleGroupedHorizontalQuartPlots() *plot.Plot {
 	rand.Seed(int64(0))
 	n := 100
 	uniform := make(plotter.Values, n)
@@ -339,9 +330,9 @@ func Example_groupedHorizontalQuartPlots() *plot.Plot {
 	return p
 }
 
-// Example_verticalBoxPlots draws vertical boxplots
+// exampleVerticalBoxPlots draws vertical boxplots
 // with some labels on their points.
-func Example_verticalBoxPlots() *plot.Plot {
+func exampleVerticalBoxPlots() *plot.Plot {
 	rand.Seed(int64(0))
 	n := 100
 	uniform := make(valueLabels, n)
@@ -391,9 +382,9 @@ func Example_verticalBoxPlots() *plot.Plot {
 	return p
 }
 
-// Example_verticalQuartPlots draws vertical boxplots
+// exampleVerticalQuartPlots draws vertical boxplots
 // with some labels on their points.
-func Example_verticalQuartPlots() *plot.Plot {
+func exampleVerticalQuartPlots() *plot.Plot {
 	rand.Seed(int64(0))
 	n := 100
 	uniform := make(valueLabels, n)
@@ -461,9 +452,9 @@ func (vs valueLabels) Label(i int) string {
 	return vs[i].Label
 }
 
-// Example_horizontalBoxPlots draws horizontal boxplots
+// exampleHorizontalBoxPlots draws horizontal boxplots
 // with some labels on their points.
-func Example_horizontalBoxPlots() *plot.Plot {
+func exampleHorizontalBoxPlots() *plot.Plot {
 	rand.Seed(int64(0))
 	n := 100
 	uniform := make(valueLabels, n)
@@ -515,9 +506,9 @@ func Example_horizontalBoxPlots() *plot.Plot {
 	return p
 }
 
-// Example_horizontalQuartPlots draws horizontal quartile plots
+// exampleHorizontalQuartPlots draws horizontal quartile plots
 // with some labels on their points.
-func Example_horizontalQuartPlots() *plot.Plot {
+func exampleHorizontalQuartPlots() *plot.Plot {
 	rand.Seed(int64(0))
 	n := 100
 	uniform := make(valueLabels, n)
@@ -569,9 +560,9 @@ func Example_horizontalQuartPlots() *plot.Plot {
 	return p
 }
 
-// Example_points draws some scatter points, a line,
+// examplePoints draws some scatter points, a line,
 // and a line with points.
-func Example_points() *plot.Plot {
+func examplePoints() *plot.Plot {
 	rand.Seed(int64(0))
 
 	n := 15
@@ -628,8 +619,8 @@ func randomPoints(n int) plotter.XYs {
 	return pts
 }
 
-// Example_errBars draws points and error bars.
-func Example_errBars() *plot.Plot {
+// exampleErrBars draws points and error bars.
+func exampleErrBars() *plot.Plot {
 
 	type errPoints struct {
 		plotter.XYs
@@ -674,7 +665,7 @@ func randomError(n int) plotter.Errors {
 	return err
 }
 
-func Example_bubbles() *plot.Plot {
+func exampleBubbles() *plot.Plot {
 	rand.Seed(int64(0))
 	n := 10
 	bubbleData := randomTriples(n)
@@ -714,7 +705,7 @@ func randomTriples(n int) plotter.XYZs {
 }
 
 // An example of making a histogram.
-func Example_histogram() *plot.Plot {
+func exampleHistogram() *plot.Plot {
 	rand.Seed(int64(0))
 	n := 10000
 	vals := make(plotter.Values, n)
@@ -753,7 +744,7 @@ func stdNorm(x float64) float64 {
 }
 
 // An example of making a bar chart.
-func Example_barChart() *plot.Plot {
+func exampleBarChart() *plot.Plot {
 	groupA := plotter.Values{20, 35, 30, 35, 27}
 	groupB := plotter.Values{25, 32, 34, 20, 25}
 	groupC := plotter.Values{12, 28, 15, 21, 8}
@@ -799,7 +790,7 @@ func Example_barChart() *plot.Plot {
 }
 
 // An example of making a stacked bar chart.
-func Example_stackedBarChart() *plot.Plot {
+func exampleStackedBarChart() *plot.Plot {
 	groupA := plotter.Values{20, 35, 30, 35, 27}
 	groupB := plotter.Values{25, 32, 34, 20, 25}
 	groupC := plotter.Values{12, 28, 15, 21, 8}
@@ -861,7 +852,7 @@ func (g unitGrid) Y(r int) float64 {
 	return float64(r)
 }
 
-func Example_heatMap() *plot.Plot {
+func exampleHeatMap() *plot.Plot {
 	m := unitGrid{mat64.NewDense(3, 4, []float64{
 		1, 2, 3, 4,
 		5, 6, 7, 8,
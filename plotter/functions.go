@@ -5,6 +5,8 @@
 package plotter
 
 import (
+	"math"
+
 	"github.com/gonum/plot"
 	"github.com/gonum/plot/vg/draw"
 )
@@ -15,6 +17,12 @@ type Function struct {
 	F       func(float64) float64
 	Samples int
 	draw.LineStyle
+
+	// Missing is the policy for samples for which
+	// F returns NaN.  By default the line is broken
+	// at them, and otherwise the samples on either
+	// side of them are joined.
+	Missing MissingPolicy
 }
 
 // NewFunction returns a Function that plots F using
@@ -28,18 +36,30 @@ func NewFunction(f func(float64) float64) *Function {
 }
 
 // Plot implements the Plotter interface, drawing a line
-// that connects each point in the Line.
+// that connects each point in the Line.  Samples for
+// which F returns NaN are handled by the Missing policy.
 func (f *Function) Plot(c draw.Canvas, p *plot.Plot) {
 	trX, trY := p.Transforms(&c)
 
 	d := (p.X.Max - p.X.Min) / float64(f.Samples-1)
-	line := make([]draw.Point, f.Samples)
-	for i := range line {
+	var lines [][]draw.Point
+	line := make([]draw.Point, 0, f.Samples)
+	for i := 0; i < f.Samples; i++ {
 		x := p.X.Min + float64(i)*d
-		line[i].X = trX(x)
-		line[i].Y = trY(f.F(x))
+		y := f.F(x)
+		if math.IsNaN(y) {
+			if (f.Missing == MissingBreak || f.Missing == MissingError) && len(line) > 0 {
+				lines = append(lines, line)
+				line = nil
+			}
+			continue
+		}
+		line = append(line, draw.Point{trX(x), trY(y)})
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
 	}
-	c.StrokeLines(f.LineStyle, c.ClipLinesXY(line)...)
+	c.StrokeLines(f.LineStyle, c.ClipLinesXY(lines...)...)
 }
 
 // Thumbnail draws a line in the given style down the
@@ -0,0 +1,244 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"errors"
+	"image/color"
+	"math"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+)
+
+// GanttTask is a task of a Gantt chart.
+type GanttTask struct {
+	// Start and End are the times of the start and
+	// the end of the task.  A task that ends when it
+	// starts is drawn as a milestone.
+	Start, End float64
+
+	// Row is the location of the task along the y
+	// axis, such as the index of the name of the
+	// task in the names given to Plot.NominalY.
+	Row float64
+
+	// Category is the index in the colors of the
+	// Gantt chart of the color of the task.
+	Category int
+
+	// DependsOn are the indices of the tasks that
+	// must end before the task starts.  Each is
+	// drawn as an arrow from the end of the task
+	// depended on to the start of this task.
+	DependsOn []int
+}
+
+// Gantt implements the Plotter interface, drawing a
+// Gantt chart or timeline: a horizontal bar for the
+// interval of each task along a time axis, a glyph
+// for each milestone and an arrow for each dependency
+// between tasks.
+type Gantt struct {
+	// Tasks is a copy of the tasks of the chart.
+	Tasks []GanttTask
+
+	// Width is the width across the time axis
+	// of the bars.
+	Width vg.Length
+
+	// Colors are the fill colors of the bars of
+	// the categories of tasks.  Categories beyond
+	// the number of colors reuse the colors.
+	Colors []color.Color
+
+	// LineStyle is the style of the outline of the bars.
+	draw.LineStyle
+
+	// Milestone is the style of the glyphs
+	// of milestones.
+	Milestone draw.GlyphStyle
+
+	// ArrowStyle is the line style of the arrows
+	// of dependencies.  If its Color is nil then
+	// no arrows are drawn.
+	ArrowStyle draw.LineStyle
+
+	// ArrowSize is the length of the heads
+	// of the arrows.
+	ArrowSize vg.Length
+}
+
+// NewGantt returns a Gantt chart of the tasks with
+// bars of the given width.  An error is returned if
+// there are no tasks, or if a task ends before it
+// starts or depends on a task that is not in the chart.
+func NewGantt(tasks []GanttTask, width vg.Length) (*Gantt, error) {
+	if width <= 0 {
+		return nil, errors.New("Width parameter was not positive")
+	}
+	if len(tasks) == 0 {
+		return nil, ErrNoData
+	}
+	cpy := make([]GanttTask, len(tasks))
+	for i, t := range tasks {
+		if err := CheckFloats(t.Start, t.End, t.Row); err != nil {
+			return nil, err
+		}
+		if t.End < t.Start {
+			return nil, errors.New("Task ends before it starts")
+		}
+		if t.Category < 0 {
+			return nil, errors.New("Negative task category")
+		}
+		for _, d := range t.DependsOn {
+			if d < 0 || d >= len(tasks) {
+				return nil, errors.New("Dependency on a missing task")
+			}
+		}
+		cpy[i] = t
+		cpy[i].DependsOn = append([]int(nil), t.DependsOn...)
+	}
+	return &Gantt{
+		Tasks:     cpy,
+		Width:     width,
+		Colors:    []color.Color{color.Gray{Y: 0x80}},
+		LineStyle: DefaultLineStyle,
+		Milestone: draw.GlyphStyle{
+			Color:  color.Black,
+			Radius: width / 2,
+			Shape:  draw.DiamondGlyph{},
+		},
+		ArrowStyle: draw.LineStyle{Color: color.Black, Width: vg.Points(0.5)},
+		ArrowSize:  vg.Points(4),
+	}, nil
+}
+
+// color returns the fill color of the category.
+func (g *Gantt) color(category int) color.Color {
+	if len(g.Colors) == 0 {
+		return color.Black
+	}
+	return g.Colors[category%len(g.Colors)]
+}
+
+// Plot implements the plot.Plotter interface.
+func (g *Gantt) Plot(c draw.Canvas, plt *plot.Plot) {
+	trX, trY := plt.Transforms(&c)
+	for _, t := range g.Tasks {
+		y := trY(t.Row)
+		if !c.ContainsY(y) {
+			continue
+		}
+		if t.Start == t.End {
+			if c.ContainsX(trX(t.Start)) {
+				c.DrawGlyphNoClip(g.Milestone, draw.Point{trX(t.Start), y})
+			}
+			continue
+		}
+		base := draw.Point{trX(t.Start), y - g.Width/2}
+		end := draw.Point{trX(t.End), y + g.Width/2}
+		drawBar(&c, base, end, true, g.color(t.Category), g.LineStyle)
+	}
+
+	if g.ArrowStyle.Color == nil {
+		return
+	}
+	for _, t := range g.Tasks {
+		to := draw.Point{trX(t.Start), trY(t.Row)}
+		for _, d := range t.DependsOn {
+			from := draw.Point{trX(g.Tasks[d].End), trY(g.Tasks[d].Row)}
+			g.drawArrow(&c, from, to)
+		}
+	}
+}
+
+// drawArrow draws an arrow from one point to
+// another, with its head at the second point.
+func (g *Gantt) drawArrow(c *draw.Canvas, from, to draw.Point) {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	l := vg.Length(math.Hypot(float64(dx), float64(dy)))
+	if l == 0 {
+		return
+	}
+	dx, dy = dx/l, dy/l
+	base := draw.Point{to.X - dx*g.ArrowSize, to.Y - dy*g.ArrowSize}
+	c.StrokeLines(g.ArrowStyle, c.ClipLinesXY([]draw.Point{from, base})...)
+	if !c.Contains(to) {
+		return
+	}
+	w := g.ArrowSize / 2
+	head := []draw.Point{
+		to,
+		{base.X - dy*w, base.Y + dx*w},
+		{base.X + dy*w, base.Y - dx*w},
+	}
+	c.FillPolygon(g.ArrowStyle.Color, head)
+}
+
+// DataRange implements the plot.DataRanger interface.
+func (g *Gantt) DataRange() (xmin, xmax, ymin, ymax float64) {
+	xmin, ymin = math.Inf(1), math.Inf(1)
+	xmax, ymax = math.Inf(-1), math.Inf(-1)
+	for _, t := range g.Tasks {
+		xmin = math.Min(xmin, t.Start)
+		xmax = math.Max(xmax, t.End)
+		ymin = math.Min(ymin, t.Row)
+		ymax = math.Max(ymax, t.Row)
+	}
+	return
+}
+
+// GlyphBoxes implements the GlyphBoxer interface.
+func (g *Gantt) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
+	boxes := make([]plot.GlyphBox, len(g.Tasks))
+	for i, t := range g.Tasks {
+		boxes[i].X = plt.X.Norm(t.Start)
+		boxes[i].Y = plt.Y.Norm(t.Row)
+		boxes[i].Rectangle = draw.Rectangle{
+			Min: draw.Point{Y: -g.Width / 2},
+			Max: draw.Point{Y: g.Width / 2},
+		}
+		if t.Start == t.End {
+			boxes[i].Rectangle = g.Milestone.Rectangle()
+		}
+	}
+	return boxes
+}
+
+// Pick returns the index of the task nearest to pt,
+// implementing the plot.Picker interface.
+func (g *Gantt) Pick(c draw.Canvas, plt *plot.Plot, pt draw.Point, tol vg.Length) (int, vg.Length, bool) {
+	trX, trY := plt.Transforms(&c)
+	pk := picker{tol: tol}
+	for i, t := range g.Tasks {
+		y := trY(t.Row)
+		w := g.Width / 2
+		if t.Start == t.End {
+			w = g.Milestone.Radius
+		}
+		pk.add(i, rectDistance(
+			draw.Point{trX(t.Start), y - w},
+			draw.Point{trX(t.End), y + w},
+			pt,
+		))
+	}
+	return pk.result()
+}
+
+// Thumbnail draws a bar of the color of the first
+// category, implementing the plot.Thumbnailer
+// interface.
+func (g *Gantt) Thumbnail(c *draw.Canvas) {
+	barThumbnail(c, g.color(0), g.LineStyle)
+}
+
+// Thumbnailer returns a thumbnailer of a bar of the
+// color of the category, for adding the category
+// to a legend.
+func (g *Gantt) Thumbnailer(category int) plot.Thumbnailer {
+	return barThumbnailer{g.color(category), g.LineStyle}
+}
@@ -0,0 +1,71 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg/draw"
+)
+
+func TestGantt(t *testing.T) {
+	tasks := []GanttTask{
+		{Start: 0, End: 2, Row: 0},
+		{Start: 3, End: 5, Row: 1, Category: 1, DependsOn: []int{0}},
+		{Start: 5, End: 5, Row: 2, DependsOn: []int{1}},
+	}
+	g, err := NewGantt(tasks, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tasks[1].DependsOn[0] = 2
+	if g.Tasks[1].DependsOn[0] != 0 {
+		t.Error("tasks were not copied")
+	}
+	xmin, xmax, ymin, ymax := g.DataRange()
+	if xmin != 0 || xmax != 5 || ymin != 0 || ymax != 2 {
+		t.Errorf("unexpected data range: got:%v,%v,%v,%v want:0,5,0,2", xmin, xmax, ymin, ymax)
+	}
+
+	g.Colors = []color.Color{color.Black, color.White}
+	if th := g.Thumbnailer(3).(barThumbnailer); th.fill != color.White {
+		t.Errorf("unexpected thumbnail color of category 3: got:%v want:%v", th.fill, color.White)
+	}
+
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.NominalY("design", "build", "release")
+	p.Add(g)
+	r, c := drawOn(p, g)
+	// Two bars, a milestone and two arrow heads.
+	if _, fills := strokes(r); fills != 5 {
+		t.Errorf("unexpected number of fills: got:%d want:5", fills)
+	}
+
+	trX, trY := p.Transforms(&c)
+	if i, _, ok := g.Pick(c, p, draw.Point{trX(5), trY(2)}, 0); !ok || i != 2 {
+		t.Errorf("unexpected pick of milestone: got:%d,%t want:2,true", i, ok)
+	}
+
+	for _, test := range []struct {
+		name string
+		task GanttTask
+	}{
+		{name: "backward task", task: GanttTask{Start: 2, End: 1}},
+		{name: "negative category", task: GanttTask{Category: -1}},
+		{name: "missing dependency", task: GanttTask{DependsOn: []int{1}}},
+	} {
+		if _, err := NewGantt([]GanttTask{test.task}, 10); err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+	if _, err := NewGantt(nil, 10); err != ErrNoData {
+		t.Errorf("unexpected error for no tasks: got:%v want:%v", err, ErrNoData)
+	}
+}
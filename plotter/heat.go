@@ -5,8 +5,10 @@
 package plotter
 
 import (
+	"image"
 	"image/color"
 	"math"
+	"sort"
 
 	"github.com/gonum/plot"
 	"github.com/gonum/plot/palette"
@@ -52,6 +54,22 @@ type HeatMap struct {
 	// Min and Max define the dynamic range of the
 	// heat map.
 	Min, Max float64
+
+	// Raster specifies whether the heat map is drawn
+	// as a single image instead of a filled rectangle
+	// for each grid cell. Drawing an image is much
+	// faster for large grids and avoids seams between
+	// the cells. The image has a pixel for each cell
+	// when the cells are evenly spaced on the canvas,
+	// and is otherwise resampled to the resolution of
+	// the canvas.
+	Raster bool
+
+	// Interpolation specifies how the image is
+	// resampled when Raster is true. Bilinear
+	// interpolation gives smooth transitions
+	// between the colors of neighboring cells.
+	Interpolation vg.Interpolation
 }
 
 // NewHeatMap creates as new heat map plotter for the given data,
@@ -96,6 +114,10 @@ func (h *HeatMap) Plot(c draw.Canvas, plt *plot.Plot) {
 	if len(pal) == 0 {
 		panic("heatmap: empty palette")
 	}
+	if h.Raster {
+		h.plotRaster(c, plt, pal)
+		return
+	}
 	// ps scales the palette uniformly across the data range.
 	ps := float64(len(pal)-1) / (h.Max - h.Min)
 
@@ -152,16 +174,7 @@ func (h *HeatMap) Plot(c draw.Canvas, plt *plot.Plot) {
 			pa.Line(x, dy)
 			pa.Close()
 
-			var col color.Color
-			switch {
-			case v < h.Min:
-				col = h.Underflow
-			case v > h.Max:
-				col = h.Overflow
-			default:
-				col = pal[int((v-h.Min)*ps+0.5)] // Apply palette scaling.
-			}
-			if col != nil {
+			if col := h.color(v, pal, ps); col != nil {
 				c.SetColor(col)
 				c.Fill(pa)
 			}
@@ -169,6 +182,107 @@ func (h *HeatMap) Plot(c draw.Canvas, plt *plot.Plot) {
 	}
 }
 
+// plotRaster draws the heat map as a single image.
+func (h *HeatMap) plotRaster(c draw.Canvas, plt *plot.Plot, pal []color.Color) {
+	cols, rows := h.GridXYZ.Dims()
+	if cols == 0 || rows == 0 {
+		return
+	}
+	ps := float64(len(pal)-1) / (h.Max - h.Min)
+
+	trX, trY := plt.Transforms(&c)
+	xs := cellEdges(cols, h.GridXYZ.X, trX)
+	ys := cellEdges(rows, h.GridXYZ.Y, trY)
+	sx, sy := spans(xs, c.ContainsX), spans(ys, c.ContainsY)
+	if sx == nil || sy == nil {
+		return
+	}
+	xs = xs[sx[0][0] : sx[len(sx)-1][1]+1]
+	ys = ys[sy[0][0] : sy[len(sy)-1][1]+1]
+
+	// Use a pixel for each cell when the cells are
+	// evenly spaced and otherwise a pixel for each
+	// dot of the canvas.
+	left, right := xs[0], xs[len(xs)-1]
+	if right < left {
+		left, right = right, left
+	}
+	bottom, top := ys[0], ys[len(ys)-1]
+	if top < bottom {
+		bottom, top = top, bottom
+	}
+	nx, ny := len(xs)-1, len(ys)-1
+	if len(sx) > 1 {
+		nx = int(math.Ceil((right - left).Dots(c)))
+	}
+	if len(sy) > 1 {
+		ny = int(math.Ceil((top - bottom).Dots(c)))
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, nx, ny))
+	for p := 0; p < nx; p++ {
+		i := sx[0][0] + cellIndex(xs, left+(vg.Length(p)+0.5)*(right-left)/vg.Length(nx))
+		for q := 0; q < ny; q++ {
+			j := sy[0][0] + cellIndex(ys, top-(vg.Length(q)+0.5)*(top-bottom)/vg.Length(ny))
+			v := h.GridXYZ.Z(i, j)
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+			if col := h.color(v, pal, ps); col != nil {
+				img.Set(p, q, col)
+			}
+		}
+	}
+	c.DrawImage(left, bottom, right-left, top-bottom, img, h.Interpolation)
+}
+
+// color returns the color used to draw the value v
+// with the palette colors pal scaled by ps.
+func (h *HeatMap) color(v float64, pal []color.Color, ps float64) color.Color {
+	switch {
+	case v < h.Min:
+		return h.Underflow
+	case v > h.Max:
+		return h.Overflow
+	default:
+		return pal[int((v-h.Min)*ps+0.5)] // Apply palette scaling.
+	}
+}
+
+// cellEdges returns the edges between n grid cells
+// centered at the coordinates given by pos,
+// transformed to the canvas by tr.
+func cellEdges(n int, pos func(int) float64, tr func(float64) vg.Length) []vg.Length {
+	e := make([]vg.Length, n+1)
+	if n == 1 {
+		e[0], e[1] = tr(pos(0)-0.5), tr(pos(0)+0.5)
+		return e
+	}
+	e[0] = tr(pos(0) - (pos(1)-pos(0))/2)
+	for i := 1; i < n; i++ {
+		e[i] = tr((pos(i-1) + pos(i)) / 2)
+	}
+	e[n] = tr(pos(n-1) + (pos(n-1)-pos(n-2))/2)
+	return e
+}
+
+// cellIndex returns the index of the cell between
+// the monotonic edges that contains x.
+func cellIndex(edges []vg.Length, x vg.Length) int {
+	n := len(edges) - 1
+	inc := edges[n] > edges[0]
+	i := sort.Search(n, func(i int) bool {
+		if inc {
+			return edges[i+1] > x
+		}
+		return edges[i+1] < x
+	})
+	if i == n {
+		i--
+	}
+	return i
+}
+
 // DataRange implements the DataRange method
 // of the plot.DataRanger interface.
 func (h *HeatMap) DataRange() (xmin, xmax, ymin, ymax float64) {
@@ -179,7 +293,7 @@ func (h *HeatMap) DataRange() (xmin, xmax, ymin, ymax float64) {
 		xmin = -0.5
 	default:
 		xmax = (3*h.GridXYZ.X(c-1) - h.GridXYZ.X(c-2)) / 2
-		xmin = (h.GridXYZ.X(0) - h.GridXYZ.X(1)) / 2
+		xmin = (3*h.GridXYZ.X(0) - h.GridXYZ.X(1)) / 2
 	}
 	switch r {
 	case 1: // Make a unit length when there is no neighbour.
@@ -187,11 +301,33 @@ func (h *HeatMap) DataRange() (xmin, xmax, ymin, ymax float64) {
 		ymin = -0.5
 	default:
 		ymax = (3*h.GridXYZ.Y(r-1) - h.GridXYZ.Y(r-2)) / 2
-		ymin = (h.GridXYZ.Y(0) - h.GridXYZ.Y(1)) / 2
+		ymin = (3*h.GridXYZ.Y(0) - h.GridXYZ.Y(1)) / 2
 	}
 	return xmin, xmax, ymin, ymax
 }
 
+// Pick returns the index of the cell nearest to pt,
+// implementing the plot.Picker interface.  The cell in
+// column c and row r of the grid has the index c*rows+r,
+// where rows is the number of rows of the grid.
+func (h *HeatMap) Pick(c draw.Canvas, plt *plot.Plot, pt draw.Point, tol vg.Length) (int, vg.Length, bool) {
+	cols, rows := h.GridXYZ.Dims()
+	if cols == 0 || rows == 0 {
+		return 0, 0, false
+	}
+	trX, trY := plt.Transforms(&c)
+	xs := cellEdges(cols, h.GridXYZ.X, trX)
+	ys := cellEdges(rows, h.GridXYZ.Y, trY)
+	i, j := cellIndex(xs, pt.X), cellIndex(ys, pt.Y)
+	pk := picker{tol: tol}
+	pk.add(i*rows+j, rectDistance(
+		draw.Point{xs[i], ys[j]},
+		draw.Point{xs[i+1], ys[j+1]},
+		pt,
+	))
+	return pk.result()
+}
+
 // GlyphBoxes implements the GlyphBoxes method
 // of the plot.GlyphBoxer interface.
 func (h *HeatMap) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
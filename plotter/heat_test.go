@@ -0,0 +1,160 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/gonum/matrix/mat64"
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/palette"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/recorder"
+)
+
+// irregularGrid is a grid with arbitrary column and row coordinates.
+type irregularGrid struct {
+	xs, ys []float64
+	z      mat64.Matrix
+}
+
+func (g irregularGrid) Dims() (c, r int)   { return len(g.xs), len(g.ys) }
+func (g irregularGrid) Z(c, r int) float64 { return g.z.At(r, c) }
+func (g irregularGrid) X(c int) float64    { return g.xs[c] }
+func (g irregularGrid) Y(r int) float64    { return g.ys[r] }
+
+// rasterHeatMap returns the image drawn by the heat map h in a
+// 100x100 point recording.
+func rasterHeatMap(t *testing.T, h *HeatMap) *recorder.DrawImage {
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.HideAxes()
+	p.BackgroundColor = nil
+	p.X.Padding = 0
+	p.Y.Padding = 0
+	p.Add(h)
+
+	r := recorder.New(72)
+	p.Draw(draw.NewCanvas(r, 100, 100))
+	var img *recorder.DrawImage
+	for _, a := range r.Actions {
+		switch a := a.(type) {
+		case *recorder.DrawImage:
+			if img != nil {
+				t.Fatal("heat map drawn with more than one image")
+			}
+			img = a
+		case *recorder.Fill:
+			t.Fatal("unexpected fill in raster heat map")
+		}
+	}
+	if img == nil {
+		t.Fatal("heat map not drawn as an image")
+	}
+	return img
+}
+
+func TestHeatMapRaster(t *testing.T) {
+	pal := palette.Heat(12, 1).Colors()
+	m := irregularGrid{
+		xs: []float64{0, 1, 2, 3},
+		ys: []float64{0, 1, 2},
+		z: mat64.NewDense(3, 4, []float64{
+			1, 2, 3, 4,
+			5, math.NaN(), 7, 8,
+			9, 10, 11, 12,
+		}),
+	}
+	h := NewHeatMap(m, palette.Heat(12, 1))
+	h.Raster = true
+	h.Interpolation = vg.Bilinear
+
+	d := rasterHeatMap(t, h)
+	if d.Interpolation != vg.Bilinear {
+		t.Errorf("unexpected interpolation: got:%v want:%v", d.Interpolation, vg.Bilinear)
+	}
+	if d.X != 0 || d.Y != 0 || d.Width != 100 || d.Height != 100 {
+		t.Errorf("unexpected image placement: got:(%v, %v, %v, %v) want:(0, 0, 100, 100)",
+			d.X, d.Y, d.Width, d.Height)
+	}
+	if b := d.Image.Bounds(); b != image.Rect(0, 0, 4, 3) {
+		t.Fatalf("unexpected image bounds: got:%v want:%v", b, image.Rect(0, 0, 4, 3))
+	}
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 3; j++ {
+			got := color.NRGBAModel.Convert(d.Image.At(i, 2-j))
+			v := m.Z(i, j)
+			var want color.Color = color.NRGBA{}
+			if !math.IsNaN(v) {
+				want = color.NRGBAModel.Convert(pal[int(v)-1])
+			}
+			if got != want {
+				t.Errorf("unexpected color for cell (%d, %d): got:%v want:%v", i, j, got, want)
+			}
+		}
+	}
+
+	// Unevenly spaced columns are resampled
+	// to the resolution of the canvas.
+	m.xs = []float64{1, 2, 4, 8}
+	h.GridXYZ = m
+	d = rasterHeatMap(t, h)
+	if b := d.Image.Bounds(); b != image.Rect(0, 0, 100, 3) {
+		t.Fatalf("unexpected resampled image bounds: got:%v want:%v", b, image.Rect(0, 0, 100, 3))
+	}
+	// The columns span 0.5 to 10 in the data space, so the first
+	// cell ends at 1.5 and the last starts at 6.
+	for _, test := range []struct {
+		x, col int
+	}{
+		{x: 0, col: 0},
+		{x: 9, col: 0},
+		{x: 11, col: 1},
+		{x: 47, col: 2},
+		{x: 58, col: 3},
+		{x: 99, col: 3},
+	} {
+		got := color.NRGBAModel.Convert(d.Image.At(test.x, 0))
+		want := color.NRGBAModel.Convert(pal[int(m.Z(test.col, 2))-1])
+		if got != want {
+			t.Errorf("unexpected color at pixel %d: got:%v want:%v", test.x, got, want)
+		}
+	}
+}
+
+func TestHeatMapDataRange(t *testing.T) {
+	for _, test := range []struct {
+		xs, ys                 []float64
+		xmin, xmax, ymin, ymax float64
+	}{
+		{
+			xs: []float64{0, 1, 2}, ys: []float64{0, 1},
+			xmin: -0.5, xmax: 2.5, ymin: -0.5, ymax: 1.5,
+		},
+		{
+			// The range must follow the grid away from the origin.
+			xs: []float64{10, 20, 30}, ys: []float64{-4, -2},
+			xmin: 5, xmax: 35, ymin: -5, ymax: -1,
+		},
+		{
+			xs: []float64{1, 2, 4}, ys: []float64{0, 3, 4},
+			xmin: 0.5, xmax: 5, ymin: -1.5, ymax: 4.5,
+		},
+	} {
+		z := mat64.NewDense(len(test.ys), len(test.xs), nil)
+		h := NewHeatMap(irregularGrid{xs: test.xs, ys: test.ys, z: z}, palette.Heat(4, 1))
+		xmin, xmax, ymin, ymax := h.DataRange()
+		if xmin != test.xmin || xmax != test.xmax || ymin != test.ymin || ymax != test.ymax {
+			t.Errorf("unexpected data range for grid %v by %v: got:[%v, %v]x[%v, %v] want:[%v, %v]x[%v, %v]",
+				test.xs, test.ys, xmin, xmax, ymin, ymax, test.xmin, test.xmax, test.ymin, test.ymax)
+		}
+	}
+}
@@ -6,11 +6,12 @@ package plotter
 
 import (
 	"errors"
-	"fmt"
 	"image/color"
 	"math"
+	"sort"
 
 	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
 	"github.com/gonum/plot/vg/draw"
 )
 
@@ -20,7 +21,8 @@ type Histogram struct {
 	// Bins is the set of bins for this histogram.
 	Bins []HistogramBin
 
-	// Width is the width of each bin.
+	// Width is the width of each bin, or zero if
+	// the bins are not of equal width.
 	Width float64
 
 	// FillColor is the color used to fill each
@@ -31,25 +33,110 @@ type Histogram struct {
 	// LineStyle is the style of the outline of each
 	// bar of the histogram.
 	draw.LineStyle
+
+	// Step specifies whether the histogram is drawn
+	// as a single stepped outline around all of its
+	// bins instead of as a bar for each bin.
+	Step bool
+
+	// stackedOn is the histogram upon which
+	// this histogram is stacked.
+	stackedOn *Histogram
 }
 
 // NewHistogram returns a new histogram
 // that represents the distribution of values
-// using the given number of bins.
+// using the given number of bins of equal width.
 //
 // Each y value is assumed to be the frequency
 // count for the corresponding x.
 //
-// If the number of bins is non-positive than
-// a reasonable default is used.
+// An error is returned if the number of bins is
+// not positive.  NewHistogramBins can be used
+// with a BinCount or a bin rule to choose the
+// number of bins automatically.  Samples with
+// missing values are left out, as in
+// NewHistogramBins.
 func NewHistogram(xy XYer, n int) (*Histogram, error) {
 	if n <= 0 {
 		return nil, errors.New("Histogram with non-positive number of bins")
 	}
-	bins, width := binPoints(xy, n)
+	return NewHistogramBins(xy, BinCount(n))
+}
+
+// NewHistogramBins returns a new histogram that
+// represents the distribution of the weighted
+// samples in xy using the bins returned by b.
+// Each y value is the weight of the corresponding
+// x value.  Samples outside of the bins are
+// ignored.
+//
+// Samples with a missing value or weight are left
+// out.  Data can be checked for missing values first
+// with CopyXYsMissing and MissingError.
+//
+// An error is returned if there are no samples,
+// if a sample is infinite, or if the bins cannot
+// be made.
+func NewHistogramBins(xy XYer, b Binner) (*Histogram, error) {
+	xy, err := skipMissing(xy)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := b.Edges(xy)
+	if err != nil {
+		return nil, err
+	}
+	return newHistogram(xy, edges)
+}
+
+// NewHistograms returns histograms of several
+// series of weighted samples, as in NewHistogramBins,
+// with the same bins returned by b for the samples
+// of all of the series.  The histograms can be
+// overlaid, or stacked using StackOn.
+func NewHistograms(b Binner, xys ...XYer) ([]*Histogram, error) {
+	xys = append([]XYer(nil), xys...)
+	for i, xy := range xys {
+		var err error
+		if xys[i], err = skipMissing(xy); err != nil {
+			return nil, err
+		}
+	}
+	edges, err := b.Edges(concatXYs(xys))
+	if err != nil {
+		return nil, err
+	}
+	hs := make([]*Histogram, len(xys))
+	for i, xy := range xys {
+		hs[i], err = newHistogram(xy, edges)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return hs, nil
+}
+
+// skipMissing returns the samples of xy without
+// those with a missing value or weight.
+func skipMissing(xy XYer) (XYer, error) {
+	if !hasMissing(xy) {
+		return xy, nil
+	}
+	return CopyXYsMissing(xy, MissingSkip)
+}
+
+// newHistogram returns a new histogram of the
+// weighted samples in xy using the bins with
+// the given edges.
+func newHistogram(xy XYer, edges []float64) (*Histogram, error) {
+	bins, err := binPoints(xy, edges)
+	if err != nil {
+		return nil, err
+	}
 	return &Histogram{
 		Bins:      bins,
-		Width:     width,
+		Width:     binWidth(edges),
 		FillColor: color.Gray{128},
 		LineStyle: DefaultLineStyle,
 	}, nil
@@ -70,55 +157,224 @@ func (u unitYs) XY(i int) (float64, float64) {
 	return u.Value(i), 1.0
 }
 
+// concatXYs is the concatenation of XYers.
+type concatXYs []XYer
+
+func (xys concatXYs) Len() int {
+	var n int
+	for _, xy := range xys {
+		n += xy.Len()
+	}
+	return n
+}
+
+func (xys concatXYs) XY(i int) (float64, float64) {
+	for _, xy := range xys {
+		if i < xy.Len() {
+			return xy.XY(i)
+		}
+		i -= xy.Len()
+	}
+	panic("plotter: index out of range")
+}
+
+// StackOn stacks the histogram on top of another
+// histogram, so that each bin is drawn above the
+// corresponding bin of the other histogram.  An
+// error is returned if the histograms do not have
+// the same bins.
+func (h *Histogram) StackOn(on *Histogram) error {
+	if len(h.Bins) != len(on.Bins) {
+		return errors.New("Stacked histograms have different bins")
+	}
+	for i, bin := range h.Bins {
+		if bin.Min != on.Bins[i].Min || bin.Max != on.Bins[i].Max {
+			return errors.New("Stacked histograms have different bins")
+		}
+	}
+	h.stackedOn = on
+	return nil
+}
+
+// binTop returns the top of the ith bin,
+// taking into account any histograms upon
+// which it is stacked.
+func (h *Histogram) binTop(i int) float64 {
+	if h == nil {
+		return 0
+	}
+	return h.Bins[i].Weight + h.stackedOn.binTop(i)
+}
+
 // Plot implements the Plotter interface, drawing a line
 // that connects each point in the Line.
 func (h *Histogram) Plot(c draw.Canvas, p *plot.Plot) {
 	trX, trY := p.Transforms(&c)
+	if h.Step {
+		h.plotStep(c, trX, trY)
+		return
+	}
 
-	for _, bin := range h.Bins {
+	for i, bin := range h.Bins {
+		bottom := h.stackedOn.binTop(i)
+		top := bottom + bin.Weight
 		pts := []draw.Point{
-			{trX(bin.Min), trY(0)},
-			{trX(bin.Max), trY(0)},
-			{trX(bin.Max), trY(bin.Weight)},
-			{trX(bin.Min), trY(bin.Weight)},
+			{trX(bin.Min), trY(bottom)},
+			{trX(bin.Max), trY(bottom)},
+			{trX(bin.Max), trY(top)},
+			{trX(bin.Min), trY(top)},
 		}
 		if h.FillColor != nil {
 			c.FillPolygon(h.FillColor, c.ClipPolygonXY(pts))
 		}
-		pts = append(pts, draw.Point{trX(bin.Min), trY(0)})
+		pts = append(pts, pts[0])
 		c.StrokeLines(h.LineStyle, c.ClipLinesXY(pts)...)
 	}
 }
 
+// plotStep draws the histogram as a stepped
+// outline around all of its bins.
+func (h *Histogram) plotStep(c draw.Canvas, trX, trY func(float64) vg.Length) {
+	n := len(h.Bins)
+	if n == 0 {
+		return
+	}
+	outline := make([]draw.Point, 0, 2*n+2)
+	outline = append(outline, draw.Point{trX(h.Bins[0].Min), trY(h.stackedOn.binTop(0))})
+	for i, bin := range h.Bins {
+		top := trY(h.binTop(i))
+		outline = append(outline, draw.Point{trX(bin.Min), top}, draw.Point{trX(bin.Max), top})
+	}
+	outline = append(outline, draw.Point{trX(h.Bins[n-1].Max), trY(h.stackedOn.binTop(n - 1))})
+
+	if h.FillColor != nil {
+		poly := append([]draw.Point(nil), outline...)
+		for i := n - 1; i >= 0; i-- {
+			bottom := trY(h.stackedOn.binTop(i))
+			poly = append(poly, draw.Point{trX(h.Bins[i].Max), bottom}, draw.Point{trX(h.Bins[i].Min), bottom})
+		}
+		c.FillPolygon(h.FillColor, c.ClipPolygonXY(poly))
+	}
+	c.StrokeLines(h.LineStyle, c.ClipLinesXY(outline)...)
+}
+
 // DataRange returns the minimum and maximum X and Y values
 func (h *Histogram) DataRange() (xmin, xmax, ymin, ymax float64) {
 	xmin = math.Inf(1)
 	xmax = math.Inf(-1)
 	ymax = math.Inf(-1)
-	for _, bin := range h.Bins {
+	for i, bin := range h.Bins {
 		if bin.Max > xmax {
 			xmax = bin.Max
 		}
 		if bin.Min < xmin {
 			xmin = bin.Min
 		}
-		if bin.Weight > ymax {
-			ymax = bin.Weight
-		}
+		bottom := h.stackedOn.binTop(i)
+		top := bottom + bin.Weight
+		ymin = math.Min(ymin, math.Min(bottom, top))
+		ymax = math.Max(ymax, math.Max(bottom, top))
 	}
 	return
 }
 
+// Pick returns the index of the bin nearest to pt,
+// implementing the plot.Picker interface.
+func (h *Histogram) Pick(c draw.Canvas, plt *plot.Plot, pt draw.Point, tol vg.Length) (int, vg.Length, bool) {
+	trX, trY := plt.Transforms(&c)
+	pk := picker{tol: tol}
+	for i, bin := range h.Bins {
+		bottom := h.stackedOn.binTop(i)
+		pk.add(i, rectDistance(
+			draw.Point{trX(bin.Min), trY(bottom)},
+			draw.Point{trX(bin.Max), trY(bottom + bin.Weight)},
+			pt,
+		))
+	}
+	return pk.result()
+}
+
 // Normalize normalizes the histogram so that the
 // total area beneath it sums to a given value.
+// The height of each bin is made proportional
+// to its weight divided by its width.
 func (h *Histogram) Normalize(sum float64) {
 	mass := 0.0
 	for _, b := range h.Bins {
 		mass += b.Weight
 	}
-	for i := range h.Bins {
-		h.Bins[i].Weight *= sum / (h.Width * mass)
+	for i, b := range h.Bins {
+		h.Bins[i].Weight *= sum / ((b.Max - b.Min) * mass)
+	}
+}
+
+// Normalization specifies how the bin weights of
+// a histogram are scaled by NormalizeBy.
+type Normalization int
+
+const (
+	// NormCount leaves each bin weight as the
+	// total weight of the samples in the bin.
+	NormCount Normalization = iota
+
+	// NormProbability scales the bin weights to
+	// the fractions of the total weight in each
+	// bin, which sum to one.
+	NormProbability
+
+	// NormDensity scales the bin weights so that
+	// the total area of the bins is one, estimating
+	// the probability density of the samples.
+	NormDensity
+
+	// NormCumulative sets each bin weight to the
+	// fraction of the total weight in the bin and
+	// all of the bins below it.
+	NormCumulative
+
+	// NormComplementaryCumulative sets each bin
+	// weight to the fraction of the total weight in
+	// the bin and all of the bins above it.
+	NormComplementaryCumulative
+)
+
+// NormalizeBy scales the bin weights of a histogram
+// of sample weights as specified by n.  The weights
+// are not changed if they sum to zero.  An error is
+// returned if n is not a known normalization.
+func (h *Histogram) NormalizeBy(n Normalization) error {
+	if n < NormCount || n > NormComplementaryCumulative {
+		return errors.New("Unknown histogram normalization")
+	}
+	total := 0.0
+	for _, b := range h.Bins {
+		total += b.Weight
+	}
+	if total == 0 {
+		return nil
+	}
+	switch n {
+	case NormCount:
+	case NormProbability:
+		for i := range h.Bins {
+			h.Bins[i].Weight /= total
+		}
+	case NormDensity:
+		h.Normalize(1)
+	case NormCumulative:
+		cum := 0.0
+		for i := range h.Bins {
+			cum += h.Bins[i].Weight
+			h.Bins[i].Weight = cum / total
+		}
+	case NormComplementaryCumulative:
+		cum := 0.0
+		for i := len(h.Bins) - 1; i >= 0; i-- {
+			cum += h.Bins[i].Weight
+			h.Bins[i].Weight = cum / total
+		}
 	}
+	return nil
 }
 
 // Thumbnail draws a rectangle in the given style of the histogram.
@@ -141,49 +397,50 @@ func (h *Histogram) Thumbnail(c *draw.Canvas) {
 	c.StrokeLines(h.LineStyle, c.ClipLinesXY(pts)...)
 }
 
-// binPoints returns a slice containing the
-// given number of bins, and the width of
-// each bin.
-//
-// If the given number of bins is not positive
-// then a reasonable default is used.  The
-// default is the square root of the sum of
-// the y values.
-func binPoints(xys XYer, n int) ([]HistogramBin, float64) {
-	xmin, xmax := Range(XValues{xys})
-	if n <= 0 {
-		m := 0.0
-		for i := 0; i < xys.Len(); i++ {
-			_, y := xys.XY(i)
-			m += math.Max(y, 1.0)
-		}
-		n = int(math.Ceil(math.Sqrt(m)))
+// binPoints returns the bins with the given
+// edges containing the weighted samples of xys.
+// Samples outside of the bins are ignored.  The
+// last bin includes its upper edge.
+func binPoints(xys XYer, edges []float64) ([]HistogramBin, error) {
+	if err := checkEdges(edges); err != nil {
+		return nil, err
 	}
-	if n < 1 || xmax <= xmin {
-		n = 1
-	}
-
+	n := len(edges) - 1
 	bins := make([]HistogramBin, n)
-
-	w := (xmax - xmin) / float64(n)
 	for i := range bins {
-		bins[i].Min = xmin + float64(i)*w
-		bins[i].Max = xmin + float64(i+1)*w
+		bins[i].Min = edges[i]
+		bins[i].Max = edges[i+1]
 	}
 
 	for i := 0; i < xys.Len(); i++ {
 		x, y := xys.XY(i)
-		bin := int((x - xmin) / w)
-		if x == xmax {
-			bin = n - 1
+		if err := CheckFloats(x, y); err != nil {
+			return nil, err
+		}
+		if x < edges[0] || x > edges[n] {
+			continue
 		}
-		if bin < 0 || bin >= n {
-			panic(fmt.Sprintf("%g, xmin=%g, xmax=%g, w=%g, bin=%d, n=%d\n",
-				x, xmin, xmax, w, bin, n))
+		bin := sort.Search(n, func(j int) bool { return x < edges[j+1] })
+		if bin == n {
+			bin = n - 1
 		}
 		bins[bin].Weight += y
 	}
-	return bins, w
+	return bins, nil
+}
+
+// binWidth returns the width of the bins with
+// the given edges, or zero if the bins are not
+// of equal width.
+func binWidth(edges []float64) float64 {
+	n := len(edges) - 1
+	w := (edges[n] - edges[0]) / float64(n)
+	for i := 0; i < n; i++ {
+		if math.Abs(edges[i+1]-edges[i]-w) > 1e-9*w {
+			return 0
+		}
+	}
+	return w
 }
 
 // A HistogramBin approximates the number of values
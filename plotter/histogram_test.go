@@ -0,0 +1,237 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/recorder"
+)
+
+func TestBinners(t *testing.T) {
+	uniform := make(XYs, 100)
+	for i := range uniform {
+		uniform[i] = struct{ X, Y float64 }{float64(i), 1}
+	}
+	for _, test := range []struct {
+		binner Binner
+		bins   int
+	}{
+		{binner: BinCount(4), bins: 4},
+		{binner: BinCount(0), bins: 10},
+		{binner: SturgesBins{}, bins: 8},
+		{binner: ScottBins{}, bins: 5},
+		{binner: FreedmanDiaconisBins{}, bins: 5},
+		{binner: DoaneBins{}, bins: 8},
+	} {
+		e, err := test.binner.Edges(uniform)
+		if err != nil {
+			t.Errorf("unexpected error for %T: %v", test.binner, err)
+			continue
+		}
+		if len(e) != test.bins+1 {
+			t.Errorf("unexpected number of bins for %T: got:%d want:%d", test.binner, len(e)-1, test.bins)
+		}
+		if e[0] != 0 || e[len(e)-1] != 99 {
+			t.Errorf("unexpected range of bins for %T: got:[%v,%v] want:[0,99]", test.binner, e[0], e[len(e)-1])
+		}
+
+		// Weights must be equivalent to repeated samples.
+		weighted := XYs{{0, 2}, {1, 1}, {3, 3}, {10, 2}}
+		repeated := XYs{{0, 1}, {0, 1}, {1, 1}, {3, 1}, {3, 1}, {3, 1}, {10, 1}, {10, 1}}
+		we, err := test.binner.Edges(weighted)
+		if err != nil {
+			t.Errorf("unexpected error for %T: %v", test.binner, err)
+			continue
+		}
+		re, err := test.binner.Edges(repeated)
+		if err != nil {
+			t.Errorf("unexpected error for %T: %v", test.binner, err)
+			continue
+		}
+		if !reflect.DeepEqual(we, re) {
+			t.Errorf("unexpected edges of weighted samples for %T: got:%v want:%v", test.binner, we, re)
+		}
+	}
+}
+
+func TestBinnersOutlier(t *testing.T) {
+	xys := make(XYs, 1001)
+	for i := 0; i < 1000; i++ {
+		xys[i] = struct{ X, Y float64 }{float64(i) / 1000, 1}
+	}
+	xys[1000] = struct{ X, Y float64 }{1e9, 1}
+	for _, b := range []Binner{ScottBins{}, FreedmanDiaconisBins{}} {
+		e, err := b.Edges(xys)
+		if err != nil {
+			t.Errorf("unexpected error for %T: %v", b, err)
+			continue
+		}
+		if len(e)-1 > len(xys) {
+			t.Errorf("too many bins for %T: got:%d want at most:%d", b, len(e)-1, len(xys))
+		}
+		if e[0] != 0 || e[len(e)-1] != 1e9 {
+			t.Errorf("unexpected range of bins for %T: got:[%v,%v] want:[0,1e9]", b, e[0], e[len(e)-1])
+		}
+	}
+	if _, err := NewHistogramBins(xys, FreedmanDiaconisBins{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLogBins(t *testing.T) {
+	e, err := LogBins(3).Edges(XYs{{1, 1}, {10, 1}, {1000, 1}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []float64{1, 10, 100, 1000}
+	for i := range want {
+		if math.Abs(e[i]-want[i]) > 1e-12*want[i] {
+			t.Errorf("unexpected edges: got:%v want:%v", e, want)
+			break
+		}
+	}
+	if _, err = LogBins(3).Edges(XYs{{0, 1}, {10, 1}}); err == nil {
+		t.Error("expected error for logarithmic bins of zero")
+	}
+}
+
+func TestNewHistogramBins(t *testing.T) {
+	h, err := NewHistogramBins(XYs{{-1, 1}, {0, 1}, {0.5, 2}, {1, 3}, {3, 4}, {5, 5}}, BinEdges{0, 1, 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []HistogramBin{{Min: 0, Max: 1, Weight: 3}, {Min: 1, Max: 3, Weight: 7}}
+	if !reflect.DeepEqual(h.Bins, want) {
+		t.Errorf("unexpected bins: got:%v want:%v", h.Bins, want)
+	}
+	if h.Width != 0 {
+		t.Errorf("unexpected width of variable bins: got:%v want:0", h.Width)
+	}
+
+	for _, test := range []struct {
+		name   string
+		xys    XYs
+		binner Binner
+	}{
+		{name: "no samples", xys: XYs{}, binner: SturgesBins{}},
+		{name: "infinite sample", xys: XYs{{0, 1}, {math.Inf(1), 1}}, binner: BinCount(2)},
+		{name: "only missing samples", xys: XYs{{math.NaN(), 1}}, binner: BinCount(2)},
+		{name: "infinite weight", xys: XYs{{0, math.Inf(1)}}, binner: BinEdges{0, 1}},
+		{name: "one edge", xys: XYs{{0, 1}}, binner: BinEdges{0}},
+		{name: "decreasing edges", xys: XYs{{0, 1}}, binner: BinEdges{0, 2, 1}},
+	} {
+		if _, err := NewHistogramBins(test.xys, test.binner); err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+
+	h, err = NewHist(Values{2, 2, 2}, 3)
+	if err != nil {
+		t.Fatalf("unexpected error for constant samples: %v", err)
+	}
+	if h.Bins[0].Min != 1.5 || h.Bins[2].Max != 2.5 || h.Bins[1].Weight != 3 {
+		t.Errorf("unexpected bins of constant samples: %v", h.Bins)
+	}
+}
+
+func TestNormalizeBy(t *testing.T) {
+	for _, test := range []struct {
+		norm Normalization
+		want []float64
+	}{
+		{norm: NormCount, want: []float64{1, 3, 4}},
+		{norm: NormProbability, want: []float64{0.125, 0.375, 0.5}},
+		{norm: NormDensity, want: []float64{0.125, 0.1875, 0.5}},
+		{norm: NormCumulative, want: []float64{0.125, 0.5, 1}},
+		{norm: NormComplementaryCumulative, want: []float64{1, 0.875, 0.5}},
+	} {
+		h, err := NewHistogramBins(XYs{{0, 1}, {2, 3}, {3.5, 4}}, BinEdges{0, 1, 3, 4})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := h.NormalizeBy(test.norm); err != nil {
+			t.Fatalf("unexpected error for normalization %d: %v", test.norm, err)
+		}
+		for i, b := range h.Bins {
+			if b.Weight != test.want[i] {
+				t.Errorf("unexpected weight of bin %d for normalization %d: got:%v want:%v", i, test.norm, b.Weight, test.want[i])
+			}
+		}
+	}
+
+	h, err := NewHistogramBins(XYs{{0, 1}}, BinEdges{0, 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := h.NormalizeBy(NormComplementaryCumulative + 1); err == nil {
+		t.Error("expected error for unknown normalization")
+	}
+	if h.Bins[0].Weight != 1 {
+		t.Errorf("weight changed by unknown normalization: got:%v want:1", h.Bins[0].Weight)
+	}
+}
+
+func TestHistogramStack(t *testing.T) {
+	hs, err := NewHistograms(BinCount(2), XYs{{0, 1}, {1, 1}}, XYs{{2, 2}, {3, 3}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hs[0].Bins[0].Min != 0 || hs[1].Bins[1].Max != 3 {
+		t.Errorf("histograms do not share bins: %v %v", hs[0].Bins, hs[1].Bins)
+	}
+	if err = hs[1].StackOn(hs[0]); err != nil {
+		t.Fatalf("unexpected error stacking histograms: %v", err)
+	}
+	_, _, ymin, ymax := hs[1].DataRange()
+	if ymin != 0 || ymax != 5 {
+		t.Errorf("unexpected y range of stacked histogram: got:[%v,%v] want:[0,5]", ymin, ymax)
+	}
+
+	other, err := NewHistogram(XYs{{0, 1}, {1, 1}}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = other.StackOn(hs[0]); err == nil {
+		t.Error("expected error stacking histograms with different bins")
+	}
+}
+
+func TestHistogramStep(t *testing.T) {
+	h, err := NewHist(Values{0, 1, 1, 2, 2, 2}, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h.Step = true
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.Add(h)
+	r := recorder.New(72)
+	c := p.DataCanvas(draw.NewCanvas(r, 100, 100))
+	r.Reset()
+	h.Plot(c, p)
+
+	var fills, strokes int
+	for _, a := range r.Actions {
+		switch a := a.(type) {
+		case *recorder.Fill:
+			fills++
+		case *recorder.Stroke:
+			strokes++
+			if len(a.Path) != 8 {
+				t.Errorf("unexpected number of outline points: got:%d want:8", len(a.Path))
+			}
+		}
+	}
+	if fills != 1 || strokes != 1 {
+		t.Errorf("unexpected step histogram drawing: got %d fills and %d strokes, want 1 of each", fills, strokes)
+	}
+}
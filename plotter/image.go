@@ -0,0 +1,178 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"image"
+	"math"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+)
+
+// Image is a plotter that draws a raster image
+// stretched over a rectangle of the data space.
+type Image struct {
+	img        image.Image
+	cols, rows int
+	xmin, xmax float64
+	ymin, ymax float64
+
+	// Interpolation specifies how the pixels of
+	// the image are resampled when it is drawn.
+	Interpolation vg.Interpolation
+}
+
+// NewImage creates a new image plotter that draws img
+// with its lower left corner at (xmin, ymin) and its
+// upper right corner at (xmax, ymax) in the data space.
+// The pixels of the image are placed according to the
+// scales of the plot's axes, so each pixel covers an
+// equal range of data values.
+func NewImage(img image.Image, xmin, ymin, xmax, ymax float64) *Image {
+	b := img.Bounds()
+	return &Image{
+		img:  img,
+		cols: b.Dx(),
+		rows: b.Dy(),
+		xmin: xmin,
+		xmax: xmax,
+		ymin: ymin,
+		ymax: ymax,
+	}
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+// Pixels that do not lie within the draw area are not drawn.
+func (img *Image) Plot(c draw.Canvas, plt *plot.Plot) {
+	if img.cols == 0 || img.rows == 0 {
+		return
+	}
+	trX, trY := plt.Transforms(&c)
+
+	// The edges of the pixel columns from left to
+	// right and the pixel rows from top to bottom.
+	xs := make([]vg.Length, img.cols+1)
+	for i := range xs {
+		xs[i] = trX(img.xmin + float64(i)*(img.xmax-img.xmin)/float64(img.cols))
+	}
+	ys := make([]vg.Length, img.rows+1)
+	for j := range ys {
+		ys[j] = trY(img.ymax - float64(j)*(img.ymax-img.ymin)/float64(img.rows))
+	}
+
+	sx, sy := spans(xs, c.ContainsX), spans(ys, c.ContainsY)
+	if sx == nil || sy == nil {
+		return
+	}
+	if len(sx) == 1 && len(sy) == 1 {
+		cs, rs := sx[0], sy[0]
+		sub := subImage(img.img, image.Rect(cs[0], rs[0], cs[1], rs[1]))
+		c.DrawImage(xs[cs[0]], ys[rs[1]], xs[cs[1]]-xs[cs[0]], ys[rs[0]]-ys[rs[1]], sub, img.Interpolation)
+		return
+	}
+	xs = xs[sx[0][0] : sx[len(sx)-1][1]+1]
+	ys = ys[sy[0][0] : sy[len(sy)-1][1]+1]
+
+	// The pixels are not evenly spaced on the canvas,
+	// so resample the image with a pixel for each dot
+	// of the canvas along the uneven axes.
+	left, right := xs[0], xs[len(xs)-1]
+	if right < left {
+		left, right = right, left
+	}
+	bottom, top := ys[len(ys)-1], ys[0]
+	if top < bottom {
+		bottom, top = top, bottom
+	}
+	nx, ny := len(xs)-1, len(ys)-1
+	if len(sx) > 1 {
+		nx = int(math.Ceil((right - left).Dots(c)))
+	}
+	if len(sy) > 1 {
+		ny = int(math.Ceil((top - bottom).Dots(c)))
+	}
+
+	b := img.img.Bounds()
+	dst := image.NewNRGBA64(image.Rect(0, 0, nx, ny))
+	for p := 0; p < nx; p++ {
+		i := sx[0][0] + cellIndex(xs, left+(vg.Length(p)+0.5)*(right-left)/vg.Length(nx))
+		for q := 0; q < ny; q++ {
+			j := sy[0][0] + cellIndex(ys, top-(vg.Length(q)+0.5)*(top-bottom)/vg.Length(ny))
+			dst.Set(p, q, img.img.At(b.Min.X+i, b.Min.Y+j))
+		}
+	}
+	c.DrawImage(left, bottom, right-left, top-bottom, dst, img.Interpolation)
+}
+
+// spans returns the ranges of pixels, given by the indices
+// of their edges, that are drawn together. The pixels with
+// both edges contained in the draw area are drawn, in a
+// single span if the edges are evenly spaced, which is the
+// case for a linear axis scale, and in spans of a single
+// pixel otherwise, in which case the pixels must be
+// resampled to be drawn as a single image.
+func spans(edges []vg.Length, contains func(vg.Length) bool) [][2]int {
+	first, last := -1, -1
+	for i, e := range edges {
+		if contains(e) {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 || first == last {
+		return nil
+	}
+
+	n := last - first
+	step := (edges[last] - edges[first]) / vg.Length(n)
+	tol := 1e-6 * math.Abs(float64(edges[last]-edges[first]))
+	even := true
+	for i := first; i <= last; i++ {
+		if math.Abs(float64(edges[i]-edges[first]-vg.Length(i-first)*step)) > tol {
+			even = false
+			break
+		}
+	}
+	if even {
+		return [][2]int{{first, last}}
+	}
+	s := make([][2]int, n)
+	for i := range s {
+		s[i] = [2]int{first + i, first + i + 1}
+	}
+	return s
+}
+
+// subImage returns the part of img in the rectangle r
+// given relative to the top left corner of the image.
+func subImage(img image.Image, r image.Rectangle) image.Image {
+	b := img.Bounds()
+	r = r.Add(b.Min)
+	if r == b {
+		return img
+	}
+	if s, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return s.SubImage(r)
+	}
+	dst := image.NewNRGBA64(image.Rect(0, 0, r.Dx(), r.Dy()))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			dst.Set(x-r.Min.X, y-r.Min.Y, img.At(x, y))
+		}
+	}
+	return dst
+}
+
+// DataRange implements the DataRange method
+// of the plot.DataRanger interface.
+func (img *Image) DataRange() (xmin, xmax, ymin, ymax float64) {
+	return img.xmin, img.xmax, img.ymin, img.ymax
+}
@@ -0,0 +1,94 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/recorder"
+)
+
+func TestSpans(t *testing.T) {
+	contains := func(x vg.Length) bool { return 0 <= x && x <= 10 }
+	for _, test := range []struct {
+		edges []vg.Length
+		want  [][2]int
+	}{
+		{edges: []vg.Length{0, 2, 4, 6}, want: [][2]int{{0, 3}}},
+		{edges: []vg.Length{-2, 0, 2, 4, 6, 8, 10, 12}, want: [][2]int{{1, 6}}},
+		{edges: []vg.Length{0, 1, 3, 7}, want: [][2]int{{0, 1}, {1, 2}, {2, 3}}},
+		{edges: []vg.Length{-3, 1, 3, 7, 15}, want: [][2]int{{1, 2}, {2, 3}}},
+		{edges: []vg.Length{-2, 4, 12}, want: nil},
+		{edges: []vg.Length{11, 12}, want: nil},
+	} {
+		got := spans(test.edges, contains)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected spans for %v: got:%v want:%v", test.edges, got, test.want)
+		}
+	}
+}
+
+func TestImagePlot(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	img.Set(0, 0, color.NRGBA{R: 0xff, A: 0xff})
+
+	for _, scale := range []plot.Normalizer{plot.LinearScale{}, plot.LogScale{}} {
+		p, err := plot.New()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		p.HideAxes()
+		p.X.Scale = scale
+		p.Add(NewImage(img, 1, 1, 4, 3))
+
+		r := recorder.New(72)
+		p.Draw(draw.NewCanvas(r, 100, 100))
+		var got []*recorder.DrawImage
+		for _, a := range r.Actions {
+			if d, ok := a.(*recorder.DrawImage); ok {
+				got = append(got, d)
+			}
+		}
+		if len(got) != 1 {
+			t.Fatalf("unexpected number of images for %T: got:%d want:1", scale, len(got))
+		}
+		d := got[0]
+		b := d.Image.Bounds()
+		cols := 3
+		if _, ok := scale.(plot.LogScale); ok {
+			cols = int(math.Ceil(float64(d.Width)))
+		}
+		if b.Dx() != cols || b.Dy() != 2 {
+			t.Errorf("unexpected image bounds for %T: got:%v want:%dx2", scale, b, cols)
+		}
+
+		// The first column spans 1 to 2 of 1 to 4 in the
+		// data space, which is a third of the image on a
+		// linear scale and half of it on a log scale.
+		want := cols / 3
+		if _, ok := scale.(plot.LogScale); ok {
+			want = int(math.Floor(float64(d.Width) / 2))
+		}
+		var red int
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if color.NRGBAModel.Convert(d.Image.At(x, b.Min.Y)) == (color.NRGBA{R: 0xff, A: 0xff}) {
+				red++
+			}
+		}
+		if red < want || red > want+1 {
+			t.Errorf("unexpected number of red pixels in top row for %T: got:%d want:%d", scale, red, want)
+		}
+	}
+}
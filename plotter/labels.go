@@ -26,7 +26,9 @@ type Labels struct {
 	XYs
 
 	// Labels is the set of labels corresponding
-	// to each point.
+	// to each point.  Labels may contain markup
+	// as described for draw.TextStyle if the
+	// Markup field of TextStyle is set.
 	Labels []string
 
 	// TextStyle is the style of the label text.
@@ -6,6 +6,7 @@ package plotter
 
 import (
 	"image/color"
+	"math"
 
 	"github.com/gonum/plot"
 	"github.com/gonum/plot/vg"
@@ -15,20 +16,37 @@ import (
 // Line implements the Plotter interface, drawing a line.
 type Line struct {
 	// XYs is a copy of the points for this line.
+	// XYs is nil for a line returned by NewLineRef.
 	XYs
 
+	// ref is the caller's data for a line
+	// returned by NewLineRef.
+	ref XYer
+
 	// LineStyle is the style of the line connecting
 	// the points.
 	draw.LineStyle
 
 	// ShadeColor is the color of the shaded area.
 	ShadeColor *color.Color
+
+	// Downsampling is the method by which the
+	// points are reduced to those needed to draw
+	// the line at the resolution of the canvas.
+	Downsampling Downsampling
+
+	// Missing is the policy for points of the line
+	// with missing values.  By default the line is
+	// broken at missing points.
+	Missing MissingPolicy
 }
 
 // NewLine returns a Line that uses the default line style and
-// does not draw glyphs.
+// does not draw glyphs.  Points with missing values are
+// kept, and are handled by the Missing policy of the line
+// when it is drawn.
 func NewLine(xys XYer) (*Line, error) {
-	data, err := CopyXYs(xys)
+	data, err := CopyXYsMissing(xys, MissingBreak)
 	if err != nil {
 		return nil, err
 	}
@@ -38,15 +56,71 @@ func NewLine(xys XYer) (*Line, error) {
 	}, nil
 }
 
+// NewLineRef returns a Line of the points of xys that
+// uses the default line style.  Unlike NewLine, the
+// points are not copied, and the line refers to xys
+// when it is drawn, so xys must not be changed while
+// the line is in use, unless xys has a Snapshot method
+// returning XYs, like RingXYs, in which case the points
+// are copied with it each time the line is drawn.  An
+// error is returned if one of the points is infinite.
+func NewLineRef(xys XYer) (*Line, error) {
+	if err := checkXYs(xys); err != nil {
+		return nil, err
+	}
+	return &Line{
+		ref:       xys,
+		LineStyle: DefaultLineStyle,
+	}, nil
+}
+
+// Len returns the number of points of the line.
+func (pts *Line) Len() int {
+	if pts.ref != nil {
+		return pts.ref.Len()
+	}
+	return len(pts.XYs)
+}
+
+// XY returns the ith point of the line.
+func (pts *Line) XY(i int) (x, y float64) {
+	if pts.ref != nil {
+		return pts.ref.XY(i)
+	}
+	return pts.XYs.XY(i)
+}
+
+// data returns the points of the line, copied by
+// their Snapshot method if the line refers to
+// points that have one.
+func (pts *Line) data() XYer {
+	if pts.ref != nil {
+		return snapshot(pts.ref)
+	}
+	return pts.XYs
+}
+
 // Plot draws the Line, implementing the plot.Plotter
 // interface.
 func (pts *Line) Plot(c draw.Canvas, plt *plot.Plot) {
-	trX, trY := plt.Transforms(&c)
-	ps := make([]draw.Point, len(pts.XYs))
+	for _, run := range missingRuns(pts.data(), pts.Missing) {
+		pts.plotRun(c, plt, run)
+	}
+}
 
-	for i, p := range pts.XYs {
-		ps[i].X = trX(p.X)
-		ps[i].Y = trY(p.Y)
+// plotRun draws the points of a run of the line
+// without missing points.
+func (pts *Line) plotRun(c draw.Canvas, plt *plot.Plot, data XYer) {
+	trX, trY := plt.Transforms(&c)
+	var ps []draw.Point
+	switch pts.Downsampling {
+	case MinMaxDownsampling:
+		ps = minMaxPoints(data, trX, trY, c.Min.X, pixelWidth(c))
+	case LTTBDownsampling:
+		columns := int(math.Ceil(float64(c.Size().X / pixelWidth(c))))
+		ps = lttb(transformXYs(data, trX, trY), 2*columns)
+	default:
+		ps = transformXYs(data, trX, trY)
 	}
 
 	if pts.ShadeColor != nil && len(ps) > 0 {
@@ -54,10 +128,10 @@ func (pts *Line) Plot(c draw.Canvas, plt *plot.Plot) {
 		minY := trY(plt.Y.Min)
 		var pa vg.Path
 		pa.Move(ps[0].X, minY)
-		for i := range pts.XYs {
+		for i := range ps {
 			pa.Line(ps[i].X, ps[i].Y)
 		}
-		pa.Line(ps[len(pts.XYs)-1].X, minY)
+		pa.Line(ps[len(ps)-1].X, minY)
 		pa.Close()
 		c.Fill(pa)
 	}
@@ -69,7 +143,13 @@ func (pts *Line) Plot(c draw.Canvas, plt *plot.Plot) {
 // x and y values, implementing the plot.DataRanger
 // interface.
 func (pts *Line) DataRange() (xmin, xmax, ymin, ymax float64) {
-	return XYRange(pts)
+	return XYRange(pts.data())
+}
+
+// Pick returns the index of the point nearest to pt,
+// implementing the plot.Picker interface.
+func (pts *Line) Pick(c draw.Canvas, plt *plot.Plot, pt draw.Point, tol vg.Length) (int, vg.Length, bool) {
+	return pickXYs(c, plt, pts.data(), pt, tol)
 }
 
 // Thumbnail the thumbnail for the Line,
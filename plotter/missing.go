@@ -0,0 +1,215 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import "math"
+
+// MissingPolicy specifies how missing data, which are
+// values that are NaN, are handled.  Line, Function and
+// Scatter have a Missing field holding the policy used
+// when they are drawn.  The other plotters keep or leave
+// out missing values as described for their constructors,
+// and their data can be handled by a policy before they
+// are made, with CopyXYsMissing or CopyValuesMissing.
+// Plotters accept missing values, but never infinite
+// values.
+type MissingPolicy int
+
+const (
+	// MissingBreak draws nothing for missing values,
+	// so that a line is broken at them.  It is the
+	// policy of plotters whose Missing field is unset.
+	MissingBreak MissingPolicy = iota
+
+	// MissingSkip leaves out points with missing
+	// values, so that a line joins the points on
+	// either side of them.
+	MissingSkip
+
+	// MissingInterpolate replaces missing values by
+	// linear interpolation between the values on either
+	// side of them, by x value for points and by index
+	// for values.  Points with missing x values are left
+	// out, and missing values without a value on one
+	// side are kept as for MissingBreak.
+	MissingInterpolate
+
+	// MissingError rejects missing values.  CopyXYsMissing
+	// and CopyValuesMissing return ErrNaN for them, so that
+	// data can be checked before a plotter is made from
+	// them.  Plotters whose Missing field is MissingError
+	// draw missing values as for MissingBreak, since their
+	// data have already been accepted.
+	MissingError
+)
+
+// isMissing returns whether any of the values is NaN.
+func isMissing(fs ...float64) bool {
+	for _, f := range fs {
+		if math.IsNaN(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkInfinite returns ErrInfinity if any
+// of the values is infinite.
+func checkInfinite(fs ...float64) error {
+	for _, f := range fs {
+		if math.IsInf(f, 0) {
+			return ErrInfinity
+		}
+	}
+	return nil
+}
+
+// CopyValuesMissing returns a Values that is a copy of the
+// values from a Valuer with the missing values handled by
+// the policy.  MissingSkip and MissingBreak both keep
+// missing values, so that the index of each value is
+// unchanged.  The values of a BarChart may be interpolated
+// with it before the BarChart is made.  An error is
+// returned if there are no values, if one of the values
+// is infinite, or if one of the values is missing and the
+// policy is MissingError.
+func CopyValuesMissing(vs Valuer, m MissingPolicy) (Values, error) {
+	if vs.Len() == 0 {
+		return nil, ErrNoData
+	}
+	cpy := make(Values, vs.Len())
+	for i := range cpy {
+		cpy[i] = vs.Value(i)
+		if err := checkInfinite(cpy[i]); err != nil {
+			return nil, err
+		}
+		if m == MissingError && isMissing(cpy[i]) {
+			return nil, ErrNaN
+		}
+	}
+	if m == MissingInterpolate {
+		interpolate(cpy, func(i int) float64 { return float64(i) })
+	}
+	return cpy, nil
+}
+
+// CopyXYsMissing returns an XYs that is a copy of the x and
+// y values from an XYer with the missing values handled by
+// the policy.  An error is returned if one of the values is
+// infinite, or if one of the values is missing and the
+// policy is MissingError.
+func CopyXYsMissing(data XYer, m MissingPolicy) (XYs, error) {
+	cpy := make(XYs, 0, data.Len())
+	for i, n := 0, data.Len(); i < n; i++ {
+		x, y := data.XY(i)
+		if err := checkInfinite(x, y); err != nil {
+			return nil, err
+		}
+		switch {
+		case m == MissingError && isMissing(x, y):
+			return nil, ErrNaN
+		case m == MissingSkip && isMissing(x, y):
+			continue
+		case m == MissingInterpolate && isMissing(x):
+			continue
+		}
+		cpy = append(cpy, struct{ X, Y float64 }{x, y})
+	}
+	if m == MissingInterpolate {
+		ys := make([]float64, len(cpy))
+		for i, p := range cpy {
+			ys[i] = p.Y
+		}
+		interpolate(ys, func(i int) float64 { return cpy[i].X })
+		for i := range cpy {
+			cpy[i].Y = ys[i]
+		}
+	}
+	return cpy, nil
+}
+
+// interpolate replaces each run of NaN values of vs that
+// has values on both sides by linear interpolation between
+// them at the positions given by at.
+func interpolate(vs []float64, at func(i int) float64) {
+	prev := -1
+	for i, v := range vs {
+		if math.IsNaN(v) {
+			continue
+		}
+		if prev >= 0 && i > prev+1 {
+			x0, x1 := at(prev), at(i)
+			for j := prev + 1; j < i; j++ {
+				f := 0.5
+				if x1 != x0 {
+					f = (at(j) - x0) / (x1 - x0)
+				}
+				vs[j] = vs[prev] + f*(v-vs[prev])
+			}
+		}
+		prev = i
+	}
+}
+
+// xySpan is the points i through j-1 of an XYer.
+type xySpan struct {
+	XYer
+	i, j int
+}
+
+func (s xySpan) Len() int {
+	return s.j - s.i
+}
+
+func (s xySpan) XY(i int) (x, y float64) {
+	return s.XYer.XY(s.i + i)
+}
+
+// hasMissing returns whether any of the points
+// of data has a missing value.
+func hasMissing(data XYer) bool {
+	for i, n := 0, data.Len(); i < n; i++ {
+		if isMissing(data.XY(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// missingRuns returns the runs of points of data that
+// are between missing points, after the missing points
+// are handled by the policy.  The only run is data
+// itself if none of its points are missing.
+func missingRuns(data XYer, m MissingPolicy) []XYer {
+	if !hasMissing(data) {
+		return []XYer{data}
+	}
+	if m == MissingInterpolate {
+		if cpy, err := CopyXYsMissing(data, m); err == nil {
+			data = cpy
+		}
+	}
+	var (
+		runs  []XYer
+		start int
+	)
+	n := data.Len()
+	for i := 0; i < n; i++ {
+		if !isMissing(data.XY(i)) {
+			continue
+		}
+		if i > start {
+			runs = append(runs, xySpan{data, start, i})
+		}
+		start = i + 1
+	}
+	if n > start {
+		runs = append(runs, xySpan{data, start, n})
+	}
+	if m == MissingSkip && len(runs) > 1 {
+		return []XYer{concatXYs(runs)}
+	}
+	return runs
+}
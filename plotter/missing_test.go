@@ -0,0 +1,292 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/recorder"
+)
+
+var nan = math.NaN()
+
+// sameXYs returns whether a and b are equal,
+// treating NaNs as equal.
+func sameXYs(a, b XYs) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !sameFloat(a[i].X, b[i].X) || !sameFloat(a[i].Y, b[i].Y) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameFloat(a, b float64) bool {
+	return a == b || (math.IsNaN(a) && math.IsNaN(b))
+}
+
+func TestCopyXYsMissing(t *testing.T) {
+	data := XYs{{0, nan}, {1, 1}, {2, nan}, {4, 7}, {nan, 6}, {5, nan}}
+	for _, test := range []struct {
+		policy MissingPolicy
+		want   XYs
+		err    error
+	}{
+		{policy: MissingSkip, want: XYs{{1, 1}, {4, 7}}},
+		{policy: MissingBreak, want: data},
+		{policy: MissingInterpolate, want: XYs{{0, nan}, {1, 1}, {2, 3}, {4, 7}, {5, nan}}},
+		{policy: MissingError, err: ErrNaN},
+	} {
+		got, err := CopyXYsMissing(data, test.policy)
+		if err != test.err {
+			t.Errorf("unexpected error for policy %d: got:%v want:%v", test.policy, err, test.err)
+			continue
+		}
+		if !sameXYs(got, test.want) {
+			t.Errorf("unexpected points for policy %d: got:%v want:%v", test.policy, got, test.want)
+		}
+	}
+	if _, err := CopyXYsMissing(XYs{{0, math.Inf(1)}}, MissingSkip); err != ErrInfinity {
+		t.Errorf("unexpected error for infinite point: got:%v want:%v", err, ErrInfinity)
+	}
+
+	got, err := CopyValuesMissing(Values{nan, 1, nan, nan, 4}, MissingInterpolate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Values{nan, 1, 2, 3, 4}
+	for i := range want {
+		if !sameFloat(got[i], want[i]) {
+			t.Errorf("unexpected interpolated values: got:%v want:%v", got, want)
+			break
+		}
+	}
+	if _, err := CopyValuesMissing(Values{1, nan}, MissingError); err != ErrNaN {
+		t.Errorf("unexpected error for missing value: got:%v want:%v", err, ErrNaN)
+	}
+	if _, err := CopyValuesMissing(Values{1, 2}, MissingError); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if min, max := Range(Values{nan, 3, -1, nan}); min != -1 || max != 3 {
+		t.Errorf("unexpected range: got:%v,%v want:-1,3", min, max)
+	}
+}
+
+// strokes returns the stroked paths and the
+// number of fills recorded by r.
+func strokes(r *recorder.Canvas) (paths []int, fills int) {
+	for _, a := range r.Actions {
+		switch a := a.(type) {
+		case *recorder.Stroke:
+			paths = append(paths, len(a.Path))
+		case *recorder.Fill:
+			fills++
+		}
+	}
+	return paths, fills
+}
+
+// drawPlotter draws the plotter alone on a plot,
+// returning the actions of the plotter.
+func drawPlotter(t *testing.T, p plot.Plotter) *recorder.Canvas {
+	plt, err := plot.New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plt.Add(p)
+	r, _ := drawOn(plt, p)
+	return r
+}
+
+// drawOn draws the plotter on the data canvas of
+// the plot, to which it has been added, returning
+// the actions of the plotter and the data canvas.
+func drawOn(plt *plot.Plot, p plot.Plotter) (*recorder.Canvas, draw.Canvas) {
+	r := recorder.New(72)
+	c := plt.DataCanvas(draw.NewCanvas(r, 300, 300))
+	r.Reset()
+	p.Plot(c, plt)
+	return r, c
+}
+
+func TestLineMissing(t *testing.T) {
+	data := XYs{{0, 0}, {1, 1}, {2, nan}, {3, 3}, {4, 4}, {5, 5}}
+	if _, err := NewLine(XYs{{0, math.Inf(1)}}); err != ErrInfinity {
+		t.Errorf("unexpected error: got:%v want:%v", err, ErrInfinity)
+	}
+	for _, test := range []struct {
+		policy MissingPolicy
+		ref    bool
+		want   []int
+	}{
+		{policy: MissingBreak, want: []int{2, 3}},
+		{policy: MissingSkip, want: []int{5}},
+		{policy: MissingInterpolate, want: []int{6}},
+		{policy: MissingError, want: []int{2, 3}},
+		{policy: MissingBreak, ref: true, want: []int{2, 3}},
+		{policy: MissingSkip, ref: true, want: []int{5}},
+		{policy: MissingInterpolate, ref: true, want: []int{6}},
+	} {
+		newLine := NewLine
+		if test.ref {
+			newLine = NewLineRef
+		}
+		l, err := newLine(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		l.Missing = test.policy
+		l.ShadeColor = &DefaultLineStyle.Color
+		got, fills := strokes(drawPlotter(t, l))
+		if !reflect.DeepEqual(got, test.want) || fills != len(test.want) {
+			t.Errorf("unexpected strokes for policy %d ref=%t: got:%v with %d fills want:%v",
+				test.policy, test.ref, got, fills, test.want)
+		}
+		if xmin, xmax, ymin, ymax := l.DataRange(); xmin != 0 || xmax != 5 || ymin != 0 || ymax != 5 {
+			t.Errorf("unexpected data range: got:%v,%v,%v,%v want:0,5,0,5", xmin, xmax, ymin, ymax)
+		}
+	}
+
+	f := NewFunction(func(x float64) float64 {
+		if x > 0.4 && x < 0.6 {
+			return nan
+		}
+		return x
+	})
+	f.Samples = 11
+	plt, err := plot.New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plt.X.Min, plt.X.Max = 0, 1
+	plt.Y.Min, plt.Y.Max = 0, 1
+	for _, test := range []struct {
+		policy MissingPolicy
+		want   []int
+	}{
+		{policy: MissingBreak, want: []int{5, 5}},
+		{policy: MissingSkip, want: []int{10}},
+		{policy: MissingInterpolate, want: []int{10}},
+		{policy: MissingError, want: []int{5, 5}},
+	} {
+		f.Missing = test.policy
+		r, _ := drawOn(plt, f)
+		if got, _ := strokes(r); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected function strokes for policy %d: got:%v want:%v", test.policy, got, test.want)
+		}
+	}
+}
+
+func TestScatterMissing(t *testing.T) {
+	data := XYs{{0, 0}, {1, nan}, {2, 2}}
+	s, err := NewScatter(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, test := range []struct {
+		policy MissingPolicy
+		want   int
+	}{
+		{policy: MissingBreak, want: 2},
+		{policy: MissingSkip, want: 2},
+		{policy: MissingInterpolate, want: 3},
+		{policy: MissingError, want: 2},
+	} {
+		s.Missing = test.policy
+		if got, _ := strokes(drawPlotter(t, s)); len(got) != test.want {
+			t.Errorf("unexpected number of glyphs for policy %d: got:%d want:%d", test.policy, len(got), test.want)
+		}
+	}
+	s.Missing = MissingBreak
+
+	plt, err := plot.New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plt.Add(s)
+	c := draw.NewCanvas(recorder.New(72), 300, 300)
+	plt.Draw(c)
+	dc := plt.DataCanvas(c)
+	trX, trY := plt.Transforms(&dc)
+	if i, _, ok := s.Pick(dc, plt, draw.Point{trX(1), trY(1)}, 1000); !ok || i == 1 {
+		t.Errorf("unexpected pick: got:%d,%t", i, ok)
+	}
+}
+
+func TestBarChartMissing(t *testing.T) {
+	b, err := NewBarChart(Values{1, nan, -2}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b.ValueLabels = LabelsAbove
+	if _, _, ymin, ymax := b.DataRange(); ymin != -2 || ymax != 1 {
+		t.Errorf("unexpected data range: got:%v,%v want:-2,1", ymin, ymax)
+	}
+	top, err := NewBarChart(Values{1, 1, 1}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	top.StackOn(b)
+	if got := top.BarHeight(1); got != 1 {
+		t.Errorf("unexpected height stacked on missing bar: got:%v want:1", got)
+	}
+	r := drawPlotter(t, b)
+	var labels int
+	for _, a := range r.Actions {
+		if _, ok := a.(*recorder.FillString); ok {
+			labels++
+		}
+	}
+	if _, fills := strokes(r); fills != 2 || labels != 2 {
+		t.Errorf("unexpected bars: got %d bars and %d labels want 2 and 2", fills, labels)
+	}
+}
+
+func TestHistogramMissing(t *testing.T) {
+	data := Values{1, nan, 2, 3, nan}
+	h, err := NewHist(data, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var total float64
+	for _, b := range h.Bins {
+		total += b.Weight
+	}
+	if total != 3 {
+		t.Errorf("unexpected total weight: got:%v want:3", total)
+	}
+	hs, err := NewHistograms(BinCount(2), XYs{{1, 1}, {nan, 1}}, XYs{{3, nan}, {2, 1}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hs[0].Bins[0].Weight != 1 || hs[1].Bins[1].Weight != 1 {
+		t.Errorf("unexpected bins: got:%v and %v", hs[0].Bins, hs[1].Bins)
+	}
+	if _, err := NewHist(Values{nan}, 2); err != ErrNoData {
+		t.Errorf("unexpected error: got:%v want:%v", err, ErrNoData)
+	}
+}
+
+func TestBoxPlotMissing(t *testing.T) {
+	data := Values{nan, 1, 2, nan, 3, 4, 5}
+	b, err := NewBoxPlot(10, 0, data, WithWeights(Values{1, 1, 1, 1, 1, 1, 1}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.Median != 3 || b.Min != 1 || b.Max != 5 || len(b.Values) != 5 || len(b.Weights) != 5 {
+		t.Errorf("unexpected statistics: got median:%v min:%v max:%v n:%d", b.Median, b.Min, b.Max, len(b.Values))
+	}
+	if _, err := NewBoxPlot(10, 0, Values{nan}); err != ErrNoData {
+		t.Errorf("unexpected error: got:%v want:%v", err, ErrNoData)
+	}
+}
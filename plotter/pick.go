@@ -0,0 +1,86 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"math"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+)
+
+// picker finds the item nearest to a point
+// within a tolerance.
+type picker struct {
+	tol  vg.Length
+	i    int
+	dist vg.Length
+	ok   bool
+}
+
+// add considers the item i at the distance d.
+// Earlier items are kept over equally near
+// later items.
+func (p *picker) add(i int, d vg.Length) {
+	if math.IsNaN(float64(d)) || d > p.tol || (p.ok && d >= p.dist) {
+		return
+	}
+	p.i, p.dist, p.ok = i, d, true
+}
+
+// result returns the index and distance of the
+// nearest item, implementing plot.Picker.
+func (p *picker) result() (i int, dist vg.Length, ok bool) {
+	return p.i, p.dist, p.ok
+}
+
+// pickXYs returns the index of the point of xys
+// nearest to pt after transforming to the canvas.
+func pickXYs(c draw.Canvas, plt *plot.Plot, xys XYer, pt draw.Point, tol vg.Length) (int, vg.Length, bool) {
+	trX, trY := plt.Transforms(&c)
+	pk := picker{tol: tol}
+	for i, n := 0, xys.Len(); i < n; i++ {
+		x, y := xys.XY(i)
+		dx, dy := trX(x)-pt.X, trY(y)-pt.Y
+		pk.add(i, vg.Length(math.Hypot(float64(dx), float64(dy))))
+	}
+	return pk.result()
+}
+
+// pickPoints returns the index of the canvas
+// point of pts nearest to pt.
+func pickPoints(pts []draw.Point, pt draw.Point, tol vg.Length) (int, vg.Length, bool) {
+	pk := picker{tol: tol}
+	for i, p := range pts {
+		dx, dy := p.X-pt.X, p.Y-pt.Y
+		pk.add(i, vg.Length(math.Hypot(float64(dx), float64(dy))))
+	}
+	return pk.result()
+}
+
+// rectDistance returns the distance between pt and the
+// rectangle with the opposite corners a and b, or zero
+// if pt is inside the rectangle.
+func rectDistance(a, b, pt draw.Point) vg.Length {
+	dx := gap(pt.X, a.X, b.X)
+	dy := gap(pt.Y, a.Y, b.Y)
+	return vg.Length(math.Hypot(float64(dx), float64(dy)))
+}
+
+// gap returns the distance between x and
+// the interval between a and b.
+func gap(x, a, b vg.Length) vg.Length {
+	if a > b {
+		a, b = b, a
+	}
+	switch {
+	case x < a:
+		return a - x
+	case x > b:
+		return x - b
+	}
+	return 0
+}
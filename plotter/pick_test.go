@@ -0,0 +1,70 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"testing"
+
+	"github.com/gonum/matrix/mat64"
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/palette"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/recorder"
+)
+
+func TestPick(t *testing.T) {
+	bars, err := NewBarChart(Values{1, 3, 2}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hist, err := NewHist(Values{0, 0.5, 1, 1.5, 3, 3.5}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	heat := NewHeatMap(irregularGrid{
+		xs: []float64{0, 1, 3},
+		ys: []float64{0, 1},
+		z:  mat64.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}),
+	}, palette.Heat(4, 1))
+
+	for _, test := range []struct {
+		name   string
+		picker plot.Picker
+		x, y   float64
+		dx, dy vg.Length
+		index  int
+		ok     bool
+	}{
+		{name: "bar", picker: bars, x: 1, y: 2, index: 1, ok: true},
+		{name: "bar beside", picker: bars, x: 2, y: 1, dx: 8, index: 2, ok: true},
+		{name: "bar above", picker: bars, x: 2, y: 3, ok: false},
+		{name: "bin", picker: hist, x: 0.5, y: 1, index: 0, ok: true},
+		{name: "bin", picker: hist, x: 3, y: 2, index: 1, ok: true},
+		{name: "bin above", picker: hist, x: 3, y: 5, ok: false},
+		{name: "cell", picker: heat, x: 0, y: 0, index: 0, ok: true},
+		{name: "cell", picker: heat, x: 2.1, y: 0.8, index: 5, ok: true},
+		{name: "cell below", picker: heat, x: 1, y: -0.5, dy: -2, index: 2, ok: true},
+		{name: "cell far below", picker: heat, x: 1, y: -0.5, dy: -20, ok: false},
+	} {
+		p, err := plot.New()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		p.Add(test.picker.(plot.Plotter))
+		c := p.DataCanvas(draw.NewCanvas(recorder.New(72), 300, 300))
+		trX, trY := p.Transforms(&c)
+		pt := draw.Point{X: trX(test.x) + test.dx, Y: trY(test.y) + test.dy}
+
+		i, _, ok := test.picker.Pick(c, p, pt, 5)
+		if ok != test.ok {
+			t.Errorf("unexpected %s pick result at (%v,%v): got:%t want:%t", test.name, test.x, test.y, ok, test.ok)
+			continue
+		}
+		if ok && i != test.index {
+			t.Errorf("unexpected %s picked at (%v,%v): got:%d want:%d", test.name, test.x, test.y, i, test.index)
+		}
+	}
+}
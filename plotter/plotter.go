@@ -54,11 +54,15 @@ type Valuer interface {
 }
 
 // Range returns the minimum and maximum values.
+// Missing values, which are NaN, are ignored.
 func Range(vs Valuer) (min, max float64) {
 	min = math.Inf(1)
 	max = math.Inf(-1)
 	for i := 0; i < vs.Len(); i++ {
 		v := vs.Value(i)
+		if math.IsNaN(v) {
+			continue
+		}
 		min = math.Min(min, v)
 		max = math.Max(max, v)
 	}
@@ -146,6 +150,17 @@ func CopyXYs(data XYer) (XYs, error) {
 	return cpy, nil
 }
 
+// checkXYs returns an error if one of the
+// data points contains an Infinity.
+func checkXYs(data XYer) error {
+	for i, n := 0, data.Len(); i < n; i++ {
+		if err := checkInfinite(data.XY(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (xys XYs) Len() int {
 	return len(xys)
 }
@@ -0,0 +1,202 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"sort"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+)
+
+// ProbPlot implements the Plotter interface, drawing
+// a quantile-quantile (Q-Q) or probability-probability
+// (P-P) plot as a glyph at each point and a straight
+// reference line.  Points near the reference line show
+// that the compared distributions agree.
+type ProbPlot struct {
+	// XYs is a copy of the points of the plot.
+	XYs
+
+	// GlyphStyle is the style of the glyphs drawn
+	// at each point.
+	draw.GlyphStyle
+
+	// Slope and Intercept define the reference
+	// line y = Slope·x + Intercept.
+	Slope, Intercept float64
+
+	// Reference is the style of the reference line.
+	// If its Color is nil then the reference line is
+	// not drawn.
+	Reference draw.LineStyle
+}
+
+// NewQQ returns a Q-Q plot of the quantiles of the
+// sample values, as y values, against the quantiles
+// of the distribution d.  The reference line passes
+// through the points of the first and third quartiles.
+func NewQQ(vs Valuer, d Distribution) (*ProbPlot, error) {
+	ys, err := sortedValues(vs)
+	if err != nil {
+		return nil, err
+	}
+	xys := make(XYs, len(ys))
+	for i, y := range ys {
+		xys[i].X = d.Quantile(plottingPosition(i, len(ys)))
+		xys[i].Y = y
+		if err := CheckFloats(xys[i].X); err != nil {
+			return nil, err
+		}
+	}
+	p := newProbPlot(xys)
+	p.fitQuartiles(d.Quantile, quantileFunc(ys, nil, HazenQuantiles))
+	return p, nil
+}
+
+// NewQQSamples returns a Q-Q plot of the quantiles
+// of the sample values ys against the quantiles of
+// the sample values xs.  A point is plotted for each
+// value of the smaller sample, with the quantiles of
+// the larger sample interpolated.  The reference line
+// passes through the points of the first and third
+// quartiles.
+func NewQQSamples(xs, ys Valuer) (*ProbPlot, error) {
+	sx, err := sortedValues(xs)
+	if err != nil {
+		return nil, err
+	}
+	sy, err := sortedValues(ys)
+	if err != nil {
+		return nil, err
+	}
+	n := len(sx)
+	if len(sy) < n {
+		n = len(sy)
+	}
+	qx := quantileFunc(sx, nil, HazenQuantiles)
+	qy := quantileFunc(sy, nil, HazenQuantiles)
+	xys := make(XYs, n)
+	for i := range xys {
+		q := plottingPosition(i, n)
+		xys[i].X = qx(q)
+		xys[i].Y = qy(q)
+	}
+	p := newProbPlot(xys)
+	p.fitQuartiles(qx, qy)
+	return p, nil
+}
+
+// NewPP returns a P-P plot of the empirical cumulative
+// probabilities of the sample values, as y values,
+// against the probabilities given by the cumulative
+// distribution function of d.  The reference line is
+// y = x.
+func NewPP(vs Valuer, d Distribution) (*ProbPlot, error) {
+	vals, err := sortedValues(vs)
+	if err != nil {
+		return nil, err
+	}
+	xys := make(XYs, len(vals))
+	for i, v := range vals {
+		xys[i].X = d.CDF(v)
+		xys[i].Y = plottingPosition(i, len(vals))
+		if err := CheckFloats(xys[i].X); err != nil {
+			return nil, err
+		}
+	}
+	p := newProbPlot(xys)
+	p.Slope = 1
+	return p, nil
+}
+
+// newProbPlot returns a ProbPlot of the points
+// that uses the default glyph and line styles.
+func newProbPlot(xys XYs) *ProbPlot {
+	return &ProbPlot{
+		XYs:        xys,
+		GlyphStyle: DefaultGlyphStyle,
+		Reference:  DefaultLineStyle,
+	}
+}
+
+// fitQuartiles sets the reference line to pass through
+// the first and third quartiles given by the quantile
+// functions qx and qy, or to y = x if the quartiles of
+// qx are equal.
+func (p *ProbPlot) fitQuartiles(qx, qy func(float64) float64) {
+	x1, x3 := qx(0.25), qx(0.75)
+	y1, y3 := qy(0.25), qy(0.75)
+	if x1 == x3 {
+		p.Slope, p.Intercept = 1, 0
+		return
+	}
+	p.Slope = (y3 - y1) / (x3 - x1)
+	p.Intercept = y1 - p.Slope*x1
+}
+
+// Plot draws the ProbPlot, implementing the plot.Plotter
+// interface.
+func (p *ProbPlot) Plot(c draw.Canvas, plt *plot.Plot) {
+	trX, trY := plt.Transforms(&c)
+	if p.Reference.Color != nil {
+		line := []draw.Point{
+			{trX(plt.X.Min), trY(p.Slope*plt.X.Min + p.Intercept)},
+			{trX(plt.X.Max), trY(p.Slope*plt.X.Max + p.Intercept)},
+		}
+		c.StrokeLines(p.Reference, c.ClipLinesXY(line)...)
+	}
+	for _, pt := range p.XYs {
+		c.DrawGlyph(p.GlyphStyle, draw.Point{trX(pt.X), trY(pt.Y)})
+	}
+}
+
+// DataRange returns the minimum and maximum
+// x and y values, implementing the plot.DataRanger
+// interface.
+func (p *ProbPlot) DataRange() (xmin, xmax, ymin, ymax float64) {
+	return XYRange(p)
+}
+
+// GlyphBoxes returns a slice of plot.GlyphBoxes,
+// implementing the plot.GlyphBoxer interface.
+func (p *ProbPlot) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
+	bs := make([]plot.GlyphBox, len(p.XYs))
+	for i, pt := range p.XYs {
+		bs[i].X = plt.X.Norm(pt.X)
+		bs[i].Y = plt.Y.Norm(pt.Y)
+		bs[i].Rectangle = p.GlyphStyle.Rectangle()
+	}
+	return bs
+}
+
+// Pick returns the index of the point nearest to pt,
+// implementing the plot.Picker interface.
+func (p *ProbPlot) Pick(c draw.Canvas, plt *plot.Plot, pt draw.Point, tol vg.Length) (int, vg.Length, bool) {
+	return pickXYs(c, plt, p.XYs, pt, tol)
+}
+
+// Thumbnail the thumbnail for the ProbPlot,
+// implementing the plot.Thumbnailer interface.
+func (p *ProbPlot) Thumbnail(c *draw.Canvas) {
+	c.DrawGlyph(p.GlyphStyle, c.Center())
+}
+
+// sortedValues returns a sorted copy of the values.
+func sortedValues(vs Valuer) (Values, error) {
+	vals, err := CopyValues(vs)
+	if err != nil {
+		return nil, err
+	}
+	sort.Float64s(vals)
+	return vals, nil
+}
+
+// plottingPosition returns the cumulative probability
+// at which the ith of n sorted values is plotted.
+func plottingPosition(i, n int) float64 {
+	return (float64(i) + 0.5) / float64(n)
+}
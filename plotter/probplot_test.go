@@ -0,0 +1,79 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistributions(t *testing.T) {
+	for _, d := range []Distribution{
+		NormalDist{Mu: 1, Sigma: 2},
+		LogNormalDist{Mu: 0, Sigma: 0.5},
+		UniformDist{Min: -1, Max: 3},
+		ExponentialDist{Rate: 0.5},
+	} {
+		for _, p := range []float64{0.001, 0.25, 0.5, 0.9, 0.999} {
+			if got := d.CDF(d.Quantile(p)); math.Abs(got-p) > 1e-12 {
+				t.Errorf("unexpected CDF of quantile %v of %#v: got:%v", p, d, got)
+			}
+		}
+	}
+	if got := (NormalDist{Sigma: 1}).Quantile(0.975); math.Abs(got-1.959963984540054) > 1e-12 {
+		t.Errorf("unexpected standard normal quantile: got:%v want:1.959963984540054", got)
+	}
+}
+
+func TestQQ(t *testing.T) {
+	vs := Values{3, -1, 1, 7, 5}
+	p, err := NewQQ(vs, UniformDist{Min: 0, Max: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, pt := range p.XYs {
+		x, y := (float64(i)+0.5)/5, float64(2*i-1)
+		if math.Abs(pt.X-x) > 1e-15 || pt.Y != y {
+			t.Errorf("unexpected point %d: got:%v want:{%v %v}", i, pt, x, y)
+		}
+	}
+	if math.Abs(p.Slope-10) > 1e-12 || math.Abs(p.Intercept+2) > 1e-12 {
+		t.Errorf("unexpected reference line: got:y=%vx%+v want:y=10x-2", p.Slope, p.Intercept)
+	}
+
+	p, err = NewQQSamples(Values{4, 1, 2, 3}, Values{20, 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := XYs{{1.5, 10}, {3.5, 20}}
+	if len(p.XYs) != len(want) {
+		t.Fatalf("unexpected number of points: got:%d want:%d", len(p.XYs), len(want))
+	}
+	for i, pt := range p.XYs {
+		if pt != want[i] {
+			t.Errorf("unexpected point %d: got:%v want:%v", i, pt, want[i])
+		}
+	}
+
+	if _, err = NewQQ(Values{}, NormalDist{Sigma: 1}); err != ErrNoData {
+		t.Errorf("unexpected error for empty sample: got:%v want:%v", err, ErrNoData)
+	}
+}
+
+func TestPP(t *testing.T) {
+	p, err := NewPP(Values{0.5, 0, 1, 0.25}, UniformDist{Min: 0, Max: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := XYs{{0, 0.125}, {0.25, 0.375}, {0.5, 0.625}, {1, 0.875}}
+	for i, pt := range p.XYs {
+		if pt != want[i] {
+			t.Errorf("unexpected point %d: got:%v want:%v", i, pt, want[i])
+		}
+	}
+	if p.Slope != 1 || p.Intercept != 0 {
+		t.Errorf("unexpected reference line: got:y=%vx%+v want:y=x", p.Slope, p.Intercept)
+	}
+}
@@ -48,6 +48,19 @@ type QuartPlot struct {
 	// WhiskerStyle is the line style used to draw the
 	// whiskers.
 	WhiskerStyle draw.LineStyle
+
+	// Notched specifies whether a line in NotchStyle
+	// is drawn from NotchLow to NotchHigh to show the
+	// confidence interval of the median.
+	Notched bool
+
+	// NotchStyle is the line style used to draw the
+	// confidence interval of the median.
+	NotchStyle draw.LineStyle
+
+	// Points is an overlay of the values drawn
+	// over or beside the plot.
+	Points DataPoints
 }
 
 // NewQuartPlot returns a new QuartPlot that represents
@@ -61,16 +74,23 @@ type QuartPlot struct {
 // value that is outside of the fences are drawn as
 // Outside points.  The adjacent values (to which the
 // whiskers stretch) are the minimum and maximum
-// values that are not outside the fences.
-func NewQuartPlot(loc float64, values Valuer) (*QuartPlot, error) {
+// values that are not outside the fences.  The
+// computation of the statistics may be changed by
+// the options.
+func NewQuartPlot(loc float64, values Valuer, opts ...StatOption) (*QuartPlot, error) {
 	b := new(QuartPlot)
 	var err error
-	if b.fiveStatPlot, err = newFiveStat(0, loc, values); err != nil {
+	if b.fiveStatPlot, err = newFiveStat(0, loc, values, opts); err != nil {
 		return nil, err
 	}
 
 	b.MedianStyle = DefaultQuartMedianStyle
 	b.WhiskerStyle = DefaultQuartWhiskerStyle
+	b.NotchStyle = draw.LineStyle{Color: color.Black, Width: vg.Points(1)}
+	b.Points = DataPoints{
+		Spread:     vg.Points(5),
+		GlyphStyle: draw.GlyphStyle{Color: color.Black, Radius: vg.Points(1), Shape: draw.CircleGlyph{}},
+	}
 
 	return b, err
 }
@@ -90,11 +110,21 @@ func (b *QuartPlot) Plot(c draw.Canvas, plt *plot.Plot) {
 	aHigh := trY(b.AdjHigh)
 
 	c.StrokeLine2(b.WhiskerStyle, x, aHigh, x, q3)
+	if b.Notched {
+		notch := []draw.Point{{x, trY(b.NotchLow)}, {x, trY(b.NotchHigh)}}
+		c.StrokeLines(b.NotchStyle, c.ClipLinesY(notch)...)
+	}
 	if c.ContainsY(med.Y) {
 		c.DrawGlyphNoClip(b.MedianStyle, med)
 	}
 	c.StrokeLine2(b.WhiskerStyle, x, aLow, x, q1)
 
+	ys := make([]vg.Length, len(b.Values))
+	for i, v := range b.Values {
+		ys[i] = trY(v)
+	}
+	b.Points.plot(&c, ys, x, b.MedianStyle.Radius+b.Points.Radius*2, false)
+
 	ostyle := b.MedianStyle
 	ostyle.Radius = b.MedianStyle.Radius / 2
 	for _, out := range b.Outside {
@@ -129,6 +159,13 @@ func (b *QuartPlot) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
 	bs[len(bs)-1].Y = plt.Y.Norm(b.Median)
 	bs[len(bs)-1].Rectangle = b.MedianStyle.Rectangle()
 	bs[len(bs)-1].Rectangle.Min.X += b.Offset
+	if min, max, ok := b.Points.besideExtent(b.MedianStyle.Radius + b.Points.Radius*2); ok {
+		bs = append(bs, plot.GlyphBox{
+			X:         plt.X.Norm(b.Location),
+			Y:         plt.Y.Norm(b.Median),
+			Rectangle: draw.Rectangle{Min: draw.Point{X: b.Offset + min}, Max: draw.Point{X: b.Offset + max}},
+		})
+	}
 	return bs
 }
 
@@ -175,8 +212,8 @@ type HorizQuartPlot struct{ *QuartPlot }
 // MakeHorizQuartPlot returns a HorizQuartPlot,
 // plotting the values in a horizontal plot
 // centered along a fixed location of the y axis.
-func MakeHorizQuartPlot(loc float64, vs Valuer) (HorizQuartPlot, error) {
-	q, err := NewQuartPlot(loc, vs)
+func MakeHorizQuartPlot(loc float64, vs Valuer, opts ...StatOption) (HorizQuartPlot, error) {
+	q, err := NewQuartPlot(loc, vs, opts...)
 	return HorizQuartPlot{q}, err
 }
 
@@ -195,11 +232,21 @@ func (b HorizQuartPlot) Plot(c draw.Canvas, plt *plot.Plot) {
 	aHigh := trX(b.AdjHigh)
 
 	c.StrokeLine2(b.WhiskerStyle, aHigh, y, q3, y)
+	if b.Notched {
+		notch := []draw.Point{{trX(b.NotchLow), y}, {trX(b.NotchHigh), y}}
+		c.StrokeLines(b.NotchStyle, c.ClipLinesX(notch)...)
+	}
 	if c.ContainsX(med.X) {
 		c.DrawGlyphNoClip(b.MedianStyle, med)
 	}
 	c.StrokeLine2(b.WhiskerStyle, aLow, y, q1, y)
 
+	xs := make([]vg.Length, len(b.Values))
+	for i, v := range b.Values {
+		xs[i] = trX(v)
+	}
+	b.Points.plot(&c, xs, y, b.MedianStyle.Radius+b.Points.Radius*2, true)
+
 	ostyle := b.MedianStyle
 	ostyle.Radius = b.MedianStyle.Radius / 2
 	for _, out := range b.Outside {
@@ -234,6 +281,13 @@ func (b HorizQuartPlot) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
 	bs[len(bs)-1].Y = plt.Y.Norm(b.Location)
 	bs[len(bs)-1].Rectangle = b.MedianStyle.Rectangle()
 	bs[len(bs)-1].Rectangle.Min.Y += b.Offset
+	if min, max, ok := b.Points.besideExtent(b.MedianStyle.Radius + b.Points.Radius*2); ok {
+		bs = append(bs, plot.GlyphBox{
+			X:         plt.X.Norm(b.Median),
+			Y:         plt.Y.Norm(b.Location),
+			Rectangle: draw.Rectangle{Min: draw.Point{Y: b.Offset + min}, Max: draw.Point{Y: b.Offset + max}},
+		})
+	}
 	return bs
 }
 
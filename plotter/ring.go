@@ -0,0 +1,178 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"errors"
+	"sync"
+)
+
+// RingXYs implements the XYer interface, holding the
+// most recent points appended to it up to a fixed
+// capacity, for plotting a live feed of data.  Points
+// may be appended while a plot of the RingXYs is being
+// drawn from another goroutine.  Points are indexed
+// from the oldest to the newest, and the number of
+// points never decreases, so an index less than a
+// length returned by Len remains valid.
+//
+// A plotter made by NewLineRef or NewScatterRef of a
+// RingXYs copies the points with Snapshot each time it
+// is drawn, so the points drawn are consistent even if
+// points are appended while drawing.  The data range
+// found when the plotter was added to a plot is not
+// updated as points are appended, so either the axis
+// ranges of the plot must be set, or a new plot and
+// plotter of the Snapshot of the points must be made
+// for each frame, such as in the frame function of an
+// animation.Stream.
+type RingXYs struct {
+	mu   sync.RWMutex
+	xys  XYs
+	head int
+}
+
+// NewRingXYs returns an empty RingXYs that holds
+// at most capacity points.
+func NewRingXYs(capacity int) (*RingXYs, error) {
+	if capacity <= 0 {
+		return nil, errors.New("Capacity parameter was not positive")
+	}
+	return &RingXYs{xys: make(XYs, 0, capacity)}, nil
+}
+
+// Append appends a point, replacing the oldest
+// point if the RingXYs is full.  Missing values,
+// which are NaN, are kept.  An error is returned
+// if x or y is infinite.
+func (r *RingXYs) Append(x, y float64) error {
+	if err := checkInfinite(x, y); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.xys) < cap(r.xys) {
+		r.xys = append(r.xys, struct{ X, Y float64 }{x, y})
+		return nil
+	}
+	r.xys[r.head].X, r.xys[r.head].Y = x, y
+	r.head = (r.head + 1) % len(r.xys)
+	return nil
+}
+
+// Cap returns the maximum number of points
+// held by the RingXYs.
+func (r *RingXYs) Cap() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return cap(r.xys)
+}
+
+// Len returns the number of points.
+func (r *RingXYs) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.xys)
+}
+
+// XY returns the ith oldest point.
+func (r *RingXYs) XY(i int) (x, y float64) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p := r.xys[(r.head+i)%len(r.xys)]
+	return p.X, p.Y
+}
+
+// Snapshot returns a copy of the points, from
+// the oldest to the newest.
+func (r *RingXYs) Snapshot() XYs {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cpy := make(XYs, 0, len(r.xys))
+	cpy = append(cpy, r.xys[r.head:]...)
+	return append(cpy, r.xys[:r.head]...)
+}
+
+// snapshot returns the points of data, copied by
+// their Snapshot method if they have one, so that
+// they cannot change while they are being used.
+func snapshot(data XYer) XYer {
+	if s, ok := data.(interface {
+		Snapshot() XYs
+	}); ok {
+		return s.Snapshot()
+	}
+	return data
+}
+
+// RingValues implements the Valuer interface, holding
+// the most recent values appended to it up to a fixed
+// capacity.  It is safe for concurrent use in the same
+// way as RingXYs.
+type RingValues struct {
+	mu   sync.RWMutex
+	vs   Values
+	head int
+}
+
+// NewRingValues returns an empty RingValues that
+// holds at most capacity values.
+func NewRingValues(capacity int) (*RingValues, error) {
+	if capacity <= 0 {
+		return nil, errors.New("Capacity parameter was not positive")
+	}
+	return &RingValues{vs: make(Values, 0, capacity)}, nil
+}
+
+// Append appends a value, replacing the oldest
+// value if the RingValues is full.  Missing values,
+// which are NaN, are kept.  An error is returned if
+// v is infinite.
+func (r *RingValues) Append(v float64) error {
+	if err := checkInfinite(v); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.vs) < cap(r.vs) {
+		r.vs = append(r.vs, v)
+		return nil
+	}
+	r.vs[r.head] = v
+	r.head = (r.head + 1) % len(r.vs)
+	return nil
+}
+
+// Cap returns the maximum number of values
+// held by the RingValues.
+func (r *RingValues) Cap() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return cap(r.vs)
+}
+
+// Len returns the number of values.
+func (r *RingValues) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.vs)
+}
+
+// Value returns the ith oldest value.
+func (r *RingValues) Value(i int) float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.vs[(r.head+i)%len(r.vs)]
+}
+
+// Snapshot returns a copy of the values, from
+// the oldest to the newest.
+func (r *RingValues) Snapshot() Values {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cpy := make(Values, 0, len(r.vs))
+	cpy = append(cpy, r.vs[r.head:]...)
+	return append(cpy, r.vs[:r.head]...)
+}
@@ -0,0 +1,150 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/recorder"
+)
+
+func TestRingXYs(t *testing.T) {
+	if _, err := NewRingXYs(0); err == nil {
+		t.Error("expected error for zero capacity")
+	}
+	r, err := NewRingXYs(3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, want := range []XYs{
+		{{0, 0}},
+		{{0, 0}, {1, 10}},
+		{{0, 0}, {1, 10}, {2, 20}},
+		{{1, 10}, {2, 20}, {3, 30}},
+		{{2, 20}, {3, 30}, {4, 40}},
+	} {
+		if err := r.Append(float64(i), float64(10*i)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := r.Snapshot(); !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected snapshot after %d appends: got:%v want:%v", i+1, got, want)
+		}
+		got, err := CopyXYs(r)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected points after %d appends: got:%v want:%v", i+1, got, want)
+		}
+	}
+	if err := r.Append(math.Inf(1), 0); err != ErrInfinity {
+		t.Errorf("unexpected error for infinite point: got:%v want:%v", err, ErrInfinity)
+	}
+	if err := r.Append(math.NaN(), 50); err != nil {
+		t.Errorf("unexpected error for missing point: %v", err)
+	}
+	if got := r.Snapshot(); !sameXYs(got, XYs{{3, 30}, {4, 40}, {math.NaN(), 50}}) {
+		t.Errorf("unexpected snapshot with missing point: got:%v", got)
+	}
+	if r.Cap() != 3 || r.Len() != 3 {
+		t.Errorf("unexpected size: got cap:%d len:%d want cap:3 len:3", r.Cap(), r.Len())
+	}
+}
+
+func TestRingValues(t *testing.T) {
+	r, err := NewRingValues(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, v := range []float64{1, 2, 3} {
+		if err := r.Append(v); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got, want := r.Snapshot(), (Values{2, 3}); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected snapshot: got:%v want:%v", got, want)
+	}
+	got, err := CopyValues(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (Values{2, 3}); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected values: got:%v want:%v", got, want)
+	}
+}
+
+func TestRingConcurrentPlot(t *testing.T) {
+	r, err := NewRingXYs(100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r.Append(0, 0)
+	r.Append(1, 1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 2; i < 10000; i++ {
+			r.Append(float64(i), float64(i))
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		// Each frame plots a snapshot so that the points
+		// cannot change while the frame is drawn.
+		xys := r.Snapshot()
+		l, err := NewLine(xys)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		p, err := plot.New()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		p.Add(l)
+		p.Draw(draw.NewCanvas(recorder.New(72), 300, 300))
+		for j := range xys {
+			if xys[j].X != xys[j].Y || (j > 0 && xys[j].X != xys[j-1].X+1) {
+				t.Fatalf("torn snapshot of frame %d at point %d: %v", i, j, xys)
+			}
+		}
+		if p.X.Min != xys[0].X || p.X.Max != xys[len(xys)-1].X {
+			t.Errorf("unexpected X range of frame %d: got:[%v, %v] want:[%v, %v]",
+				i, p.X.Min, p.X.Max, xys[0].X, xys[len(xys)-1].X)
+		}
+	}
+	wg.Wait()
+}
+
+func TestRingRef(t *testing.T) {
+	r, err := NewRingXYs(3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		r.Append(float64(i), float64(i))
+	}
+	l, err := NewLineRef(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s, err := NewScatterRef(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := XYs{{1, 1}, {2, 2}, {3, 3}}
+	for _, data := range []XYer{l.data(), s.data()} {
+		if got, ok := data.(XYs); !ok || !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected data of plotter referring to ring: got:%#v want:%v", data, want)
+		}
+	}
+	if got, _ := strokes(drawPlotter(t, l)); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("unexpected strokes: got:%v want:[3]", got)
+	}
+}
@@ -5,7 +5,10 @@
 package plotter
 
 import (
+	"math"
+
 	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
 	"github.com/gonum/plot/vg/draw"
 )
 
@@ -13,17 +16,40 @@ import (
 // a glyph for each of a set of points.
 type Scatter struct {
 	// XYs is a copy of the points for this scatter.
+	// XYs is nil for a scatter returned by
+	// NewScatterRef.
 	XYs
 
+	// ref is the caller's data for a scatter
+	// returned by NewScatterRef.
+	ref XYer
+
 	// GlyphStyle is the style of the glyphs drawn
 	// at each point.
 	draw.GlyphStyle
+
+	// Merge specifies whether points that fall in
+	// the same pixel of the canvas are drawn as a
+	// single glyph, which keeps the drawing of a
+	// dense scatter small.  The scatter looks the
+	// same only if the glyph color is opaque, since
+	// overlapping translucent glyphs are darker than
+	// a single glyph.
+	Merge bool
+
+	// Missing is the policy for points with missing
+	// values.  By default no glyph is drawn for them.
+	// With MissingInterpolate a glyph is drawn at the
+	// interpolated point, as for a Line.
+	Missing MissingPolicy
 }
 
 // NewScatter returns a Scatter that uses the
-// default glyph style.
+// default glyph style.  Points with missing values
+// are kept, and are handled by the Missing policy
+// of the scatter when it is drawn.
 func NewScatter(xys XYer) (*Scatter, error) {
-	data, err := CopyXYs(xys)
+	data, err := CopyXYsMissing(xys, MissingBreak)
 	if err != nil {
 		return nil, err
 	}
@@ -33,12 +59,83 @@ func NewScatter(xys XYer) (*Scatter, error) {
 	}, err
 }
 
+// NewScatterRef returns a Scatter of the points of
+// xys that uses the default glyph style.  Unlike
+// NewScatter, the points are not copied, and the
+// scatter refers to xys when it is drawn, so xys
+// must not be changed while the scatter is in use,
+// unless xys has a Snapshot method returning XYs,
+// like RingXYs, in which case the points are copied
+// with it each time the scatter is drawn.  An error
+// is returned if one of the points is infinite.
+func NewScatterRef(xys XYer) (*Scatter, error) {
+	if err := checkXYs(xys); err != nil {
+		return nil, err
+	}
+	return &Scatter{
+		ref:        xys,
+		GlyphStyle: DefaultGlyphStyle,
+	}, nil
+}
+
+// Len returns the number of points of the scatter.
+func (pts *Scatter) Len() int {
+	if pts.ref != nil {
+		return pts.ref.Len()
+	}
+	return len(pts.XYs)
+}
+
+// XY returns the ith point of the scatter.
+func (pts *Scatter) XY(i int) (x, y float64) {
+	if pts.ref != nil {
+		return pts.ref.XY(i)
+	}
+	return pts.XYs.XY(i)
+}
+
+// data returns the points of the scatter, copied
+// by their Snapshot method if the scatter refers to
+// points that have one.
+func (pts *Scatter) data() XYer {
+	if pts.ref != nil {
+		return snapshot(pts.ref)
+	}
+	return pts.XYs
+}
+
 // Plot draws the Scatter, implementing the plot.Plotter
 // interface.
 func (pts *Scatter) Plot(c draw.Canvas, plt *plot.Plot) {
 	trX, trY := plt.Transforms(&c)
-	for _, p := range pts.XYs {
-		c.DrawGlyph(pts.GlyphStyle, draw.Point{trX(p.X), trY(p.Y)})
+	data := pts.data()
+	if pts.Missing == MissingInterpolate && hasMissing(data) {
+		if cpy, err := CopyXYsMissing(data, MissingInterpolate); err == nil {
+			data = cpy
+		}
+	}
+	var (
+		w    vg.Length
+		seen map[[2]int]bool
+	)
+	if pts.Merge {
+		w = pixelWidth(c)
+		seen = make(map[[2]int]bool)
+	}
+	for i, n := 0, data.Len(); i < n; i++ {
+		x, y := data.XY(i)
+		if isMissing(x, y) {
+			continue
+		}
+		p := draw.Point{trX(x), trY(y)}
+		if pts.Merge {
+			cell := [2]int{column(p.X, c.Min.X, w), column(p.Y, c.Min.Y, w)}
+			if seen[cell] {
+				continue
+			}
+			seen[cell] = true
+		}
+		c.DrawGlyph(pts.GlyphStyle, p)
 	}
 }
 
@@ -46,17 +143,54 @@ func (pts *Scatter) Plot(c draw.Canvas, plt *plot.Plot) {
 // x and y values, implementing the plot.DataRanger
 // interface.
 func (pts *Scatter) DataRange() (xmin, xmax, ymin, ymax float64) {
-	return XYRange(pts)
+	return XYRange(pts.data())
+}
+
+// Pick returns the index of the point nearest to pt,
+// implementing the plot.Picker interface.
+func (pts *Scatter) Pick(c draw.Canvas, plt *plot.Plot, pt draw.Point, tol vg.Length) (int, vg.Length, bool) {
+	return pickXYs(c, plt, pts.data(), pt, tol)
 }
 
+// mergeCells is the number of cells across each
+// axis in which the glyph boxes of a merged scatter
+// are collapsed, which is finer than the pixels of
+// most canvases.
+const mergeCells = 4096
+
 // GlyphBoxes returns a slice of plot.GlyphBoxes,
 // implementing the plot.GlyphBoxer interface.
+// No boxes are returned for points with missing
+// values.  If Merge is set, the boxes of points
+// that fall in the same cell of a fine grid over
+// the axes are collapsed into one box, as their
+// glyphs are merged when they are drawn.
 func (pts *Scatter) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
-	bs := make([]plot.GlyphBox, len(pts.XYs))
-	for i, p := range pts.XYs {
-		bs[i].X = plt.X.Norm(p.X)
-		bs[i].Y = plt.Y.Norm(p.Y)
-		bs[i].Rectangle = pts.GlyphStyle.Rectangle()
+	data := pts.data()
+	var (
+		bs   []plot.GlyphBox
+		seen map[[2]int]bool
+	)
+	if pts.Merge {
+		seen = make(map[[2]int]bool)
+	} else {
+		bs = make([]plot.GlyphBox, 0, data.Len())
+	}
+	r := pts.GlyphStyle.Rectangle()
+	for i, n := 0, data.Len(); i < n; i++ {
+		x, y := data.XY(i)
+		if isMissing(x, y) {
+			continue
+		}
+		b := plot.GlyphBox{X: plt.X.Norm(x), Y: plt.Y.Norm(y), Rectangle: r}
+		if pts.Merge {
+			cell := [2]int{int(math.Floor(b.X * mergeCells)), int(math.Floor(b.Y * mergeCells))}
+			if seen[cell] {
+				continue
+			}
+			seen[cell] = true
+		}
+		bs = append(bs, b)
 	}
 	return bs
 }
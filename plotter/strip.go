@@ -0,0 +1,235 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"errors"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+)
+
+// StripPlot implements the Plotter interface, drawing
+// a glyph for each of the values at a location along
+// the x axis, such as a category of a plot with
+// nominal x values.  The glyphs are spread across
+// the axis so that equal values can be seen.
+type StripPlot struct {
+	// Values is a copy of the values of the plot.
+	Values
+
+	// Location is the location of the plot
+	// along its axis.
+	Location float64
+
+	// Offset is added to the x location of the plot.
+	// When the Offset is zero, the glyphs are spread
+	// around their x location.
+	Offset vg.Length
+
+	// Layout is the layout of the glyphs,
+	// StripPoints or SwarmPoints.
+	Layout PointLayout
+
+	// Spread is the greatest offset of jittered
+	// glyphs from the location.  It is also the
+	// width kept free on either side of the location
+	// for a swarm, since the width of a swarm is not
+	// known until it is drawn.
+	Spread vg.Length
+
+	// Seed is the seed of the random jitter.
+	Seed int64
+
+	// GlyphStyle is the style of the glyphs.
+	draw.GlyphStyle
+}
+
+// NewStripPlot returns a StripPlot of the values
+// at the location, jittered by up to spread.
+func NewStripPlot(loc float64, spread vg.Length, values Valuer) (*StripPlot, error) {
+	if spread < 0 {
+		return nil, errors.New("Negative spread")
+	}
+	vs, err := CopyValues(values)
+	if err != nil {
+		return nil, err
+	}
+	return &StripPlot{
+		Values:     vs,
+		Location:   loc,
+		Layout:     StripPoints,
+		Spread:     spread,
+		GlyphStyle: DefaultGlyphStyle,
+	}, nil
+}
+
+// NewBeeswarm returns a StripPlot of the values at
+// the location, packed as a beeswarm so that the
+// glyphs do not overlap.  The packing depends on
+// the glyph radius and is computed in canvas units
+// each time the plot is drawn.  The width kept free
+// for the swarm is given by the Spread, which is
+// four glyph radii.
+func NewBeeswarm(loc float64, values Valuer) (*StripPlot, error) {
+	s, err := NewStripPlot(loc, 0, values)
+	if err != nil {
+		return nil, err
+	}
+	s.Layout = SwarmPoints
+	s.Spread = 4 * s.Radius
+	return s, nil
+}
+
+// points returns the canvas points of the glyphs,
+// with the values along the x axis if horiz is true.
+func (s *StripPlot) points(c draw.Canvas, plt *plot.Plot, horiz bool) []draw.Point {
+	trX, trY := plt.Transforms(&c)
+	tr, trLoc := trY, trX
+	if horiz {
+		tr, trLoc = trX, trY
+	}
+	along := make([]vg.Length, len(s.Values))
+	for i, v := range s.Values {
+		along[i] = tr(v)
+	}
+	loc := trLoc(s.Location) + s.Offset
+	offs := layoutOffsets(s.Layout, along, s.Radius, s.Spread, s.Seed, false)
+	pts := make([]draw.Point, len(along))
+	for i, v := range along {
+		pts[i] = draw.Point{loc + offs[i], v}
+		if horiz {
+			pts[i] = draw.Point{v, loc + offs[i]}
+		}
+	}
+	return pts
+}
+
+// Plot draws the StripPlot, implementing the
+// plot.Plotter interface.
+func (s *StripPlot) Plot(c draw.Canvas, plt *plot.Plot) {
+	trX, _ := plt.Transforms(&c)
+	if !c.ContainsX(trX(s.Location)) {
+		return
+	}
+	for _, pt := range s.points(c, plt, false) {
+		if c.ContainsY(pt.Y) {
+			c.DrawGlyphNoClip(s.GlyphStyle, pt)
+		}
+	}
+}
+
+// DataRange returns the minimum and maximum x
+// and y values, implementing the plot.DataRanger
+// interface.
+func (s *StripPlot) DataRange() (xmin, xmax, ymin, ymax float64) {
+	ymin, ymax = Range(s.Values)
+	return s.Location, s.Location, ymin, ymax
+}
+
+// GlyphBoxes returns a slice of GlyphBoxes for the
+// glyphs, implementing the plot.GlyphBoxer interface.
+// The boxes of the glyphs cover their spread.
+func (s *StripPlot) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
+	bs := make([]plot.GlyphBox, len(s.Values))
+	for i, v := range s.Values {
+		bs[i].X = plt.X.Norm(s.Location)
+		bs[i].Y = plt.Y.Norm(v)
+		bs[i].Rectangle = s.glyphRectangle()
+		bs[i].Rectangle.Min.X += s.Offset
+		bs[i].Rectangle.Max.X += s.Offset
+	}
+	return bs
+}
+
+// glyphRectangle returns the rectangle around
+// the location covered by a glyph, widened
+// across the axis by the spread.
+func (s *StripPlot) glyphRectangle() draw.Rectangle {
+	r := s.GlyphStyle.Rectangle()
+	r.Min.X -= s.Spread
+	r.Max.X += s.Spread
+	return r
+}
+
+// Pick returns the index of the value whose glyph
+// is nearest to pt, implementing the plot.Picker
+// interface.
+func (s *StripPlot) Pick(c draw.Canvas, plt *plot.Plot, pt draw.Point, tol vg.Length) (int, vg.Length, bool) {
+	return pickPoints(s.points(c, plt, false), pt, tol)
+}
+
+// Thumbnail the thumbnail for the StripPlot,
+// implementing the plot.Thumbnailer interface.
+func (s *StripPlot) Thumbnail(c *draw.Canvas) {
+	c.DrawGlyph(s.GlyphStyle, c.Center())
+}
+
+// HorizStripPlot is like a regular StripPlot, however,
+// it draws horizontally instead of vertically.
+type HorizStripPlot struct{ *StripPlot }
+
+// MakeHorizStripPlot returns a HorizStripPlot of the
+// values at a location along the y axis, jittered
+// by up to spread.
+func MakeHorizStripPlot(loc float64, spread vg.Length, values Valuer) (HorizStripPlot, error) {
+	s, err := NewStripPlot(loc, spread, values)
+	return HorizStripPlot{s}, err
+}
+
+// MakeHorizBeeswarm returns a HorizStripPlot of the
+// values at a location along the y axis, packed as
+// a beeswarm.
+func MakeHorizBeeswarm(loc float64, values Valuer) (HorizStripPlot, error) {
+	s, err := NewBeeswarm(loc, values)
+	return HorizStripPlot{s}, err
+}
+
+// Plot draws the HorizStripPlot, implementing the
+// plot.Plotter interface.
+func (s HorizStripPlot) Plot(c draw.Canvas, plt *plot.Plot) {
+	_, trY := plt.Transforms(&c)
+	if !c.ContainsY(trY(s.Location)) {
+		return
+	}
+	for _, pt := range s.points(c, plt, true) {
+		if c.ContainsX(pt.X) {
+			c.DrawGlyphNoClip(s.GlyphStyle, pt)
+		}
+	}
+}
+
+// DataRange returns the minimum and maximum x
+// and y values, implementing the plot.DataRanger
+// interface.
+func (s HorizStripPlot) DataRange() (xmin, xmax, ymin, ymax float64) {
+	xmin, xmax = Range(s.Values)
+	return xmin, xmax, s.Location, s.Location
+}
+
+// GlyphBoxes returns a slice of GlyphBoxes for the
+// glyphs, implementing the plot.GlyphBoxer interface.
+// The boxes of the glyphs cover their spread.
+func (s HorizStripPlot) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
+	bs := make([]plot.GlyphBox, len(s.Values))
+	for i, v := range s.Values {
+		r := s.glyphRectangle()
+		bs[i].X = plt.X.Norm(v)
+		bs[i].Y = plt.Y.Norm(s.Location)
+		bs[i].Rectangle = draw.Rectangle{
+			Min: draw.Point{r.Min.Y, r.Min.X + s.Offset},
+			Max: draw.Point{r.Max.Y, r.Max.X + s.Offset},
+		}
+	}
+	return bs
+}
+
+// Pick returns the index of the value whose glyph
+// is nearest to pt, implementing the plot.Picker
+// interface.
+func (s HorizStripPlot) Pick(c draw.Canvas, plt *plot.Plot, pt draw.Point, tol vg.Length) (int, vg.Length, bool) {
+	return pickPoints(s.points(c, plt, true), pt, tol)
+}
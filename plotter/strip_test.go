@@ -0,0 +1,143 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/recorder"
+)
+
+func TestBeeswarm(t *testing.T) {
+	vs := Values{1, 1, 1, 1, 2, 5}
+	s, err := NewBeeswarm(0, vs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h, err := MakeHorizBeeswarm(0, vs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.Add(s)
+	c := p.DataCanvas(draw.NewCanvas(recorder.New(72), 200, 200))
+	for _, horiz := range []bool{false, true} {
+		pts := s.points(c, p, horiz)
+		if horiz {
+			pts = h.points(c, p, true)
+		}
+		for i := range pts {
+			for j := i + 1; j < len(pts); j++ {
+				d := math.Hypot(float64(pts[i].X-pts[j].X), float64(pts[i].Y-pts[j].Y))
+				if d < float64(2*s.Radius)-1e-9 {
+					t.Errorf("glyphs %d and %d overlap with horiz=%t", i, j, horiz)
+				}
+			}
+		}
+	}
+
+	// The glyph boxes cover the glyphs of the swarm.
+	trX, _ := p.Transforms(&c)
+	var max vg.Length
+	for _, pt := range s.points(c, p, false) {
+		if off := pt.X - trX(0); off > max {
+			max = off
+		} else if -off > max {
+			max = -off
+		}
+	}
+	for _, b := range s.GlyphBoxes(p) {
+		if b.Rectangle.Max.X < max+s.Radius || b.Rectangle.Min.X > -max-s.Radius {
+			t.Errorf("glyph box %v does not cover swarm of half width %v", b.Rectangle, max+s.Radius)
+		}
+	}
+	for _, b := range h.GlyphBoxes(p) {
+		if b.Rectangle.Max.Y < max+s.Radius || b.Rectangle.Min.Y > -max-s.Radius {
+			t.Errorf("horizontal glyph box %v does not cover swarm of half width %v", b.Rectangle, max+s.Radius)
+		}
+	}
+}
+
+func TestStripPlot(t *testing.T) {
+	s, err := NewStripPlot(1, 5, Values{1, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.Seed = 7
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.NominalX("a", "b")
+	p.Add(s)
+	c := p.DataCanvas(draw.NewCanvas(recorder.New(72), 200, 200))
+	trX, _ := p.Transforms(&c)
+
+	pts := s.points(c, p, false)
+	for i, pt := range pts {
+		if off := pt.X - trX(1); off < -5 || off > 5 {
+			t.Errorf("jitter of glyph %d out of spread: got:%v", i, off)
+		}
+	}
+	if !reflect.DeepEqual(pts, s.points(c, p, false)) {
+		t.Error("jitter differs between draws with the same seed")
+	}
+	if i, _, ok := s.Pick(c, p, pts[2], vg.Points(1)); !ok || i != 2 {
+		t.Errorf("unexpected pick: got:%d,%t want:2,true", i, ok)
+	}
+
+	s.Seed = 8
+	if reflect.DeepEqual(pts, s.points(c, p, false)) {
+		t.Error("jitter is the same with different seeds")
+	}
+
+	if _, err = NewStripPlot(0, -1, Values{1}); err == nil {
+		t.Error("expected error for negative spread")
+	}
+}
+
+func TestDotPlot(t *testing.T) {
+	d, err := NewDotPlot(Values{3, 1, 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.XMin = 1
+	xmin, xmax, ymin, ymax := d.DataRange()
+	if xmin != 1 || xmax != 3 || ymin != 1 || ymax != 3 {
+		t.Errorf("unexpected data range: got:%v,%v,%v,%v want:1,3,1,3", xmin, xmax, ymin, ymax)
+	}
+
+	h, err := MakeHorizDotPlot(Values{3, 1, 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.NominalY("a", "b", "c")
+	p.Add(h)
+	r := recorder.New(72)
+	c := p.DataCanvas(draw.NewCanvas(r, 200, 200))
+	r.Reset()
+	h.Plot(c, p)
+	var guides int
+	for _, a := range r.Actions {
+		if s, ok := a.(*recorder.Stroke); ok && len(s.Path) == 2 && s.Path[0].X == c.Min.X {
+			guides++
+		}
+	}
+	if guides != 3 {
+		t.Errorf("unexpected number of guide lines: got:%d want:3", guides)
+	}
+}
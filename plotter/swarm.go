@@ -0,0 +1,189 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+)
+
+// PointLayout specifies how glyphs at values along
+// an axis are spread across it.
+type PointLayout int
+
+const (
+	// NoPoints draws no glyphs.
+	NoPoints PointLayout = iota
+
+	// StripPoints draws the glyphs offset by a
+	// random jitter of at most the spread, or on
+	// a line if the spread is zero.  The jitter is
+	// the same each time the glyphs are drawn.
+	StripPoints
+
+	// SwarmPoints draws the glyphs as near to the
+	// line as possible without overlapping, as a
+	// beeswarm.
+	SwarmPoints
+)
+
+// DataPoints is an overlay of the raw values of
+// a box plot or quartile plot.
+type DataPoints struct {
+	// Layout is the layout of the points.
+	// The zero value draws no points.
+	Layout PointLayout
+
+	// Beside specifies whether the points are drawn
+	// to one side of the plot rather than over it.
+	Beside bool
+
+	// Spread is the greatest offset of jittered
+	// points from their line.  It is also the width
+	// kept free for a swarm drawn beside a plot,
+	// since the width of a swarm is not known until
+	// it is drawn.
+	Spread vg.Length
+
+	// GlyphStyle is the style of the points.
+	draw.GlyphStyle
+}
+
+// plot draws the points at the canvas positions
+// along the axis of the values.  The points are
+// centered at across, or drawn from across+beside
+// if the points are beside the plot.  If horiz is
+// true then the values are along the x axis.
+func (d *DataPoints) plot(c *draw.Canvas, along []vg.Length, across, beside vg.Length, horiz bool) {
+	if d.Layout == NoPoints {
+		return
+	}
+	if d.Beside {
+		across += beside
+	}
+	offs := layoutOffsets(d.Layout, along, d.Radius, d.Spread, 1, d.Beside)
+	for i, v := range along {
+		if horiz {
+			if c.ContainsX(v) {
+				c.DrawGlyphNoClip(d.GlyphStyle, draw.Point{v, across + offs[i]})
+			}
+			continue
+		}
+		if c.ContainsY(v) {
+			c.DrawGlyphNoClip(d.GlyphStyle, draw.Point{across + offs[i], v})
+		}
+	}
+}
+
+// besideExtent returns the range of offsets across
+// the plot, from its center, that is covered by the
+// points if they are drawn beside the plot from
+// beside, and whether they are drawn beside it.
+func (d *DataPoints) besideExtent(beside vg.Length) (min, max vg.Length, ok bool) {
+	if d.Layout == NoPoints || !d.Beside {
+		return 0, 0, false
+	}
+	return beside - d.Radius, beside + d.Spread + d.Radius, true
+}
+
+// layoutOffsets returns the offsets across a line of
+// glyphs of radius r at the positions along the line,
+// given by jitter of at most spread with the seed or
+// by swarm, depending on the layout.
+func layoutOffsets(layout PointLayout, along []vg.Length, r, spread vg.Length, seed int64, oneSided bool) []vg.Length {
+	if layout == SwarmPoints {
+		return swarm(along, r, oneSided)
+	}
+	return jitter(len(along), spread, seed, oneSided)
+}
+
+// jitter returns n random offsets of at most spread,
+// which are not negative if oneSided is true.  The
+// offsets are the same for each call with the same
+// n and seed.
+func jitter(n int, spread vg.Length, seed int64, oneSided bool) []vg.Length {
+	rnd := rand.New(rand.NewSource(seed))
+	offs := make([]vg.Length, n)
+	for i := range offs {
+		f := rnd.Float64()
+		if !oneSided {
+			f = 2*f - 1
+		}
+		offs[i] = vg.Length(f) * spread
+	}
+	return offs
+}
+
+// swarm returns the offsets across a line of glyphs
+// of radius r at the positions along the line, so
+// that no glyphs overlap and each glyph is as near
+// to the line as it can be placed, in order along
+// the line.  If oneSided is true then the offsets
+// are not negative.
+func swarm(along []vg.Length, r vg.Length, oneSided bool) []vg.Length {
+	order := make([]int, len(along))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Sort(byPosition{along, order})
+
+	d := float64(2 * r)
+	offs := make([]vg.Length, len(along))
+	for k, i := range order {
+		// Collect the placed glyphs that are near
+		// enough along the line to collide.
+		var near []int
+		for l := k - 1; l >= 0; l-- {
+			j := order[l]
+			if float64(along[i]-along[j]) >= d {
+				break
+			}
+			near = append(near, j)
+		}
+
+		// Try the line and each position touching a
+		// near glyph, keeping the nearest that fits.
+		cands := []float64{0}
+		for _, j := range near {
+			dy := float64(along[i] - along[j])
+			dx := math.Sqrt(d*d - dy*dy)
+			cands = append(cands, float64(offs[j])+dx, float64(offs[j])-dx)
+		}
+		best := math.Inf(1)
+		for _, x := range cands {
+			if (oneSided && x < 0) || math.Abs(x) >= math.Abs(best) {
+				continue
+			}
+			fits := true
+			for _, j := range near {
+				dx := x - float64(offs[j])
+				dy := float64(along[i] - along[j])
+				if dx*dx+dy*dy < d*d*(1-1e-9) {
+					fits = false
+					break
+				}
+			}
+			if fits {
+				best = x
+			}
+		}
+		offs[i] = vg.Length(best)
+	}
+	return offs
+}
+
+// byPosition sorts indices by the positions they index.
+type byPosition struct {
+	ls  []vg.Length
+	idx []int
+}
+
+func (b byPosition) Len() int           { return len(b.idx) }
+func (b byPosition) Less(i, j int) bool { return b.ls[b.idx[i]] < b.ls[b.idx[j]] }
+func (b byPosition) Swap(i, j int)      { b.idx[i], b.idx[j] = b.idx[j], b.idx[i] }
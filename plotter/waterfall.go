@@ -0,0 +1,236 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"errors"
+	"image/color"
+	"math"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+)
+
+// Waterfall implements the Plotter interface, drawing
+// a waterfall chart of a running total.  Each value is
+// a change to the total, drawn as a bar from the total
+// before the change to the total after it, and colored
+// by whether the total increases or decreases.  The
+// locations of the bars along the x axis are the
+// indices of their values, as for a BarChart.
+type Waterfall struct {
+	Values
+
+	// Totals are the indices of the bars that are
+	// drawn from zero to the running total, such as
+	// subtotals and a final total.  The values of
+	// total bars are not added to the running total.
+	Totals []int
+
+	// Width is the width of the bars.
+	Width vg.Length
+
+	// Offset is added to the x location of each bar.
+	// When the Offset is zero, the bars are drawn
+	// centered at their x location.
+	Offset vg.Length
+
+	// XMin is the X location of the first bar.
+	XMin float64
+
+	// Horizontal specifies whether the bars extend
+	// along the x axis rather than the y axis, with
+	// XMin and Offset applying to the y axis.
+	Horizontal bool
+
+	// Increase, Decrease and Total are the fill
+	// colors of the bars of increases, of decreases
+	// and of totals.
+	Increase, Decrease, Total color.Color
+
+	// LineStyle is the style of the outline of the bars.
+	draw.LineStyle
+
+	// ConnectorStyle is the style of the lines joining
+	// the end of each bar to the next bar.  If its
+	// Color is nil then no connectors are drawn.
+	ConnectorStyle draw.LineStyle
+}
+
+// NewWaterfall returns a new waterfall chart with a
+// bar for each of the changes to the running total.
+func NewWaterfall(vs Valuer, width vg.Length) (*Waterfall, error) {
+	if width <= 0 {
+		return nil, errors.New("Width parameter was not positive")
+	}
+	values, err := CopyValues(vs)
+	if err != nil {
+		return nil, err
+	}
+	return &Waterfall{
+		Values:    values,
+		Width:     width,
+		Increase:  color.RGBA{G: 0x90, A: 0xff},
+		Decrease:  color.RGBA{R: 0xc0, A: 0xff},
+		Total:     color.Gray{Y: 0x80},
+		LineStyle: DefaultLineStyle,
+		ConnectorStyle: draw.LineStyle{
+			Color:  color.Gray{Y: 0x60},
+			Width:  vg.Points(0.5),
+			Dashes: []vg.Length{vg.Points(2), vg.Points(2)},
+		},
+	}, nil
+}
+
+// spans returns the running totals from and to which
+// each bar is drawn, and whether each bar is a total.
+func (w *Waterfall) spans() (from, to []float64, total []bool) {
+	total = make([]bool, len(w.Values))
+	for _, i := range w.Totals {
+		if i >= 0 && i < len(total) {
+			total[i] = true
+		}
+	}
+	from = make([]float64, len(w.Values))
+	to = make([]float64, len(w.Values))
+	var sum float64
+	for i, v := range w.Values {
+		if total[i] {
+			to[i] = sum
+			continue
+		}
+		from[i] = sum
+		sum += v
+		to[i] = sum
+	}
+	return from, to, total
+}
+
+// bar returns the corners of the bar at the location i
+// from and to the values on the canvas, the first at
+// from and the second at to, and whether the location
+// of the bar is within the canvas.
+func (w *Waterfall) bar(c *draw.Canvas, plt *plot.Plot, i int, from, to float64) (base, end draw.Point, ok bool) {
+	trX, trY := plt.Transforms(c)
+	trLoc, trVal, contains := trX, trY, c.ContainsX
+	if w.Horizontal {
+		trLoc, trVal, contains = trY, trX, c.ContainsY
+	}
+	loc := trLoc(w.XMin + float64(i))
+	if !contains(loc) {
+		return draw.Point{}, draw.Point{}, false
+	}
+	lo := loc - w.Width/2 + w.Offset
+	hi := lo + w.Width
+	if w.Horizontal {
+		return draw.Point{trVal(from), lo}, draw.Point{trVal(to), hi}, true
+	}
+	return draw.Point{lo, trVal(from)}, draw.Point{hi, trVal(to)}, true
+}
+
+// Plot implements the plot.Plotter interface.
+func (w *Waterfall) Plot(c draw.Canvas, plt *plot.Plot) {
+	from, to, total := w.spans()
+	for i := range w.Values {
+		base, end, ok := w.bar(&c, plt, i, from[i], to[i])
+		if !ok {
+			continue
+		}
+		fill := w.Increase
+		switch {
+		case total[i]:
+			fill = w.Total
+		case to[i] < from[i]:
+			fill = w.Decrease
+		}
+		drawBar(&c, base, end, w.Horizontal, fill, w.LineStyle)
+	}
+
+	if w.ConnectorStyle.Color == nil {
+		return
+	}
+	for i := 1; i < len(w.Values); i++ {
+		_, prev, ok := w.bar(&c, plt, i-1, from[i-1], to[i-1])
+		if !ok {
+			continue
+		}
+		next, _, ok := w.bar(&c, plt, i, from[i], to[i])
+		if !ok {
+			continue
+		}
+		if w.Horizontal {
+			c.StrokeLines(w.ConnectorStyle, c.ClipLinesX([]draw.Point{{prev.X, prev.Y}, {prev.X, next.Y}})...)
+			continue
+		}
+		c.StrokeLines(w.ConnectorStyle, c.ClipLinesY([]draw.Point{{prev.X, prev.Y}, {next.X, prev.Y}})...)
+	}
+}
+
+// DataRange implements the plot.DataRanger interface.
+func (w *Waterfall) DataRange() (xmin, xmax, ymin, ymax float64) {
+	xmin = w.XMin
+	xmax = xmin + float64(len(w.Values)-1)
+	from, to, _ := w.spans()
+	ymin = math.Inf(1)
+	ymax = math.Inf(-1)
+	for i := range from {
+		ymin = math.Min(ymin, math.Min(from[i], to[i]))
+		ymax = math.Max(ymax, math.Max(from[i], to[i]))
+	}
+	if w.Horizontal {
+		return ymin, ymax, xmin, xmax
+	}
+	return
+}
+
+// Pick returns the index of the bar nearest to pt,
+// implementing the plot.Picker interface.
+func (w *Waterfall) Pick(c draw.Canvas, plt *plot.Plot, pt draw.Point, tol vg.Length) (int, vg.Length, bool) {
+	from, to, _ := w.spans()
+	pk := picker{tol: tol}
+	for i := range w.Values {
+		base, end, ok := w.bar(&c, plt, i, from[i], to[i])
+		if !ok {
+			continue
+		}
+		pk.add(i, rectDistance(base, end, pt))
+	}
+	return pk.result()
+}
+
+// GlyphBoxes implements the GlyphBoxer interface.
+func (w *Waterfall) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
+	boxes := make([]plot.GlyphBox, len(w.Values))
+	for i := range w.Values {
+		x := w.XMin + float64(i)
+		boxes[i].Rectangle = draw.Rectangle{
+			Min: draw.Point{X: w.Offset - w.Width/2},
+			Max: draw.Point{X: w.Offset + w.Width/2},
+		}
+		if w.Horizontal {
+			boxes[i].Y = plt.Y.Norm(x)
+			boxes[i].Rectangle = transposeRect(boxes[i].Rectangle)
+			continue
+		}
+		boxes[i].X = plt.X.Norm(x)
+	}
+	return boxes
+}
+
+// Thumbnail draws a bar of the Increase color,
+// implementing the plot.Thumbnailer interface.
+func (w *Waterfall) Thumbnail(c *draw.Canvas) {
+	barThumbnail(c, w.Increase, w.LineStyle)
+}
+
+// Thumbnailers returns thumbnailers of bars of the
+// Increase, Decrease and Total colors, for adding
+// the categories of bars to a legend.
+func (w *Waterfall) Thumbnailers() (increase, decrease, total plot.Thumbnailer) {
+	return barThumbnailer{w.Increase, w.LineStyle},
+		barThumbnailer{w.Decrease, w.LineStyle},
+		barThumbnailer{w.Total, w.LineStyle}
+}
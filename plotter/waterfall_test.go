@@ -0,0 +1,63 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/recorder"
+)
+
+func TestWaterfall(t *testing.T) {
+	w, err := NewWaterfall(Values{10, -4, 0, 3, -12, 0}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w.Totals = []int{2, 5}
+	from, to, total := w.spans()
+	if want := []float64{0, 10, 0, 6, 9, 0}; !reflect.DeepEqual(from, want) {
+		t.Errorf("unexpected bar starts: got:%v want:%v", from, want)
+	}
+	if want := []float64{10, 6, 6, 9, -3, -3}; !reflect.DeepEqual(to, want) {
+		t.Errorf("unexpected bar ends: got:%v want:%v", to, want)
+	}
+	if want := []bool{false, false, true, false, false, true}; !reflect.DeepEqual(total, want) {
+		t.Errorf("unexpected totals: got:%v want:%v", total, want)
+	}
+	xmin, xmax, ymin, ymax := w.DataRange()
+	if xmin != 0 || xmax != 5 || ymin != -3 || ymax != 10 {
+		t.Errorf("unexpected data range: got:%v,%v,%v,%v want:0,5,-3,10", xmin, xmax, ymin, ymax)
+	}
+
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.Add(w)
+	r, c := drawOn(p, w)
+	var fills []color.Color
+	var col color.Color
+	for _, a := range r.Actions {
+		switch a := a.(type) {
+		case *recorder.SetColor:
+			col = a.Color
+		case *recorder.Fill:
+			fills = append(fills, col)
+		}
+	}
+	want := []color.Color{w.Increase, w.Decrease, w.Total, w.Increase, w.Decrease, w.Total}
+	if !reflect.DeepEqual(fills, want) {
+		t.Errorf("unexpected bar colors: got:%v want:%v", fills, want)
+	}
+
+	trX, trY := p.Transforms(&c)
+	if i, _, ok := w.Pick(c, p, draw.Point{trX(4), trY(0)}, 0); !ok || i != 4 {
+		t.Errorf("unexpected pick: got:%d,%t want:4,true", i, ok)
+	}
+}
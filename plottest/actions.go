@@ -0,0 +1,232 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plottest
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/recorder"
+)
+
+// ActionTolerance specifies how different two recorded
+// action streams may be while still being considered to match.
+type ActionTolerance struct {
+	// Float is the maximum difference between two
+	// floating point values, either absolute or
+	// relative to the larger magnitude.
+	Float float64
+
+	// Text is the maximum distance, in points, between
+	// the positions at which two strings are drawn.
+	// Text positions depend on the metrics of the fonts
+	// used, so they are compared more loosely than other
+	// values.
+	Text vg.Length
+}
+
+// DefaultActionTolerance is the tolerance used when comparing
+// recorded actions if no other tolerance is specified.
+var DefaultActionTolerance = ActionTolerance{
+	Float: 1e-9,
+	Text:  0.5,
+}
+
+// CompareActions compares two recorded action streams, returning
+// an error describing the first difference found, or nil if the
+// streams match.
+//
+// Actions are compared structurally. Floating point values, including
+// vg.Length values, are considered equal if they differ by no more
+// than tol.Float, either absolutely or relative to the larger magnitude.
+// The positions of strings are equal if they are no more than tol.Text
+// apart. Images are equal if they have the same bounds and pixel colors.
+// Unexported fields, such as caller locations, are not compared.
+func CompareActions(got, want []recorder.Action, tol ActionTolerance) error {
+	n := len(got)
+	if len(want) < n {
+		n = len(want)
+	}
+	for i := 0; i < n; i++ {
+		path, ok := equalAction(got[i], want[i], tol)
+		if !ok {
+			return fmt.Errorf("plottest: action %d differs at %q:\n\tgot:  %s\n\twant: %s",
+				i, strings.TrimPrefix(path, "."), got[i].Call(), want[i].Call())
+		}
+	}
+	if len(got) != len(want) {
+		return fmt.Errorf("plottest: action count mismatch: got:%d want:%d", len(got), len(want))
+	}
+	return nil
+}
+
+// equalAction returns whether a and b are equal within tol. If they
+// are not, the path to the first differing element is returned.
+func equalAction(a, b recorder.Action, tol ActionTolerance) (string, bool) {
+	fa, ok := a.(*recorder.FillString)
+	if !ok {
+		return equalValue(reflect.ValueOf(a), reflect.ValueOf(b), "", tol.Float)
+	}
+	fb, ok := b.(*recorder.FillString)
+	if !ok {
+		return "", false
+	}
+	if dx, dy := fa.X-fb.X, fa.Y-fb.Y; math.Hypot(float64(dx), float64(dy)) > float64(tol.Text) {
+		if math.Abs(float64(dx)) > float64(tol.Text) {
+			return ".X", false
+		}
+		return ".Y", false
+	}
+	moved := *fa
+	moved.X, moved.Y = fb.X, fb.Y
+	return equalValue(reflect.ValueOf(&moved), reflect.ValueOf(fb), "", tol.Float)
+}
+
+// equalValue returns whether a and b are equal within tol. If they are
+// not, the path to the first differing element is returned.
+func equalValue(a, b reflect.Value, path string, tol float64) (string, bool) {
+	if !a.IsValid() || !b.IsValid() {
+		return path, a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return path, false
+	}
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return path, a.IsNil() == b.IsNil()
+		}
+		if a.Type() == imageType {
+			return path, equalImage(a.Interface().(image.Image), b.Interface().(image.Image))
+		}
+		return equalValue(a.Elem(), b.Elem(), path, tol)
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			f := a.Type().Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			p, ok := equalValue(a.Field(i), b.Field(i), path+"."+f.Name, tol)
+			if !ok {
+				return p, false
+			}
+		}
+		return path, true
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return path, false
+		}
+		for i := 0; i < a.Len(); i++ {
+			p, ok := equalValue(a.Index(i), b.Index(i), fmt.Sprintf("%s[%d]", path, i), tol)
+			if !ok {
+				return p, false
+			}
+		}
+		return path, true
+	case reflect.Float32, reflect.Float64:
+		return path, equalFloat(a.Float(), b.Float(), tol)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return path, a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return path, a.Uint() == b.Uint()
+	case reflect.String:
+		return path, a.String() == b.String()
+	case reflect.Bool:
+		return path, a.Bool() == b.Bool()
+	default:
+		panic(fmt.Sprintf("plottest: cannot compare values of kind %v", a.Kind()))
+	}
+}
+
+var imageType = reflect.TypeOf((*image.Image)(nil)).Elem()
+
+// equalImage returns whether a and b have the same
+// bounds and the same colors at every pixel.
+func equalImage(a, b image.Image) bool {
+	bounds := a.Bounds()
+	if bounds != b.Bounds() {
+		return false
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if color.NRGBA64Model.Convert(a.At(x, y)) != color.NRGBA64Model.Convert(b.At(x, y)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// equalFloat returns whether a and b are equal within tol, either
+// absolutely or relative to the larger of their magnitudes.
+func equalFloat(a, b, tol float64) bool {
+	if a == b || (math.IsNaN(a) && math.IsNaN(b)) {
+		return true
+	}
+	d := math.Abs(a - b)
+	return d <= tol || d <= tol*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// Record returns the actions used to draw p on a canvas
+// with the given size.
+func Record(p *plot.Plot, w, h vg.Length) *recorder.Canvas {
+	c := recorder.New(72)
+	p.Draw(draw.NewCanvas(c, w, h))
+	return c
+}
+
+// CheckRecording records the actions used to draw p on a canvas with
+// the given size and compares them with the golden recording in the
+// file golden using CompareActions with the given tolerance. If the
+// -update-goldens flag is set, the golden file is written instead
+// using the recorder text format.
+func CheckRecording(t testing.TB, p *plot.Plot, w, h vg.Length, golden string, tol ActionTolerance) {
+	got := Record(p, w, h)
+	if *UpdateGoldens {
+		f, err := os.Create(golden)
+		if err != nil {
+			t.Errorf("failed to create golden recording: %v", err)
+			return
+		}
+		err = got.Encode(f, recorder.Text)
+		if err != nil {
+			t.Errorf("failed to write golden recording: %v", err)
+		}
+		err = f.Close()
+		if err != nil {
+			t.Errorf("failed to close golden recording: %v", err)
+		}
+		return
+	}
+
+	f, err := os.Open(golden)
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.Errorf("golden recording %s does not exist: run with -update-goldens to create it", golden)
+			return
+		}
+		t.Errorf("failed to open golden recording: %v", err)
+		return
+	}
+	defer f.Close()
+	want, err := recorder.Decode(f)
+	if err != nil {
+		t.Errorf("failed to read golden recording %s: %v", golden, err)
+		return
+	}
+	err = CompareActions(got.Actions, want.Actions, tol)
+	if err != nil {
+		t.Errorf("%s: %v", golden, err)
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package plottest provides utilities for visual regression
+// testing of plots.
+//
+// Plots may be checked against golden PNG images, allowing
+// for small perceptual differences such as those caused by
+// changes in font rendering or antialiasing, or against golden
+// recordings of the vg.Canvas actions used to draw them, allowing
+// for small numerical differences.
+//
+// Golden files are created or updated by running the tests with
+// the -update-goldens flag.
+package plottest
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+	"github.com/gonum/plot/vg/vgimg"
+)
+
+// UpdateGoldens specifies whether golden files should be
+// written rather than compared against.
+var UpdateGoldens = flag.Bool("update-goldens", false, "write golden files instead of comparing against them")
+
+// Tolerance specifies how different two images may be
+// while still being considered to match.
+type Tolerance struct {
+	// Delta is the maximum perceptual color
+	// difference, in the range [0, 1], between
+	// two pixels for them to be considered equal.
+	Delta float64
+
+	// Fraction is the maximum fraction of pixels
+	// that may differ between the two images.
+	Fraction float64
+
+	// AntiAlias specifies whether differing pixels
+	// that match a pixel adjacent to the corresponding
+	// position in the other image are ignored. This
+	// allows for small shifts in antialiased edges.
+	AntiAlias bool
+}
+
+// DefaultTolerance is the tolerance used when comparing
+// plot images if no other tolerance is specified.
+var DefaultTolerance = Tolerance{
+	Delta:     0.1,
+	Fraction:  0.001,
+	AntiAlias: true,
+}
+
+// Result holds the result of comparing two images.
+type Result struct {
+	// Pixels is the number of pixels compared.
+	Pixels int
+
+	// Differing is the number of pixels that
+	// differ by more than the tolerance.
+	Differing int
+
+	// AntiAliased is the number of pixels that
+	// differ but were ignored as antialiasing
+	// differences.
+	AntiAliased int
+
+	// Diff is an image highlighting the differences
+	// between the two images. Differing pixels are
+	// shown in red and ignored antialiasing
+	// differences are shown in yellow over a faded
+	// copy of the wanted image.
+	Diff *image.RGBA
+}
+
+// Fraction returns the fraction of pixels that differ.
+func (r Result) Fraction() float64 {
+	if r.Pixels == 0 {
+		return 0
+	}
+	return float64(r.Differing) / float64(r.Pixels)
+}
+
+// Match returns whether the result is within the given tolerance.
+func (r Result) Match(tol Tolerance) bool {
+	return r.Fraction() <= tol.Fraction
+}
+
+// CompareImages compares got against want using the given tolerance.
+// It returns an error if the images do not have the same size.
+func CompareImages(got, want image.Image, tol Tolerance) (Result, error) {
+	gb, wb := got.Bounds(), want.Bounds()
+	if gb.Dx() != wb.Dx() || gb.Dy() != wb.Dy() {
+		return Result{}, fmt.Errorf("plottest: image size mismatch: got:%dx%d want:%dx%d", gb.Dx(), gb.Dy(), wb.Dx(), wb.Dy())
+	}
+
+	res := Result{
+		Pixels: wb.Dx() * wb.Dy(),
+		Diff:   image.NewRGBA(image.Rect(0, 0, wb.Dx(), wb.Dy())),
+	}
+	for y := 0; y < wb.Dy(); y++ {
+		for x := 0; x < wb.Dx(); x++ {
+			wc := want.At(wb.Min.X+x, wb.Min.Y+y)
+			gc := got.At(gb.Min.X+x, gb.Min.Y+y)
+			switch {
+			case colorDelta(gc, wc) <= tol.Delta:
+				res.Diff.Set(x, y, faded(wc))
+			case tol.AntiAlias && hasNeighbour(gc, want, wb, x, y, tol.Delta) && hasNeighbour(wc, got, gb, x, y, tol.Delta):
+				res.AntiAliased++
+				res.Diff.Set(x, y, color.RGBA{R: 0xff, G: 0xff, A: 0xff})
+			default:
+				res.Differing++
+				res.Diff.Set(x, y, color.RGBA{R: 0xff, A: 0xff})
+			}
+		}
+	}
+	return res, nil
+}
+
+// hasNeighbour returns whether any pixel of img adjacent to the
+// position (x, y) relative to the bounds b is within delta of c.
+func hasNeighbour(c color.Color, img image.Image, b image.Rectangle, x, y int, delta float64) bool {
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			p := image.Pt(b.Min.X+x+dx, b.Min.Y+y+dy)
+			if !p.In(b) {
+				continue
+			}
+			if colorDelta(c, img.At(p.X, p.Y)) <= delta {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// maxDelta is the largest possible weighted squared YIQ
+// distance between two opaque colors.
+const maxDelta = 35215.0 / (255 * 255)
+
+// colorDelta returns the perceptual difference between a and b
+// in the range [0, 1]. The colors are composited onto a white
+// background and compared using luma-weighted distance in YIQ
+// color space.
+func colorDelta(a, b color.Color) float64 {
+	ay, ai, aq := yiq(a)
+	by, bi, bq := yiq(b)
+	y, i, q := ay-by, ai-bi, aq-bq
+	return math.Min(math.Sqrt((0.5053*y*y+0.299*i*i+0.1957*q*q)/maxDelta), 1)
+}
+
+// yiq returns the YIQ components of c after compositing
+// onto a white background.
+func yiq(c color.Color) (y, i, q float64) {
+	r, g, b, a := c.RGBA()
+	const max = 0xffff
+	bg := float64(max - a)
+	rf := (float64(r) + bg) / max
+	gf := (float64(g) + bg) / max
+	bf := (float64(b) + bg) / max
+	y = 0.29889531*rf + 0.58662247*gf + 0.11448223*bf
+	i = 0.59597799*rf - 0.27417610*gf - 0.32180189*bf
+	q = 0.21147017*rf - 0.52261711*gf + 0.31114694*bf
+	return y, i, q
+}
+
+// faded returns a light gray with the luma of c, used
+// for the background of diff images.
+func faded(c color.Color) color.Color {
+	y, _, _ := yiq(c)
+	v := uint8(255 - (1-y)*0.1*255)
+	return color.RGBA{R: v, G: v, B: v, A: 0xff}
+}
+
+// Render returns an image of p drawn with the given size
+// and resolution.
+func Render(p *plot.Plot, w, h vg.Length, dpi int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, int(w/vg.Inch*vg.Length(dpi)+0.5), int(h/vg.Inch*vg.Length(dpi)+0.5)))
+	c := vgimg.NewWith(vgimg.UseImage(img), vgimg.UseDPI(dpi))
+	p.Draw(draw.New(c))
+	return img
+}
+
+// CheckImage renders p with the given size and resolution and
+// compares the result with the golden PNG image in the file golden
+// using the given tolerance. If the -update-goldens flag is set,
+// the golden file is written instead.
+//
+// On failure the rendered image and a diff image are written next
+// to the golden file with the suffixes "_got.png" and "_diff.png".
+// A missing golden file is a failure.
+func CheckImage(t testing.TB, p *plot.Plot, w, h vg.Length, dpi int, golden string, tol Tolerance) {
+	got := Render(p, w, h, dpi)
+	if *UpdateGoldens {
+		err := writePNG(golden, got)
+		if err != nil {
+			t.Errorf("failed to write golden image: %v", err)
+		}
+		return
+	}
+
+	want, err := readPNG(golden)
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.Errorf("golden image %s does not exist: run with -update-goldens to create it", golden)
+			return
+		}
+		t.Errorf("failed to read golden image: %v", err)
+		return
+	}
+
+	base := strings.TrimSuffix(golden, filepath.Ext(golden))
+	res, err := CompareImages(got, want, tol)
+	if err != nil {
+		t.Errorf("%s: %v", golden, err)
+		writeFailure(t, base+"_got.png", got)
+		return
+	}
+	if !res.Match(tol) {
+		t.Errorf("%s: image mismatch: %d of %d pixels differ (%.3g%%)",
+			golden, res.Differing, res.Pixels, 100*res.Fraction())
+		writeFailure(t, base+"_got.png", got)
+		writeFailure(t, base+"_diff.png", res.Diff)
+	}
+}
+
+// writeFailure writes img to path for inspection of a test failure.
+func writeFailure(t testing.TB, path string, img image.Image) {
+	err := writePNG(path, img)
+	if err != nil {
+		t.Errorf("failed to write %s: %v", path, err)
+		return
+	}
+	t.Logf("wrote %s", path)
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = png.Encode(f, img)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,207 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plottest
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"testing"
+
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/recorder"
+)
+
+// square returns a white image with a black square of side 4 at (x, y).
+func square(x, y int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	draw.Draw(img, img.Bounds(), image.White, image.ZP, draw.Src)
+	draw.Draw(img, image.Rect(x, y, x+4, y+4), image.Black, image.ZP, draw.Src)
+	return img
+}
+
+func TestCompareImages(t *testing.T) {
+	strict := Tolerance{Delta: 0.1}
+	for i, test := range []struct {
+		got, want   image.Image
+		tol         Tolerance
+		differing   int
+		antiAliased int
+		match       bool
+	}{
+		{got: square(2, 2), want: square(2, 2), tol: strict, match: true},
+		{got: square(3, 2), want: square(2, 2), tol: strict, differing: 8},
+		{got: square(3, 2), want: square(2, 2), tol: DefaultTolerance, antiAliased: 8, match: true},
+		{got: square(6, 6), want: square(2, 2), tol: DefaultTolerance, differing: 30, antiAliased: 2},
+		{got: square(6, 6), want: square(2, 2), tol: Tolerance{Delta: 0.1, Fraction: 0.5}, differing: 32, match: true},
+	} {
+		res, err := CompareImages(test.got, test.want, test.tol)
+		if err != nil {
+			t.Fatalf("unexpected error for test %d: %v", i, err)
+		}
+		if res.Pixels != 100 {
+			t.Errorf("unexpected pixel count for test %d: got:%d want:100", i, res.Pixels)
+		}
+		if res.Differing != test.differing {
+			t.Errorf("unexpected differing count for test %d: got:%d want:%d", i, res.Differing, test.differing)
+		}
+		if res.AntiAliased != test.antiAliased {
+			t.Errorf("unexpected antialiased count for test %d: got:%d want:%d", i, res.AntiAliased, test.antiAliased)
+		}
+		if res.Match(test.tol) != test.match {
+			t.Errorf("unexpected match for test %d: got:%t want:%t", i, res.Match(test.tol), test.match)
+		}
+		if res.Diff.Bounds() != test.want.Bounds() {
+			t.Errorf("unexpected diff image bounds for test %d: got:%v want:%v", i, res.Diff.Bounds(), test.want.Bounds())
+		}
+	}
+
+	_, err := CompareImages(image.NewRGBA(image.Rect(0, 0, 2, 2)), square(0, 0), DefaultTolerance)
+	if err == nil {
+		t.Error("expected error for mismatched image sizes")
+	}
+}
+
+func TestColorDelta(t *testing.T) {
+	for _, test := range []struct {
+		a, b     color.Color
+		min, max float64
+	}{
+		{a: color.White, b: color.White, min: 0, max: 0},
+		{a: color.Black, b: color.Black, min: 0, max: 0},
+		{a: color.Transparent, b: color.White, min: 0, max: 0},
+		{a: color.Black, b: color.White, min: 0.9, max: 1},
+		{a: color.Gray{Y: 0x80}, b: color.Gray{Y: 0x84}, min: 0, max: 0.05},
+		{a: color.RGBA{R: 0xff, A: 0xff}, b: color.RGBA{B: 0xff, A: 0xff}, min: 0.5, max: 1},
+	} {
+		got := colorDelta(test.a, test.b)
+		if got < test.min || got > test.max {
+			t.Errorf("unexpected delta between %v and %v: got:%v want in [%v, %v]", test.a, test.b, got, test.min, test.max)
+		}
+		if rev := colorDelta(test.b, test.a); rev != got {
+			t.Errorf("asymmetric delta between %v and %v: %v != %v", test.a, test.b, got, rev)
+		}
+	}
+}
+
+func TestCompareActions(t *testing.T) {
+	path := func(x vg.Length) vg.Path {
+		return vg.Path{{Type: vg.MoveComp, X: x, Y: 1}, {Type: vg.LineComp, X: 2, Y: 3}}
+	}
+	want := []recorder.Action{
+		&recorder.SetColor{Color: color.Gray16{Y: 10}},
+		&recorder.SetLineDash{Dashes: []vg.Length{1, 2}, Offsets: 0.5},
+		&recorder.Stroke{Path: path(1)},
+		&recorder.FillString{Font: "Times-Roman", Size: 12, X: 1, Y: 2, String: "text"},
+	}
+	strict := ActionTolerance{Float: 1e-9}
+	text := ActionTolerance{Float: 1e-9, Text: 0.5}
+	for i, test := range []struct {
+		got  []recorder.Action
+		tol  ActionTolerance
+		want string
+	}{
+		{got: want},
+		{
+			got: []recorder.Action{
+				&recorder.SetColor{Color: color.Gray16{Y: 10}},
+				&recorder.SetLineDash{Dashes: []vg.Length{1, 2}, Offsets: 0.5},
+				&recorder.Stroke{Path: path(1 + 1e-12)},
+				&recorder.FillString{Font: "Times-Roman", Size: 12, X: 1, Y: 2, String: "text"},
+			},
+			tol: strict,
+		},
+		{
+			got: []recorder.Action{
+				&recorder.SetColor{Color: color.Gray16{Y: 10}},
+				&recorder.SetLineDash{Dashes: []vg.Length{1, 2}, Offsets: 0.5},
+				&recorder.Stroke{Path: path(1.1)},
+			},
+			tol:  strict,
+			want: `plottest: action 2 differs at "Path[0].X"`,
+		},
+		{
+			got: []recorder.Action{
+				&recorder.SetColor{Color: color.Gray{Y: 10}},
+			},
+			tol:  strict,
+			want: `plottest: action 0 differs at "Color"`,
+		},
+		{
+			got: []recorder.Action{
+				&recorder.SetColor{Color: color.Gray16{Y: 10}},
+				&recorder.SetLineDash{Dashes: []vg.Length{1}, Offsets: 0.5},
+			},
+			tol:  strict,
+			want: `plottest: action 1 differs at "Dashes"`,
+		},
+		{
+			got:  want[:3],
+			tol:  strict,
+			want: "plottest: action count mismatch: got:3 want:4",
+		},
+		{
+			got: []recorder.Action{
+				&recorder.SetColor{Color: color.Gray16{Y: 10}},
+				&recorder.SetLineDash{Dashes: []vg.Length{1, 2}, Offsets: 0.5},
+				&recorder.Stroke{Path: path(1)},
+				&recorder.FillString{Font: "Times-Roman", Size: 12, X: 1, Y: 2, String: "test"},
+			},
+			tol:  strict,
+			want: `plottest: action 3 differs at "String"`,
+		},
+		{
+			got: []recorder.Action{
+				&recorder.SetColor{Color: color.Gray16{Y: 10}},
+				&recorder.SetLineDash{Dashes: []vg.Length{1, 2}, Offsets: 0.5},
+				&recorder.Stroke{Path: path(1)},
+				&recorder.FillString{Font: "Times-Roman", Size: 12, X: 1.3, Y: 1.8, String: "text"},
+			},
+			tol: text,
+		},
+		{
+			got: []recorder.Action{
+				&recorder.SetColor{Color: color.Gray16{Y: 10}},
+				&recorder.SetLineDash{Dashes: []vg.Length{1, 2}, Offsets: 0.5},
+				&recorder.Stroke{Path: path(1)},
+				&recorder.FillString{Font: "Times-Roman", Size: 12, X: 1.3, Y: 1.8, String: "text"},
+			},
+			tol:  strict,
+			want: `plottest: action 3 differs at "X"`,
+		},
+		{
+			got: []recorder.Action{
+				&recorder.SetColor{Color: color.Gray16{Y: 10}},
+				&recorder.SetLineDash{Dashes: []vg.Length{1, 2}, Offsets: 0.5},
+				&recorder.Stroke{Path: path(1)},
+				&recorder.FillString{Font: "Times-Roman", Size: 12, X: 1, Y: 3, String: "text"},
+			},
+			tol:  text,
+			want: `plottest: action 3 differs at "Y"`,
+		},
+		{
+			got: []recorder.Action{
+				&recorder.SetColor{Color: color.Gray16{Y: 10}},
+				&recorder.SetLineDash{Dashes: []vg.Length{1, 2}, Offsets: 0.5},
+				&recorder.Stroke{Path: path(1)},
+				&recorder.FillString{Font: "Times-Roman", Size: 12.1, X: 1, Y: 2, String: "text"},
+			},
+			tol:  text,
+			want: `plottest: action 3 differs at "Size"`,
+		},
+	} {
+		err := CompareActions(test.got, want, test.tol)
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("unexpected error for test %d: %v", i, err)
+			}
+			continue
+		}
+		if err == nil || !strings.HasPrefix(err.Error(), test.want) {
+			t.Errorf("unexpected error for test %d:\n\tgot: %v\n\twant prefix: %s", i, err, test.want)
+		}
+	}
+}
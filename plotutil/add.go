@@ -18,6 +18,13 @@ type combineXYs struct{ xs, ys plotter.Valuer }
 func (c combineXYs) Len() int                    { return c.xs.Len() }
 func (c combineXYs) XY(i int) (float64, float64) { return c.xs.Value(i), c.ys.Value(i) }
 
+// legendEntry holds a legend name and its thumbnails so
+// that entries can be added to the legend in argument order.
+type legendEntry struct {
+	name   string
+	thumbs []plot.Thumbnailer
+}
+
 // AddStackedAreaPlots adds stacked area plot plotters to a plot.
 // The variadic arguments must be either strings
 // or plotter.Valuers.  Each valuer adds a stacked area
@@ -33,7 +40,7 @@ func (c combineXYs) XY(i int) (float64, float64) { return c.xs.Value(i), c.ys.Va
 // to the plot, and the error is returned.
 func AddStackedAreaPlots(plt *plot.Plot, xs plotter.Valuer, vs ...interface{}) error {
 	var ps []plot.Plotter
-	names := make(map[*plotter.Line]string)
+	var names []legendEntry
 	name := ""
 	var i int
 
@@ -61,7 +68,7 @@ func AddStackedAreaPlots(plt *plot.Plot, xs plotter.Valuer, vs ...interface{}) e
 			ps = append(ps, l)
 
 			if name != "" {
-				names[l] = name
+				names = append(names, legendEntry{name: name, thumbs: []plot.Thumbnailer{l}})
 				name = ""
 			}
 
@@ -71,8 +78,8 @@ func AddStackedAreaPlots(plt *plot.Plot, xs plotter.Valuer, vs ...interface{}) e
 	}
 
 	plt.Add(ps...)
-	for p, n := range names {
-		plt.Legend.Add(n, p)
+	for _, e := range names {
+		plt.Legend.Add(e.name, e.thumbs...)
 	}
 
 	return nil
@@ -130,7 +137,7 @@ func AddBoxPlots(plt *plot.Plot, width vg.Length, vs ...interface{}) error {
 // to the plot, and the error is returned.
 func AddScatters(plt *plot.Plot, vs ...interface{}) error {
 	var ps []plot.Plotter
-	names := make(map[*plotter.Scatter]string)
+	var names []legendEntry
 	name := ""
 	var i int
 	for _, v := range vs {
@@ -148,7 +155,7 @@ func AddScatters(plt *plot.Plot, vs ...interface{}) error {
 			i++
 			ps = append(ps, s)
 			if name != "" {
-				names[s] = name
+				names = append(names, legendEntry{name: name, thumbs: []plot.Thumbnailer{s}})
 				name = ""
 			}
 
@@ -157,8 +164,8 @@ func AddScatters(plt *plot.Plot, vs ...interface{}) error {
 		}
 	}
 	plt.Add(ps...)
-	for p, n := range names {
-		plt.Legend.Add(n, p)
+	for _, e := range names {
+		plt.Legend.Add(e.name, e.thumbs...)
 	}
 	return nil
 }
@@ -176,7 +183,7 @@ func AddScatters(plt *plot.Plot, vs ...interface{}) error {
 // to the plot, and the error is returned.
 func AddLines(plt *plot.Plot, vs ...interface{}) error {
 	var ps []plot.Plotter
-	names := make(map[*plotter.Line]string)
+	var names []legendEntry
 	name := ""
 	var i int
 	for _, v := range vs {
@@ -194,7 +201,7 @@ func AddLines(plt *plot.Plot, vs ...interface{}) error {
 			i++
 			ps = append(ps, l)
 			if name != "" {
-				names[l] = name
+				names = append(names, legendEntry{name: name, thumbs: []plot.Thumbnailer{l}})
 				name = ""
 			}
 
@@ -203,8 +210,8 @@ func AddLines(plt *plot.Plot, vs ...interface{}) error {
 		}
 	}
 	plt.Add(ps...)
-	for p, n := range names {
-		plt.Legend.Add(n, p)
+	for _, e := range names {
+		plt.Legend.Add(e.name, e.thumbs...)
 	}
 	return nil
 }
@@ -222,7 +229,7 @@ func AddLines(plt *plot.Plot, vs ...interface{}) error {
 // to the plot, and the error is returned.
 func AddLinePoints(plt *plot.Plot, vs ...interface{}) error {
 	var ps []plot.Plotter
-	names := make(map[[2]plot.Thumbnailer]string)
+	var names []legendEntry
 	name := ""
 	var i int
 	for _, v := range vs {
@@ -242,7 +249,7 @@ func AddLinePoints(plt *plot.Plot, vs ...interface{}) error {
 			i++
 			ps = append(ps, l, s)
 			if name != "" {
-				names[[2]plot.Thumbnailer{l, s}] = name
+				names = append(names, legendEntry{name: name, thumbs: []plot.Thumbnailer{l, s}})
 				name = ""
 			}
 
@@ -251,8 +258,8 @@ func AddLinePoints(plt *plot.Plot, vs ...interface{}) error {
 		}
 	}
 	plt.Add(ps...)
-	for ps, n := range names {
-		plt.Legend.Add(n, ps[0], ps[1])
+	for _, e := range names {
+		plt.Legend.Add(e.name, e.thumbs...)
 	}
 	return nil
 }
@@ -2,75 +2,44 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ignore
-
-package main
+package plotutil_test
 
 import (
 	"math/rand"
+	"path/filepath"
+	"testing"
 
 	"github.com/gonum/plot"
 	"github.com/gonum/plot/plotter"
+	"github.com/gonum/plot/plottest"
 	"github.com/gonum/plot/plotutil"
+	"github.com/gonum/plot/vg"
 )
 
 var examples = []struct {
 	name   string
 	mkplot func() *plot.Plot
 }{
-	{"example_errpoints", Example_errpoints},
-	{"example_stackedAreaChart", Example_stackedAreaChart},
+	{"example_errpoints", exampleErrpoints},
+	{"example_stackedAreaChart", exampleStackedAreaChart},
 }
 
-func main() {
+// TestExamples checks the example plots against golden recordings
+// in testdata. The recordings do not depend on the rasterizer, so
+// they are compared rather than rendered images. Run the tests with
+// -update-goldens to regenerate the golden files after an intended
+// change.
+func TestExamples(t *testing.T) {
 	for _, ex := range examples {
-		drawEps(ex.name, ex.mkplot)
-		drawSvg(ex.name, ex.mkplot)
-		drawPng(ex.name, ex.mkplot)
-		drawTiff(ex.name, ex.mkplot)
-		drawJpg(ex.name, ex.mkplot)
-		drawPdf(ex.name, ex.mkplot)
-	}
-}
-
-func drawEps(name string, mkplot func() *plot.Plot) {
-	if err := mkplot().Save(4, 4, name+".eps"); err != nil {
-		panic(err)
-	}
-}
-
-func drawPdf(name string, mkplot func() *plot.Plot) {
-	if err := mkplot().Save(4, 4, name+".pdf"); err != nil {
-		panic(err)
-	}
-}
-
-func drawSvg(name string, mkplot func() *plot.Plot) {
-	if err := mkplot().Save(4, 4, name+".svg"); err != nil {
-		panic(err)
-	}
-}
-
-func drawPng(name string, mkplot func() *plot.Plot) {
-	if err := mkplot().Save(4, 4, name+".png"); err != nil {
-		panic(err)
-	}
-}
-
-func drawTiff(name string, mkplot func() *plot.Plot) {
-	if err := mkplot().Save(4, 4, name+".tiff"); err != nil {
-		panic(err)
+		golden := filepath.Join("testdata", ex.name)
+		plottest.CheckRecording(t, ex.mkplot(), 4*vg.Inch, 4*vg.Inch, golden+".vgrec", plottest.DefaultActionTolerance)
 	}
 }
 
-func drawJpg(name string, mkplot func() *plot.Plot) {
-	if err := mkplot().Save(4, 4, name+".jpg"); err != nil {
-		panic(err)
-	}
-}
+// exampleErrpoints draws some error points.
+func exampleErrpoints() *plot.Plot {
+	rand.Seed(int64(0))
 
-// Example_errpoints draws some error points.
-func Example_errpoints() *plot.Plot {
 	// Get some random data.
 	n, m := 5, 10
 	pts := make([]plotter.XYer, n)
@@ -122,7 +91,7 @@ func (n stackValues) Value(i int) float64 {
 }
 
 // An example of making a stacked area chart.
-func Example_stackedAreaChart() *plot.Plot {
+func exampleStackedAreaChart() *plot.Plot {
 	p, err := plot.New()
 	if err != nil {
 		panic(err)
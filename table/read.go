@@ -0,0 +1,288 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package table
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMissing are the fields read as missing
+// values if no missing value tokens are specified.
+var DefaultMissing = []string{"", "NA", "N/A", "NaN", "nan", "null", "-"}
+
+// DefaultTimeLayouts are the layouts of times tried
+// if no time layouts are specified.
+var DefaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Reader reads a table from delimited text.  The zero
+// value of a Reader reads CSV without a header.
+type Reader struct {
+	// Comma is the field delimiter, ',' for CSV
+	// and '\t' for TSV.  If Comma is zero, ','
+	// is used.
+	Comma rune
+
+	// Comment, if not zero, is the character that
+	// begins a comment line.
+	Comment rune
+
+	// Header specifies whether the first record
+	// holds the names of the columns.
+	Header bool
+
+	// Columns, if not empty, selects the columns
+	// that are read, in order.  Each column is
+	// selected by its name in the header or, if no
+	// column has that name, by its index.
+	Columns []string
+
+	// Missing are the fields that are read as
+	// missing values.  Surrounding white space is
+	// ignored.  If Missing is nil, DefaultMissing
+	// is used.
+	Missing []string
+
+	// Kinds, if not nil, gives the kinds of the
+	// columns by their names in the table, instead
+	// of inferring them.  A column is inferred to
+	// be a Float column if all of its fields that
+	// are not missing are numbers, otherwise a Time
+	// column if all are times in one of the time
+	// layouts, and otherwise a String column.
+	Kinds map[string]Kind
+
+	// TimeLayouts are the layouts in the format of
+	// the time package that times may be written
+	// in.  If TimeLayouts is nil, DefaultTimeLayouts
+	// are used.
+	TimeLayouts []string
+
+	// Location is the location of times with no
+	// time zone.  If Location is nil, UTC is used.
+	Location *time.Location
+}
+
+// NewReader returns a new Reader of CSV
+// with a header.
+func NewReader() *Reader {
+	return &Reader{Comma: ',', Header: true}
+}
+
+// ReadFile reads a table with a header from the named
+// file, which is read as TSV if its extension is .tsv
+// or .tab and as CSV otherwise.
+func ReadFile(name string) (*Table, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := NewReader()
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".tsv", ".tab":
+		r.Comma = '\t'
+	}
+	return r.Read(f)
+}
+
+// Read reads all of the records of the table from in.
+func (r *Reader) Read(in io.Reader) (*Table, error) {
+	cr := csv.NewReader(in)
+	if r.Comma != 0 {
+		cr.Comma = r.Comma
+	}
+	cr.Comment = r.Comment
+	if r.Comma == '\t' {
+		cr.LazyQuotes = true
+	}
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("table: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("table: no records")
+	}
+
+	var names []string
+	if r.Header {
+		names, records = records[0], records[1:]
+	} else {
+		names = make([]string, len(records[0]))
+		for i := range names {
+			names[i] = strconv.Itoa(i)
+		}
+	}
+	for i := range names {
+		names[i] = strings.TrimSpace(names[i])
+	}
+
+	idx, err := r.selected(names)
+	if err != nil {
+		return nil, err
+	}
+	t := &Table{Columns: make([]*Column, len(idx))}
+	for j, i := range idx {
+		c := &Column{
+			Name:    names[i],
+			Strings: make([]string, len(records)),
+		}
+		for k, rec := range records {
+			c.Strings[k] = rec[i]
+		}
+		kind, ok := r.Kinds[c.Name]
+		if !ok {
+			kind = r.infer(c.Strings)
+		}
+		if err := r.parse(c, kind); err != nil {
+			return nil, err
+		}
+		t.Columns[j] = c
+	}
+	return t, nil
+}
+
+// selected returns the indices of the columns
+// selected by r.Columns.
+func (r *Reader) selected(names []string) ([]int, error) {
+	if len(r.Columns) == 0 {
+		idx := make([]int, len(names))
+		for i := range idx {
+			idx[i] = i
+		}
+		return idx, nil
+	}
+	idx := make([]int, len(r.Columns))
+outer:
+	for j, sel := range r.Columns {
+		for i, name := range names {
+			if name == sel {
+				idx[j] = i
+				continue outer
+			}
+		}
+		i, err := strconv.Atoi(sel)
+		if err != nil || i < 0 || i >= len(names) {
+			return nil, fmt.Errorf("table: no column %q", sel)
+		}
+		idx[j] = i
+	}
+	return idx, nil
+}
+
+// isMissing returns whether the field
+// is a missing value.
+func (r *Reader) isMissing(field string) bool {
+	missing := r.Missing
+	if missing == nil {
+		missing = DefaultMissing
+	}
+	field = strings.TrimSpace(field)
+	for _, m := range missing {
+		if field == m {
+			return true
+		}
+	}
+	return false
+}
+
+// infer returns the kind of a column of the fields.
+func (r *Reader) infer(fields []string) Kind {
+	kind := Float
+	for _, f := range fields {
+		if r.isMissing(f) {
+			continue
+		}
+		if _, err := strconv.ParseFloat(strings.TrimSpace(f), 64); err != nil {
+			kind = Time
+			break
+		}
+	}
+	if kind == Float {
+		return Float
+	}
+	if _, ok := r.timeLayout(fields); ok {
+		return Time
+	}
+	return String
+}
+
+// timeLayout returns the first time layout in which
+// all of the fields that are not missing are times.
+func (r *Reader) timeLayout(fields []string) (string, bool) {
+	layouts := r.TimeLayouts
+	if layouts == nil {
+		layouts = DefaultTimeLayouts
+	}
+outer:
+	for _, layout := range layouts {
+		for _, f := range fields {
+			if r.isMissing(f) {
+				continue
+			}
+			if _, err := time.Parse(layout, strings.TrimSpace(f)); err != nil {
+				continue outer
+			}
+		}
+		return layout, true
+	}
+	return "", false
+}
+
+// parse sets the kind and the values of c from its fields.
+func (r *Reader) parse(c *Column, kind Kind) error {
+	c.Kind = kind
+	c.Values = make([]float64, len(c.Strings))
+	var layout string
+	if kind == Time {
+		var ok bool
+		layout, ok = r.timeLayout(c.Strings)
+		if !ok {
+			return fmt.Errorf("table: column %q: no time layout matches all times", c.Name)
+		}
+	}
+	loc := r.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	for i, f := range c.Strings {
+		if kind == String || r.isMissing(f) {
+			c.Values[i] = math.NaN()
+			continue
+		}
+		f = strings.TrimSpace(f)
+		switch kind {
+		case Float:
+			v, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				return fmt.Errorf("table: column %q, row %d: %v", c.Name, i, err)
+			}
+			c.Values[i] = v
+		case Time:
+			t, err := time.ParseInLocation(layout, f, loc)
+			if err != nil {
+				return fmt.Errorf("table: column %q, row %d: %v", c.Name, i, err)
+			}
+			c.Values[i] = float64(t.UnixNano()) / 1e9
+		default:
+			return fmt.Errorf("table: column %q: invalid kind %v", c.Name, kind)
+		}
+	}
+	return nil
+}
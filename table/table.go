@@ -0,0 +1,322 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package table reads delimited text, such as CSV and TSV
+// files, into tables of columns that implement the data
+// interfaces of the plotter package.
+//
+// A table is read with a Reader, or with ReadFile, and its
+// columns are selected by name or index to make the views
+// that plotters are made from:
+//
+//	t, err := table.ReadFile("data.csv")
+//	if err != nil {
+//		return err
+//	}
+//	xys, err := t.XYs("time", "temperature")
+//	if err != nil {
+//		return err
+//	}
+//	l, err := plotter.NewLine(xys)
+//
+// The views Labels and YErrors have no Len method so that
+// they can be embedded alongside an XYs, for example to
+// make plotter.NewLabels or plotter.NewYErrorBars.
+package table
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Kind is the kind of the values of a column.
+type Kind int
+
+const (
+	// Float columns hold numbers.
+	Float Kind = iota
+
+	// Time columns hold times, with values
+	// in seconds since the Unix epoch.
+	Time
+
+	// String columns hold text, and their
+	// values are all NaN.
+	String
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case Float:
+		return "float"
+	case Time:
+		return "time"
+	case String:
+		return "string"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Column is a column of a table.  It implements
+// the plotter.Valuer and plotter.Labeller interfaces.
+type Column struct {
+	// Name is the name of the column from the
+	// header of the table, or its index if the
+	// table has no header.
+	Name string
+
+	// Kind is the kind of the values of the column.
+	Kind Kind
+
+	// Values are the values of the column.
+	// Missing values are NaN.
+	Values []float64
+
+	// Strings are the fields of the column as
+	// they were read.
+	Strings []string
+}
+
+// Len returns the number of rows of the column.
+func (c *Column) Len() int {
+	return len(c.Values)
+}
+
+// Value returns the value of row i.
+func (c *Column) Value(i int) float64 {
+	return c.Values[i]
+}
+
+// Label returns the field of row i.
+func (c *Column) Label(i int) string {
+	return c.Strings[i]
+}
+
+// Table is a table of columns of equal length.
+type Table struct {
+	Columns []*Column
+}
+
+// Len returns the number of rows of the table.
+func (t *Table) Len() int {
+	if len(t.Columns) == 0 {
+		return 0
+	}
+	return t.Columns[0].Len()
+}
+
+// Column returns the column selected by sel, which
+// is either the name of the column or, if no column
+// has that name, the index of the column.
+func (t *Table) Column(sel string) (*Column, error) {
+	for _, c := range t.Columns {
+		if c.Name == sel {
+			return c, nil
+		}
+	}
+	i, err := strconv.Atoi(sel)
+	if err != nil || i < 0 || i >= len(t.Columns) {
+		return nil, fmt.Errorf("table: no column %q", sel)
+	}
+	return t.Columns[i], nil
+}
+
+// columns returns the columns selected by sels.
+func (t *Table) columns(sels ...string) ([]*Column, error) {
+	cs := make([]*Column, len(sels))
+	for i, sel := range sels {
+		c, err := t.Column(sel)
+		if err != nil {
+			return nil, err
+		}
+		cs[i] = c
+	}
+	return cs, nil
+}
+
+// XYs returns a view of the columns selected by x and y.
+func (t *Table) XYs(x, y string) (XYs, error) {
+	cs, err := t.columns(x, y)
+	if err != nil {
+		return XYs{}, err
+	}
+	return XYs{X: cs[0], Y: cs[1]}, nil
+}
+
+// XYZs returns a view of the columns selected by x, y and z.
+func (t *Table) XYZs(x, y, z string) (XYZs, error) {
+	cs, err := t.columns(x, y, z)
+	if err != nil {
+		return XYZs{}, err
+	}
+	return XYZs{X: cs[0], Y: cs[1], Z: cs[2]}, nil
+}
+
+// Labels returns a view of the fields of the column
+// selected by sel as labels.
+func (t *Table) Labels(sel string) (Labels, error) {
+	c, err := t.Column(sel)
+	if err != nil {
+		return Labels{}, err
+	}
+	return Labels{Column: c}, nil
+}
+
+// YErrors returns a view of the columns selected by low
+// and high as the low and high errors of y values.  The
+// same column may be selected for symmetric errors.
+func (t *Table) YErrors(low, high string) (YErrors, error) {
+	cs, err := t.columns(low, high)
+	if err != nil {
+		return YErrors{}, err
+	}
+	return YErrors{Low: cs[0], High: cs[1]}, nil
+}
+
+// Grid returns a grid of the values of the column selected
+// by z at the distinct values of the columns selected by x
+// and y, such as a table in long format with a row for each
+// cell of a heat map.  Cells of the grid without a row of
+// the table are NaN.  An error is returned if two rows are
+// at the same cell.
+func (t *Table) Grid(x, y, z string) (*Grid, error) {
+	cs, err := t.columns(x, y, z)
+	if err != nil {
+		return nil, err
+	}
+	g := &Grid{
+		Xs: distinct(cs[0].Values),
+		Ys: distinct(cs[1].Values),
+	}
+	g.Zs = make([]float64, len(g.Xs)*len(g.Ys))
+	for i := range g.Zs {
+		g.Zs[i] = math.NaN()
+	}
+	set := make([]bool, len(g.Zs))
+	for i := 0; i < t.Len(); i++ {
+		xv, yv := cs[0].Values[i], cs[1].Values[i]
+		if math.IsNaN(xv) || math.IsNaN(yv) {
+			continue
+		}
+		c := sort.SearchFloat64s(g.Xs, xv)
+		r := sort.SearchFloat64s(g.Ys, yv)
+		k := r*len(g.Xs) + c
+		if set[k] {
+			return nil, fmt.Errorf("table: duplicate grid cell at %v, %v", xv, yv)
+		}
+		set[k] = true
+		g.Zs[k] = cs[2].Values[i]
+	}
+	return g, nil
+}
+
+// distinct returns the sorted distinct
+// values of vs that are not NaN.
+func distinct(vs []float64) []float64 {
+	var d []float64
+	for _, v := range vs {
+		if !math.IsNaN(v) {
+			d = append(d, v)
+		}
+	}
+	sort.Float64s(d)
+	n := 0
+	for i, v := range d {
+		if i > 0 && v == d[n-1] {
+			continue
+		}
+		d[n] = v
+		n++
+	}
+	return d[:n]
+}
+
+// XYs is a view of two columns that implements
+// the plotter.XYer interface.
+type XYs struct {
+	X, Y *Column
+}
+
+// Len returns the number of rows.
+func (xys XYs) Len() int {
+	return xys.X.Len()
+}
+
+// XY returns the x and y values of row i.
+func (xys XYs) XY(i int) (x, y float64) {
+	return xys.X.Values[i], xys.Y.Values[i]
+}
+
+// XYZs is a view of three columns that implements
+// the plotter.XYZer interface.
+type XYZs struct {
+	X, Y, Z *Column
+}
+
+// Len returns the number of rows.
+func (xyzs XYZs) Len() int {
+	return xyzs.X.Len()
+}
+
+// XYZ returns the x, y and z values of row i.
+func (xyzs XYZs) XYZ(i int) (x, y, z float64) {
+	return xyzs.X.Values[i], xyzs.Y.Values[i], xyzs.Z.Values[i]
+}
+
+// Labels is a view of a column that implements
+// the plotter.Labeller interface.
+type Labels struct {
+	Column *Column
+}
+
+// Label returns the field of row i.
+func (l Labels) Label(i int) string {
+	return l.Column.Strings[i]
+}
+
+// YErrors is a view of two columns that implements
+// the plotter.YErrorer interface.
+type YErrors struct {
+	Low, High *Column
+}
+
+// YError returns the low and high errors of row i.
+func (e YErrors) YError(i int) (low, high float64) {
+	return e.Low.Values[i], e.High.Values[i]
+}
+
+// Grid implements the plotter.GridXYZ interface
+// with values at the columns Xs and rows Ys.
+type Grid struct {
+	// Xs and Ys are the x and y values of the
+	// columns and rows of the grid, in order.
+	Xs, Ys []float64
+
+	// Zs are the values of the cells of the
+	// grid, stored in row order.
+	Zs []float64
+}
+
+// Dims returns the number of columns and rows of the grid.
+func (g *Grid) Dims() (c, r int) {
+	return len(g.Xs), len(g.Ys)
+}
+
+// Z returns the value of the cell at column c and row r.
+func (g *Grid) Z(c, r int) float64 {
+	return g.Zs[r*len(g.Xs)+c]
+}
+
+// X returns the x value of column c.
+func (g *Grid) X(c int) float64 {
+	return g.Xs[c]
+}
+
+// Y returns the y value of row r.
+func (g *Grid) Y(r int) float64 {
+	return g.Ys[r]
+}
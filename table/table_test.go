@@ -0,0 +1,241 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package table
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gonum/plot/palette"
+	"github.com/gonum/plot/plotter"
+)
+
+const csvData = `city,date,temp,low,high
+Oslo,2015-01-01,-3.5,1,2
+Rome,2015-01-02,12,0.5,0.5
+Lima,2015-01-03,NA,1,1
+Kyiv,,-7,2,1
+`
+
+// sameValues returns whether a and b are equal,
+// treating NaNs as equal.
+func sameValues(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] && !(math.IsNaN(a[i]) && math.IsNaN(b[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRead(t *testing.T) {
+	tbl, err := NewReader().Read(strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tbl.Len() != 4 || len(tbl.Columns) != 5 {
+		t.Fatalf("unexpected table size: got:%dx%d want:4x5", tbl.Len(), len(tbl.Columns))
+	}
+	day := float64(24 * 60 * 60)
+	jan1 := float64(time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC).Unix())
+	nan := math.NaN()
+	for _, test := range []struct {
+		sel    string
+		name   string
+		kind   Kind
+		values []float64
+	}{
+		{sel: "city", name: "city", kind: String, values: []float64{nan, nan, nan, nan}},
+		{sel: "date", name: "date", kind: Time, values: []float64{jan1, jan1 + day, jan1 + 2*day, nan}},
+		{sel: "temp", name: "temp", kind: Float, values: []float64{-3.5, 12, nan, -7}},
+		{sel: "3", name: "low", kind: Float, values: []float64{1, 0.5, 1, 2}},
+	} {
+		c, err := tbl.Column(test.sel)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", test.sel, err)
+			continue
+		}
+		if c.Name != test.name || c.Kind != test.kind {
+			t.Errorf("unexpected column for %q: got:%s,%v want:%s,%v", test.sel, c.Name, c.Kind, test.name, test.kind)
+		}
+		if !sameValues(c.Values, test.values) {
+			t.Errorf("unexpected values for %q: got:%v want:%v", test.sel, c.Values, test.values)
+		}
+	}
+	if _, err := tbl.Column("pressure"); err == nil {
+		t.Error("expected error for missing column")
+	}
+	if _, err := tbl.Column("5"); err == nil {
+		t.Error("expected error for column index out of range")
+	}
+}
+
+func TestReaderOptions(t *testing.T) {
+	r := NewReader()
+	r.Columns = []string{"temp", "0"}
+	r.Missing = []string{"-7"}
+	r.Kinds = map[string]Kind{"city": String}
+	tbl, err := r.Read(strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tbl.Columns) != 2 || tbl.Columns[0].Name != "temp" || tbl.Columns[1].Name != "city" {
+		t.Fatalf("unexpected columns: got:%v", tbl.Columns)
+	}
+	if tbl.Columns[0].Kind != String {
+		t.Errorf("unexpected kind of temp with NA not missing: got:%v want:string", tbl.Columns[0].Kind)
+	}
+
+	r = &Reader{Comma: ';'}
+	tbl, err = r.Read(strings.NewReader("1;2\n3;4\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tbl.Columns[1].Name != "1" || !sameValues(tbl.Columns[1].Values, []float64{2, 4}) {
+		t.Errorf("unexpected column without header: got:%+v", tbl.Columns[1])
+	}
+	tbl, err = (&Reader{}).Read(strings.NewReader("1,2\n"))
+	if err != nil {
+		t.Fatalf("unexpected error for zero Reader: %v", err)
+	}
+	if len(tbl.Columns) != 2 || tbl.Len() != 1 {
+		t.Errorf("unexpected table from zero Reader: got:%dx%d want:1x2", tbl.Len(), len(tbl.Columns))
+	}
+
+	r = NewReader()
+	r.TimeLayouts = []string{"02/01/2006 15:04"}
+	r.Location = time.FixedZone("", 3600)
+	tbl, err = r.Read(strings.NewReader("when\n01/02/2015 10:00\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := float64(time.Date(2015, 2, 1, 9, 0, 0, 0, time.UTC).Unix())
+	if c := tbl.Columns[0]; c.Kind != Time || c.Values[0] != want {
+		t.Errorf("unexpected time column: got:%v,%v want:time,%v", c.Kind, c.Values, want)
+	}
+
+	if _, err := NewReader().Read(strings.NewReader("a,b\n1\n")); err == nil {
+		t.Error("expected error for short record")
+	}
+	if _, err := NewReader().Read(strings.NewReader("")); err == nil {
+		t.Error("expected error for empty input")
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "table")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "data.tsv")
+	err = ioutil.WriteFile(name, []byte("x\ty\tname\n1\t2\ta \"b\"\n3\t4\tc\n"), 0644)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tbl, err := ReadFile(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	xys, err := tbl.XYs("x", "y")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := plotter.CopyXYs(xys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (plotter.XYs{{1, 2}, {3, 4}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected points: got:%v want:%v", got, want)
+	}
+	labels, err := tbl.Labels("name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	l, err := plotter.NewLabels(struct {
+		XYs
+		Labels
+	}{xys, labels})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{`a "b"`, "c"}; !reflect.DeepEqual(l.Labels, want) {
+		t.Errorf("unexpected labels: got:%q want:%q", l.Labels, want)
+	}
+}
+
+func TestViews(t *testing.T) {
+	tbl, err := NewReader().Read(strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	xys, err := tbl.XYs("low", "high")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	errs, err := tbl.YErrors("low", "high")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bars, err := plotter.NewYErrorBars(struct {
+		XYs
+		YErrors
+	}{xys, errs})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bars.YErrors) != 4 || bars.YErrors[1].Low != 0.5 || bars.YErrors[3].High != 1 {
+		t.Errorf("unexpected errors: got:%v", bars.YErrors)
+	}
+	xyzs, err := tbl.XYZs("low", "high", "temp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if x, y, z := xyzs.XYZ(3); x != 2 || y != 1 || z != -7 {
+		t.Errorf("unexpected XYZ: got:%v,%v,%v want:2,1,-7", x, y, z)
+	}
+	if _, err := tbl.XYZs("low", "high", "depth"); err == nil {
+		t.Error("expected error for missing column")
+	}
+	if _, err := tbl.Grid("low", "high", "temp"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := tbl.Grid("high", "high", "temp"); err == nil {
+		t.Error("expected error for duplicate grid cells")
+	}
+}
+
+func TestGrid(t *testing.T) {
+	tbl, err := NewReader().Read(strings.NewReader("x,y,z\n2,10,1\n1,10,2\n2,20,3\n1,20,4\n3,10,5\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	g, err := tbl.Grid("x", "y", "z")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c, r := g.Dims(); c != 3 || r != 2 {
+		t.Fatalf("unexpected dims: got:%d,%d want:3,2", c, r)
+	}
+	if !reflect.DeepEqual(g.Xs, []float64{1, 2, 3}) || !reflect.DeepEqual(g.Ys, []float64{10, 20}) {
+		t.Errorf("unexpected grid axes: got:%v,%v", g.Xs, g.Ys)
+	}
+	if want := []float64{2, 1, 5, 4, 3, math.NaN()}; !sameValues(g.Zs, want) {
+		t.Errorf("unexpected grid values: got:%v want:%v", g.Zs, want)
+	}
+	if g.Z(2, 0) != 5 || g.X(2) != 3 || g.Y(1) != 20 {
+		t.Errorf("unexpected grid cell: got:%v at %v,%v", g.Z(2, 0), g.X(2), g.Y(1))
+	}
+	plotter.NewHeatMap(g, palette.Heat(4, 1))
+}
@@ -21,12 +21,45 @@ type Canvas struct {
 }
 
 // TextStyle describes what text will look like.
+//
+// If Markup is true, text drawn and measured using a
+// TextStyle may contain markup in a small subset of
+// LaTeX notation:
+//
+//	^{text}           superscript
+//	_{text}           subscript
+//	\textbf{text}     bold text, if the font is in a family with a bold style
+//	\textit{text}     italic text, if the font is in a family with an italic style
+//	\color{c}{text}   colored text, where c is a color name or #rrggbb
+//	\alpha, \pm, ...  Greek letters and mathematical symbols
+//	\\ \{ \} \^ \_    literal characters
+//
+// Markup may be nested.  Anything that is not markup, such as
+// an underscore that is not followed by a brace or an unknown
+// command, is drawn as it is written.
 type TextStyle struct {
 	// Color is the text color.
 	Color color.Color
 
 	// Font is the font description.
 	Font vg.Font
+
+	// Style is the style, such as bold or italic,
+	// that is added to the style of Font when the
+	// text is drawn and measured.  It is ignored if
+	// Font is not in a family of fonts with a font
+	// of the combined style.
+	Style vg.FontStyle
+
+	// Rotation is the angle, in radians, by which the
+	// text is rotated counter-clockwise about the point
+	// at which it is drawn.
+	Rotation float64
+
+	// Markup specifies whether the text is parsed
+	// for markup.  If Markup is false the text is
+	// drawn exactly as it is written.
+	Markup bool
 }
 
 // LineStyle describes what a line will look like.
@@ -39,6 +72,18 @@ type LineStyle struct {
 
 	Dashes   []vg.Length
 	DashOffs vg.Length
+
+	// Cap and Join are the shapes drawn at the
+	// ends and at the corners of the line.
+	Cap  vg.LineCap
+	Join vg.LineJoin
+
+	// MiterLimit is the limit on the ratio of the
+	// length of a mitered corner to the line width,
+	// above which the corner is beveled instead.
+	// If MiterLimit is zero, vg.DefaultMiterLimit
+	// is used.
+	MiterLimit float64
 }
 
 // A GlyphStyle specifies the look of a glyph used to draw
@@ -61,6 +106,18 @@ type GlyphDrawer interface {
 	DrawGlyph(*Canvas, GlyphStyle, Point)
 }
 
+// A RawTexter is a vg.Canvas that interprets the text
+// given to FillString itself, such as a canvas that
+// writes LaTeX.
+type RawTexter interface {
+	vg.Canvas
+
+	// RawText returns whether text should be passed
+	// to FillString verbatim, one line at a time,
+	// rather than being parsed for markup.
+	RawText() bool
+}
+
 // DrawGlyph draws the given glyph to the draw
 // area.  If the point is not within the Canvas
 // or the sty.Shape is nil then nothing is drawn.
@@ -182,6 +239,21 @@ func (PyramidGlyph) DrawGlyph(c *Canvas, sty GlyphStyle, pt Point) {
 	c.Fill(p)
 }
 
+// DiamondGlyph is a glyph that draws a filled diamond.
+type DiamondGlyph struct{}
+
+// DrawGlyph implements the Glyph interface.
+func (DiamondGlyph) DrawGlyph(c *Canvas, sty GlyphStyle, pt Point) {
+	r := sty.Radius
+	var p vg.Path
+	p.Move(pt.X, pt.Y+r)
+	p.Line(pt.X-r, pt.Y)
+	p.Line(pt.X, pt.Y-r)
+	p.Line(pt.X+r, pt.Y)
+	p.Close()
+	c.Fill(p)
+}
+
 // PlusGlyph is a glyph that draws a plus sign
 type PlusGlyph struct{}
 
@@ -303,6 +375,13 @@ func (c *Canvas) SetLineStyle(sty LineStyle) {
 		dashDots = append(dashDots, dash)
 	}
 	c.SetLineDash(dashDots, sty.DashOffs)
+	c.SetLineCap(sty.Cap)
+	c.SetLineJoin(sty.Join)
+	miter := sty.MiterLimit
+	if miter == 0 {
+		miter = vg.DefaultMiterLimit
+	}
+	c.SetMiterLimit(miter)
 }
 
 // StrokeLines draws a line connecting a set of points
@@ -505,51 +584,126 @@ func isect(p0, p1, clip, norm Point) Point {
 // The text is offset by its width times xalign and
 // its height times yalign.  x and y give the bottom
 // left corner of the text befor e it is offset.
+// If the style has a rotation then the text is offset
+// along its rotated axes and rotated about x, y.
+// If the style has Markup set, the text may contain
+// markup, as described for TextStyle, unless the
+// underlying vg.Canvas is a RawTexter that asks for
+// the raw text.
 func (c *Canvas) FillText(sty TextStyle, x, y vg.Length, xalign, yalign float64, txt string) {
 	txt = strings.TrimRight(txt, "\n")
 	if len(txt) == 0 {
 		return
 	}
 
+	if sty.Rotation != 0 {
+		c.Push()
+		defer c.Pop()
+		c.Translate(x, y)
+		c.Rotate(sty.Rotation)
+		x, y = 0, 0
+	}
+
 	c.SetColor(sty.Color)
 
+	if r, ok := c.Canvas.(RawTexter); ok && r.RawText() {
+		sty.Markup = false
+	}
+	fnt := sty.font()
 	ht := sty.Height(txt)
-	y += ht*vg.Length(yalign) - sty.Font.Extents().Ascent
+	y += ht*vg.Length(yalign) - fnt.Extents().Ascent
 	nl := textNLines(txt)
 	for i, line := range strings.Split(txt, "\n") {
-		xoffs := vg.Length(xalign) * sty.Font.Width(line)
+		spans := sty.spans(line)
+		xoffs := vg.Length(xalign) * spansWidth(spans)
 		n := vg.Length(nl - i)
-		c.FillString(sty.Font, x+xoffs, y+n*sty.Font.Size, line)
+		clr := sty.Color
+		for _, s := range spans {
+			if !sameColor(s.color, clr) {
+				c.SetColor(s.color)
+				clr = s.color
+			}
+			c.FillString(s.font, x+xoffs, y+n*fnt.Size+s.rise, s.text)
+			xoffs += s.font.Width(s.text)
+		}
+		if !sameColor(clr, sty.Color) {
+			c.SetColor(sty.Color)
+		}
 	}
 }
 
+// sameColor returns whether a and b are the same color.
+// The colors are compared by their RGBA values, since
+// a color of a type that is not comparable would make
+// comparing a and b with == panic.
+func sameColor(a, b color.Color) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	return ar == br && ag == bg && ab == bb && aa == ba
+}
+
 // Width returns the width of lines of text
 // when using the given font.
 func (sty TextStyle) Width(txt string) (max vg.Length) {
 	txt = strings.TrimRight(txt, "\n")
 	for _, line := range strings.Split(txt, "\n") {
-		if w := sty.Font.Width(line); w > max {
+		if w := spansWidth(sty.spans(line)); w > max {
 			max = w
 		}
 	}
 	return
 }
 
+// spansWidth returns the total width of the spans.
+func spansWidth(spans []textSpan) vg.Length {
+	var w vg.Length
+	for _, s := range spans {
+		w += s.font.Width(s.text)
+	}
+	return w
+}
+
 // Height returns the height of the text when using
-// the given font.
+// the given font.  The height includes the space
+// needed for superscripts on the first line that
+// extend above the ascent of the font.
 func (sty TextStyle) Height(txt string) vg.Length {
 	nl := textNLines(txt)
 	if nl == 0 {
 		return vg.Length(0)
 	}
-	e := sty.Font.Extents()
-	return e.Height*vg.Length(nl-1) + e.Ascent
+	fnt := sty.font()
+	e := fnt.Extents()
+	top := e.Ascent
+	first := strings.SplitN(strings.TrimRight(txt, "\n"), "\n", 2)[0]
+	for _, s := range sty.spans(first) {
+		if t := s.rise + s.font.Extents().Ascent; t > top {
+			top = t
+		}
+	}
+	return e.Height*vg.Length(nl-1) + top
 }
 
 // Rectangle returns a rectangle giving the bounds of
-// this text assuming that it is drawn at 0, 0
+// this text assuming that it is drawn at 0, 0.  The
+// rectangle of rotated text bounds the rotated text.
 func (sty TextStyle) Rectangle(txt string) Rectangle {
-	return Rectangle{Max: Point{sty.Width(txt), sty.Height(txt)}}
+	w, h := sty.Width(txt), sty.Height(txt)
+	if sty.Rotation == 0 {
+		return Rectangle{Max: Point{w, h}}
+	}
+	var r Rectangle
+	for _, p := range [...]Point{{X: w}, {Y: h}, {X: w, Y: h}} {
+		p = p.rotate(sty.Rotation)
+		r.Min.X = vg.Length(math.Min(float64(r.Min.X), float64(p.X)))
+		r.Min.Y = vg.Length(math.Min(float64(r.Min.Y), float64(p.Y)))
+		r.Max.X = vg.Length(math.Max(float64(r.Max.X), float64(p.X)))
+		r.Max.Y = vg.Length(math.Max(float64(r.Max.Y), float64(p.Y)))
+	}
+	return r
 }
 
 // textNLines returns the number of lines in the text.
@@ -619,3 +773,13 @@ func (p Point) minus(q Point) Point {
 func (p Point) scale(s vg.Length) Point {
 	return Point{p.X * s, p.Y * s}
 }
+
+// rotate returns the point rotated counter-clockwise
+// about the origin by the given angle in radians.
+func (p Point) rotate(theta float64) Point {
+	sin, cos := math.Sincos(theta)
+	return Point{
+		X: p.X*vg.Length(cos) - p.Y*vg.Length(sin),
+		Y: p.X*vg.Length(sin) + p.Y*vg.Length(cos),
+	}
+}
@@ -1,7 +1,9 @@
 package draw
 
 import (
+	"fmt"
 	"image/color"
+	"math"
 	"reflect"
 	"testing"
 
@@ -56,3 +58,67 @@ func TestCrop(t *testing.T) {
 		t.Errorf(str, r1.Actions, r2.Actions)
 	}
 }
+
+func TestRotatedText(t *testing.T) {
+	fnt, err := vg.MakeFont("Helvetica", 10)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	sty := TextStyle{Color: color.Black, Font: fnt, Rotation: math.Pi / 2}
+	const txt = "rotated"
+	w, h := sty.Width(txt), sty.Height(txt)
+
+	r := sty.Rectangle(txt)
+	want := Rectangle{Min: Point{X: -h}, Max: Point{Y: w}}
+	if !near(r.Min.X, want.Min.X) || !near(r.Min.Y, want.Min.Y) ||
+		!near(r.Max.X, want.Max.X) || !near(r.Max.Y, want.Max.Y) {
+		t.Errorf("unexpected rectangle: got:%+v want:%+v", r, want)
+	}
+
+	rec := recorder.New(72)
+	c := NewCanvas(rec, 100, 100)
+	c.FillText(sty, 10, 20, -1, -0.5, txt)
+	var got []string
+	for _, a := range rec.Actions {
+		got = append(got, a.Call())
+	}
+	e := fnt.Extents()
+	wantCalls := []string{
+		"Push()",
+		"Translate(10, 20)",
+		"Rotate(1.5707963267948966)",
+		"SetColor(color.Gray16{Y:0x0})",
+		fmt.Sprintf("FillString(%q, %v, %v, %v, %q)", "Helvetica", 10, -w, -h/2-e.Ascent+10, txt),
+		"Pop()",
+	}
+	if !reflect.DeepEqual(got, wantCalls) {
+		t.Errorf("unexpected actions:\ngot:  %q\nwant: %q", got, wantCalls)
+	}
+}
+
+// sliceColor is a color of a type that is not comparable.
+type sliceColor []uint32
+
+func (c sliceColor) RGBA() (r, g, b, a uint32) {
+	return c[0], c[1], c[2], c[3]
+}
+
+func TestFillTextUncomparableColor(t *testing.T) {
+	fnt, err := vg.MakeFont("Helvetica", 10)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	rec := recorder.New(72)
+	c := NewCanvas(rec, 100, 100)
+	sty := TextStyle{Color: sliceColor{0, 0, 0xffff, 0xffff}, Font: fnt}
+	c.FillText(sty, 10, 20, 0, 0, "one\ntwo")
+	var colors int
+	for _, a := range rec.Actions {
+		if _, ok := a.(*recorder.SetColor); ok {
+			colors++
+		}
+	}
+	if colors != 1 {
+		t.Errorf("unexpected number of color changes: got:%d want:1", colors)
+	}
+}
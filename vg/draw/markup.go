@@ -0,0 +1,245 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gonum/plot/vg"
+)
+
+const (
+	// scriptScale is the size of superscripts and
+	// subscripts relative to the surrounding text.
+	scriptScale = 0.7
+
+	// superRise and subRise are the baseline shifts of
+	// superscripts and subscripts relative to the size
+	// of the surrounding text.
+	superRise = 0.4
+	subRise   = -0.2
+)
+
+// A textSpan is a section of a line of text
+// drawn with a single font and color.
+type textSpan struct {
+	text  string
+	font  vg.Font
+	color color.Color
+
+	// rise is the distance of the baseline
+	// of the span above the line's baseline.
+	rise vg.Length
+}
+
+// spans returns the spans of a line of text.
+func (sty TextStyle) spans(line string) []textSpan {
+	top := textSpan{font: sty.font(), color: sty.Color}
+	if !sty.Markup || !strings.ContainsAny(line, `\^_`) {
+		top.text = line
+		return []textSpan{top}
+	}
+	p := markupParser{s: line}
+	p.parse(top, false)
+	return p.spans
+}
+
+// markupParser parses a line of text with markup into spans.
+type markupParser struct {
+	s     string
+	pos   int
+	buf   []byte
+	spans []textSpan
+}
+
+// parse parses text drawn with the style of span until the end
+// of the line or, if group is true, the end of the group.
+func (p *markupParser) parse(span textSpan, group bool) {
+	flush := func() {
+		if len(p.buf) > 0 {
+			span.text = string(p.buf)
+			p.spans = append(p.spans, span)
+			p.buf = p.buf[:0]
+		}
+	}
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == '}' && group:
+			p.pos++
+			flush()
+			return
+
+		case (c == '^' || c == '_') && p.next(1) == '{':
+			flush()
+			p.pos += 2
+			p.parse(script(span, c == '^'), true)
+
+		case c == '\\':
+			if child, ok := p.command(span); ok {
+				flush()
+				p.parse(child, true)
+			}
+
+		default:
+			_, n := utf8.DecodeRuneInString(p.s[p.pos:])
+			p.buf = append(p.buf, p.s[p.pos:p.pos+n]...)
+			p.pos += n
+		}
+	}
+	flush()
+}
+
+// next returns the byte at offset i from the
+// current position, or zero past the end of the line.
+func (p *markupParser) next(i int) byte {
+	if p.pos+i >= len(p.s) {
+		return 0
+	}
+	return p.s[p.pos+i]
+}
+
+// command parses the command at the current position. Commands
+// that start a group return the style of the group and true.
+// Other commands are added to the text of the current span.
+func (p *markupParser) command(span textSpan) (textSpan, bool) {
+	start := p.pos
+	p.pos++
+	if c := p.next(0); strings.IndexByte(`\{}^_`, c) >= 0 {
+		p.buf = append(p.buf, c)
+		p.pos++
+		return span, false
+	}
+	end := p.pos
+	for end < len(p.s) && isLetter(p.s[end]) {
+		end++
+	}
+	name := p.s[p.pos:end]
+	p.pos = end
+
+	if r, ok := symbols[name]; ok {
+		p.buf = append(p.buf, string(r)...)
+		return span, false
+	}
+	switch {
+	case name == "textbf" && p.next(0) == '{':
+		p.pos++
+		return styled(span, vg.Bold), true
+	case name == "textit" && p.next(0) == '{':
+		p.pos++
+		return styled(span, vg.Italic), true
+	case name == "color" && p.next(0) == '{':
+		if i := strings.IndexByte(p.s[p.pos:], '}'); i > 0 {
+			clr, ok := parseColor(p.s[p.pos+1 : p.pos+i])
+			if ok && p.next(i+1) == '{' {
+				p.pos += i + 2
+				span.color = clr
+				return span, true
+			}
+		}
+	}
+
+	// Unknown commands are drawn as written.
+	p.buf = append(p.buf, p.s[start:p.pos]...)
+	return span, false
+}
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// script returns the style of a superscript or
+// a subscript of text drawn with the given style.
+func script(span textSpan, super bool) textSpan {
+	rise := subRise
+	if super {
+		rise = superRise
+	}
+	span.rise += span.font.Size * vg.Length(rise)
+	span.font.Size *= scriptScale
+	return span
+}
+
+// font returns the font of the style with
+// the style's Style added to it.
+func (sty TextStyle) font() vg.Font {
+	if sty.Style == vg.Regular {
+		return sty.Font
+	}
+	return styledFont(sty.Font, sty.Style)
+}
+
+// styled returns span with the given style added to the style
+// of its font, if the font's family has a font with that style.
+func styled(span textSpan, style vg.FontStyle) textSpan {
+	span.font = styledFont(span.font, style)
+	return span
+}
+
+// styledFont returns fnt with the given style added to its
+// style, or fnt unchanged if its family has no such font.
+func styledFont(fnt vg.Font, style vg.FontStyle) vg.Font {
+	styled := fnt
+	if styled.SetStyle(fnt.Style()|style) != nil {
+		return fnt
+	}
+	return styled
+}
+
+// parseColor returns the color with the given
+// name or hexadecimal #rrggbb specification.
+func parseColor(s string) (color.Color, bool) {
+	if c, ok := colorNames[s]; ok {
+		return c, true
+	}
+	if len(s) != 7 || s[0] != '#' {
+		return nil, false
+	}
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return nil, false
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, true
+}
+
+// colorNames maps the color names recognized
+// by the \color command to their colors.
+var colorNames = map[string]color.Color{
+	"black":   color.Black,
+	"white":   color.White,
+	"red":     color.RGBA{R: 0xff, A: 0xff},
+	"green":   color.RGBA{G: 0x80, A: 0xff},
+	"blue":    color.RGBA{B: 0xff, A: 0xff},
+	"cyan":    color.RGBA{G: 0xff, B: 0xff, A: 0xff},
+	"magenta": color.RGBA{R: 0xff, B: 0xff, A: 0xff},
+	"yellow":  color.RGBA{R: 0xff, G: 0xff, A: 0xff},
+	"gray":    color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	"orange":  color.RGBA{R: 0xff, G: 0xa5, A: 0xff},
+}
+
+// symbols maps the names of symbol commands to their characters.
+var symbols = map[string]rune{
+	"alpha": 'α', "beta": 'β', "gamma": 'γ', "delta": 'δ', "epsilon": 'ε',
+	"zeta": 'ζ', "eta": 'η', "theta": 'θ', "iota": 'ι', "kappa": 'κ',
+	"lambda": 'λ', "mu": 'μ', "nu": 'ν', "xi": 'ξ', "omicron": 'ο',
+	"pi": 'π', "rho": 'ρ', "sigma": 'σ', "tau": 'τ', "upsilon": 'υ',
+	"phi": 'φ', "chi": 'χ', "psi": 'ψ', "omega": 'ω',
+
+	"Gamma": 'Γ', "Delta": 'Δ', "Theta": 'Θ', "Lambda": 'Λ', "Xi": 'Ξ',
+	"Pi": 'Π', "Sigma": 'Σ', "Upsilon": 'Υ', "Phi": 'Φ', "Psi": 'Ψ',
+	"Omega": 'Ω',
+
+	"pm": '±', "mp": '∓', "times": '×', "div": '÷', "cdot": '·',
+	"deg": '°', "circ": '∘', "infty": '∞', "partial": '∂', "nabla": '∇',
+	"sum": '∑', "prod": '∏', "int": '∫', "sqrt": '√', "propto": '∝',
+	"leq": '≤', "geq": '≥', "neq": '≠', "approx": '≈', "sim": '∼',
+	"equiv": '≡', "ll": '≪', "gg": '≫', "in": '∈', "cdots": '⋯',
+	"ldots": '…', "prime": '′', "hbar": 'ℏ', "angstrom": 'Å',
+	"leftarrow": '←', "rightarrow": '→', "leftrightarrow": '↔',
+	"uparrow": '↑', "downarrow": '↓',
+}
@@ -0,0 +1,269 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/recorder"
+)
+
+func TestSpans(t *testing.T) {
+	fnt, err := vg.MakeFont("Helvetica", 10)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	sty := TextStyle{Color: color.Black, Font: fnt, Markup: true}
+	red := color.RGBA{R: 0xff, A: 0xff}
+	teal := color.RGBA{G: 0x80, B: 0x80, A: 0xff}
+
+	type span struct {
+		text  string
+		font  string
+		size  vg.Length
+		rise  vg.Length
+		color color.Color
+	}
+	for _, test := range []struct {
+		in   string
+		want []span
+	}{
+		{
+			in:   "plain text",
+			want: []span{{"plain text", "Helvetica", 10, 0, color.Black}},
+		},
+		{
+			in:   `x_1 a^b c\d {e} f}`,
+			want: []span{{`x_1 a^b c\d {e} f}`, "Helvetica", 10, 0, color.Black}},
+		},
+		{
+			in: "m/s^{2}",
+			want: []span{
+				{"m/s", "Helvetica", 10, 0, color.Black},
+				{"2", "Helvetica", 7, 4, color.Black},
+			},
+		},
+		{
+			in: `\sigma_{x} (10^{-3})`,
+			want: []span{
+				{"σ", "Helvetica", 10, 0, color.Black},
+				{"x", "Helvetica", 7, -2, color.Black},
+				{" (10", "Helvetica", 10, 0, color.Black},
+				{"-3", "Helvetica", 7, 4, color.Black},
+				{")", "Helvetica", 10, 0, color.Black},
+			},
+		},
+		{
+			in: "e^{x_{i}}",
+			want: []span{
+				{"e", "Helvetica", 10, 0, color.Black},
+				{"x", "Helvetica", 7, 4, color.Black},
+				{"i", "Helvetica", 4.9, 2.6, color.Black},
+			},
+		},
+		{
+			in: `a \textbf{b \textit{c}} \color{red}{d} \color{#008080}{e}`,
+			want: []span{
+				{"a ", "Helvetica", 10, 0, color.Black},
+				{"b ", "Helvetica-Bold", 10, 0, color.Black},
+				{"c", "Helvetica-BoldOblique", 10, 0, color.Black},
+				{" ", "Helvetica", 10, 0, color.Black},
+				{"d", "Helvetica", 10, 0, red},
+				{" ", "Helvetica", 10, 0, color.Black},
+				{"e", "Helvetica", 10, 0, teal},
+			},
+		},
+		{
+			in:   `\{\}\^\_\\ \unknown \color{nocolor}{x} \pm`,
+			want: []span{{`{}^_\ \unknown \color{nocolor}{x} ±`, "Helvetica", 10, 0, color.Black}},
+		},
+		{
+			in: "unterminated^{group",
+			want: []span{
+				{"unterminated", "Helvetica", 10, 0, color.Black},
+				{"group", "Helvetica", 7, 4, color.Black},
+			},
+		},
+	} {
+		got := sty.spans(test.in)
+		if len(got) != len(test.want) {
+			t.Errorf("unexpected number of spans for %q: got:%d want:%d", test.in, len(got), len(test.want))
+			continue
+		}
+		for i, s := range got {
+			w := test.want[i]
+			if s.text != w.text || s.font.Name() != w.font || !near(s.font.Size, w.size) ||
+				!near(s.rise, w.rise) || s.color != w.color {
+				t.Errorf("unexpected span %d for %q:\ngot:  %q %s %v %v %v\nwant: %q %s %v %v %v",
+					i, test.in, s.text, s.font.Name(), s.font.Size, s.rise, s.color,
+					w.text, w.font, w.size, w.rise, w.color)
+			}
+		}
+	}
+}
+
+func near(a, b vg.Length) bool {
+	const tol = 1e-9
+	return a-b < tol && b-a < tol
+}
+
+func TestFillTextMarkup(t *testing.T) {
+	fnt, err := vg.MakeFont("Times-Roman", 10)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	sty := TextStyle{Color: color.Black, Font: fnt, Markup: true}
+	small := fnt
+	small.Size = 7
+
+	const txt = `a^{2} \color{red}{b}`
+	want := fnt.Width("a") + small.Width("2") + fnt.Width(" ") + fnt.Width("b")
+	if got := sty.Width(txt); !near(got, want) {
+		t.Errorf("unexpected width: got:%v want:%v", got, want)
+	}
+	e, se := fnt.Extents(), small.Extents()
+	if got, want := sty.Height(txt), 4+se.Ascent; !near(got, want) {
+		t.Errorf("unexpected height: got:%v want:%v", got, want)
+	}
+	if got, want := sty.Height("a\nb^{2}"), e.Height+e.Ascent; !near(got, want) {
+		t.Errorf("unexpected height for superscript on second line: got:%v want:%v", got, want)
+	}
+
+	rec := recorder.New(72)
+	c := NewCanvas(rec, 100, 100)
+	c.FillText(sty, 10, 20, 0, 0, txt)
+
+	var strs []*recorder.FillString
+	var colors []color.Color
+	for _, a := range rec.Actions {
+		switch a := a.(type) {
+		case *recorder.FillString:
+			strs = append(strs, a)
+		case *recorder.SetColor:
+			colors = append(colors, a.Color)
+		}
+	}
+	base := 20 - e.Ascent + 10
+	wantStrs := []recorder.FillString{
+		{Font: "Times-Roman", Size: 10, X: 10, Y: base, String: "a"},
+		{Font: "Times-Roman", Size: 7, X: 10 + fnt.Width("a"), Y: base + 4, String: "2"},
+		{Font: "Times-Roman", Size: 10, X: 10 + fnt.Width("a") + small.Width("2"), Y: base, String: " "},
+		{Font: "Times-Roman", Size: 10, X: 10 + fnt.Width("a ") + small.Width("2"), Y: base, String: "b"},
+	}
+	if len(strs) != len(wantStrs) {
+		t.Fatalf("unexpected number of strings: got:%d want:%d", len(strs), len(wantStrs))
+	}
+	for i, s := range strs {
+		w := wantStrs[i]
+		if s.Font != w.Font || s.Size != w.Size || !near(s.X, w.X) || !near(s.Y, w.Y) || s.String != w.String {
+			t.Errorf("unexpected string %d: got:%+v want:%+v", i, *s, w)
+		}
+	}
+	wantColors := []color.Color{color.Black, colorNames["red"], color.Black}
+	if len(colors) != len(wantColors) {
+		t.Fatalf("unexpected colors: got:%v want:%v", colors, wantColors)
+	}
+	for i, clr := range colors {
+		if clr != wantColors[i] {
+			t.Errorf("unexpected color %d: got:%v want:%v", i, clr, wantColors[i])
+		}
+	}
+}
+
+func TestFillTextNoMarkup(t *testing.T) {
+	fnt, err := vg.MakeFont("Times-Roman", 10)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	sty := TextStyle{Color: color.Black, Font: fnt}
+
+	const txt = `a^{2} \color{red}{b}`
+	if got, want := sty.Width(txt), fnt.Width(txt); !near(got, want) {
+		t.Errorf("unexpected width: got:%v want:%v", got, want)
+	}
+	if got, want := sty.Height(txt), fnt.Extents().Ascent; !near(got, want) {
+		t.Errorf("unexpected height: got:%v want:%v", got, want)
+	}
+
+	rec := recorder.New(72)
+	c := NewCanvas(rec, 100, 100)
+	c.FillText(sty, 10, 20, 0, 0, txt)
+
+	var strs []string
+	for _, a := range rec.Actions {
+		if a, ok := a.(*recorder.FillString); ok {
+			strs = append(strs, a.String)
+		}
+	}
+	if len(strs) != 1 || strs[0] != txt {
+		t.Errorf("unexpected strings: got:%q want:%q", strs, []string{txt})
+	}
+}
+
+func TestTextStyleStyle(t *testing.T) {
+	fnt, err := vg.MakeFont("Times-Roman", 10)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	bold, err := vg.MakeFont("Times-Bold", 10)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+
+	for _, test := range []struct {
+		font  vg.Font
+		style vg.FontStyle
+		txt   string
+		want  []string
+	}{
+		{font: fnt, style: vg.Regular, txt: "a", want: []string{"Times-Roman"}},
+		{font: fnt, style: vg.Bold, txt: "a", want: []string{"Times-Bold"}},
+		{font: fnt, style: vg.Italic, txt: "a", want: []string{"Times-Italic"}},
+		{font: fnt, style: vg.BoldItalic, txt: "a", want: []string{"Times-BoldItalic"}},
+		{font: bold, style: vg.Bold, txt: "a", want: []string{"Times-Bold"}},
+		{font: bold, style: vg.Italic, txt: "a", want: []string{"Times-BoldItalic"}},
+		{font: fnt, style: vg.Bold, txt: `a \textit{b}`, want: []string{"Times-Bold", "Times-BoldItalic"}},
+	} {
+		sty := TextStyle{Color: color.Black, Font: test.font, Style: test.style, Markup: true}
+		styled, err := vg.MakeFont(test.want[0], 10)
+		if err != nil {
+			t.Fatalf("failed to make font: %v", err)
+		}
+
+		if got, want := sty.Height("a"), styled.Extents().Ascent; !near(got, want) {
+			t.Errorf("unexpected height for %v %v: got:%v want:%v", test.font.Name(), test.style, got, want)
+		}
+		if test.txt == "a" {
+			if got, want := sty.Width(test.txt), styled.Width(test.txt); !near(got, want) {
+				t.Errorf("unexpected width for %v %v: got:%v want:%v", test.font.Name(), test.style, got, want)
+			}
+		}
+
+		rec := recorder.New(72)
+		c := NewCanvas(rec, 100, 100)
+		c.FillText(sty, 10, 20, 0, 0, test.txt)
+		var got []string
+		for _, a := range rec.Actions {
+			if s, ok := a.(*recorder.FillString); ok {
+				got = append(got, s.Font)
+				if want := 20 - styled.Extents().Ascent + 10; !near(s.Y, want) {
+					t.Errorf("unexpected y for %v %v: got:%v want:%v", test.font.Name(), test.style, s.Y, want)
+				}
+			}
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("unexpected fonts for %v %v %q: got:%v want:%v", test.font.Name(), test.style, test.txt, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("unexpected fonts for %v %v %q: got:%v want:%v", test.font.Name(), test.style, test.txt, got, test.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vg
+
+import (
+	"errors"
+	"sync"
+)
+
+// FontStyle is the style of a font within its family.
+type FontStyle int
+
+// The styles of the fonts in a family.
+const (
+	Regular    FontStyle = 0
+	Bold       FontStyle = 1
+	Italic     FontStyle = 2
+	BoldItalic FontStyle = Bold | Italic
+)
+
+// String returns the name of the style.
+func (s FontStyle) String() string {
+	switch s {
+	case Regular:
+		return "Regular"
+	case Bold:
+		return "Bold"
+	case Italic:
+		return "Italic"
+	case BoldItalic:
+		return "BoldItalic"
+	}
+	return "FontStyle(invalid)"
+}
+
+// familyStyle is the family and style of a font.
+type familyStyle struct {
+	family string
+	style  FontStyle
+}
+
+var (
+	// families is indexed by a family name and it
+	// holds the font names of the styles of the family.
+	families = map[string]*[4]string{
+		"Courier":   {"Courier", "Courier-Bold", "Courier-Oblique", "Courier-BoldOblique"},
+		"Helvetica": {"Helvetica", "Helvetica-Bold", "Helvetica-Oblique", "Helvetica-BoldOblique"},
+		"Times":     {"Times-Roman", "Times-Bold", "Times-Italic", "Times-BoldItalic"},
+	}
+
+	// fontStyles is indexed by a font name and it
+	// holds the family and style of the font.
+	fontStyles = initFontStyles()
+
+	// familyLock protects access to the families
+	// and fontStyles maps.
+	familyLock sync.RWMutex
+)
+
+func initFontStyles() map[string]familyStyle {
+	m := make(map[string]familyStyle)
+	for family, names := range families {
+		for style, name := range names {
+			m[name] = familyStyle{family: family, style: FontStyle(style)}
+		}
+	}
+	return m
+}
+
+// AddFontFamily adds the font with the given name to a family
+// of fonts as the font with the given style. The Courier,
+// Helvetica and Times families are available by default.
+func AddFontFamily(family string, style FontStyle, name string) error {
+	if style < Regular || style > BoldItalic {
+		return errors.New("Invalid font style: " + style.String())
+	}
+	familyLock.Lock()
+	defer familyLock.Unlock()
+	names, ok := families[family]
+	if !ok {
+		names = new([4]string)
+		families[family] = names
+	}
+	if old := names[style]; old != "" {
+		delete(fontStyles, old)
+	}
+	names[style] = name
+	fontStyles[name] = familyStyle{family: family, style: style}
+	return nil
+}
+
+// FamilyFont returns the name of the font with the given
+// style in a family of fonts.
+func FamilyFont(family string, style FontStyle) (string, error) {
+	familyLock.RLock()
+	defer familyLock.RUnlock()
+	names, ok := families[family]
+	if !ok {
+		return "", errors.New("Unknown font family " + family)
+	}
+	if style < Regular || style > BoldItalic || names[style] == "" {
+		return "", errors.New("No " + style.String() + " font in family " + family)
+	}
+	return names[style], nil
+}
+
+// MakeFamilyFont returns the font with the given style
+// in a family of fonts, with the given size.
+func MakeFamilyFont(family string, style FontStyle, size Length) (Font, error) {
+	name, err := FamilyFont(family, style)
+	if err != nil {
+		return Font{}, err
+	}
+	return MakeFont(name, size)
+}
+
+// Family returns the name of the family of the font,
+// or the empty string if the font is not in a family.
+func (f *Font) Family() string {
+	familyLock.RLock()
+	defer familyLock.RUnlock()
+	return fontStyles[f.name].family
+}
+
+// Style returns the style of the font within its family.
+func (f *Font) Style() FontStyle {
+	familyLock.RLock()
+	defer familyLock.RUnlock()
+	return fontStyles[f.name].style
+}
+
+// SetStyle changes the font to the font with the given
+// style in the same family, keeping its size. If an error
+// is returned then the font is left unchanged.
+func (f *Font) SetStyle(style FontStyle) error {
+	family := f.Family()
+	if family == "" {
+		return errors.New("Font " + f.name + " is not in a font family")
+	}
+	name, err := FamilyFont(family, style)
+	if err != nil {
+		return err
+	}
+	return f.SetName(name)
+}
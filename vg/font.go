@@ -13,10 +13,12 @@ package vg
 import (
 	"errors"
 	"go/build"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 
 	"code.google.com/p/freetype-go/freetype"
 	"code.google.com/p/freetype-go/freetype/truetype"
@@ -58,7 +60,23 @@ var (
 	// caches the associated *truetype.Font.
 	loadedFonts = make(map[string]*truetype.Font)
 
-	// FontLock protects access to the loadedFonts map.
+	// fontData is indexed by a font name and it caches
+	// the TrueType file data of fonts loaded from FontDirs
+	// or added with AddFontData.
+	fontData = make(map[string][]byte)
+
+	// fallbacks is indexed by a font name and it holds
+	// the names of the fonts used to draw characters
+	// that are missing from the font.
+	fallbacks = make(map[string][]string)
+
+	// nFallbacks is the number of fonts that have
+	// fallbacks, read atomically so that fonts can
+	// be measured without locking when none do.
+	nFallbacks int32
+
+	// FontLock protects access to the loadedFonts,
+	// fontData and fallbacks maps.
 	fontLock sync.RWMutex
 )
 
@@ -115,6 +133,17 @@ func (f *Font) Font() *truetype.Font {
 	return f.font
 }
 
+// Data returns the TrueType file data of the font, for
+// embedding in vector output formats.  Data returns nil
+// if the data is not available, as is the case for fonts
+// added with AddFont.  The returned slice must not be
+// modified.
+func (f *Font) Data() []byte {
+	fontLock.RLock()
+	defer fontLock.RUnlock()
+	return fontData[f.name]
+}
+
 // SetName sets the name of the font, effectively
 // changing the font.  If an error is returned then
 // the font is left unchanged.
@@ -157,7 +186,22 @@ func (f *Font) Extents() FontExtents {
 }
 
 // Width returns width of a string when drawn using the font.
+// Characters missing from the font are measured using the
+// font's fallbacks.
 func (f *Font) Width(s string) Length {
+	if !f.hasFallbacks() {
+		return f.width(s)
+	}
+	var width Length
+	for _, r := range f.Runs(s) {
+		width += r.Font.width(r.Text)
+	}
+	return width
+}
+
+// width returns the width of a string when drawn
+// using the font, ignoring fallbacks.
+func (f *Font) width(s string) Length {
 	// scale converts truetype.FUnit to float64
 	scale := f.Size / Points(float64(f.font.FUnitsPerEm()))
 
@@ -174,13 +218,160 @@ func (f *Font) Width(s string) Length {
 	return Points(float64(width)) * scale
 }
 
+// A FontRun is a section of text that
+// is drawn using a single font.
+type FontRun struct {
+	// Font is the font used to draw the text.
+	Font Font
+
+	// Text is the text of the run.
+	Text string
+
+	// Offset is the distance along the baseline
+	// from the start of the complete text to the
+	// start of the run.
+	Offset Length
+}
+
+// Runs splits s into runs of characters drawn using the same
+// font. Each character is drawn using the first font that has
+// a glyph for it, out of the font and its fallbacks. Characters
+// that are in none of the fonts are drawn using the font.
+func (f *Font) Runs(s string) []FontRun {
+	if !f.hasFallbacks() {
+		return []FontRun{{Font: *f, Text: s}}
+	}
+	fonts := f.chain()
+	if len(fonts) == 1 {
+		return []FontRun{{Font: *f, Text: s}}
+	}
+
+	var (
+		runs  []FontRun
+		start int
+		cur   = -1
+		offs  Length
+	)
+	add := func(end int) {
+		if end > start {
+			r := FontRun{Font: fonts[cur], Text: s[start:end], Offset: offs}
+			runs = append(runs, r)
+			offs += r.Font.width(r.Text)
+		}
+		start = end
+	}
+	for i, r := range s {
+		j := 0
+		for k, fnt := range fonts {
+			if fnt.font.Index(r) != 0 {
+				j = k
+				break
+			}
+		}
+		if j != cur {
+			if cur >= 0 {
+				add(i)
+			}
+			cur = j
+		}
+	}
+	if cur >= 0 {
+		add(len(s))
+	}
+	return runs
+}
+
+// hasFallbacks returns whether fallbacks
+// have been set for the font.
+func (f *Font) hasFallbacks() bool {
+	if atomic.LoadInt32(&nFallbacks) == 0 {
+		return false
+	}
+	fontLock.RLock()
+	n := len(fallbacks[f.name])
+	fontLock.RUnlock()
+	return n != 0
+}
+
+// chain returns the font followed by its fallbacks.
+func (f *Font) chain() []Font {
+	fontLock.RLock()
+	names := fallbacks[f.name]
+	fonts := make([]Font, 1, len(names)+1)
+	fonts[0] = *f
+	for _, name := range names {
+		if fnt, ok := loadedFonts[name]; ok {
+			fonts = append(fonts, Font{Size: f.Size, name: name, font: fnt})
+		}
+	}
+	fontLock.RUnlock()
+	return fonts
+}
+
+// SetFallbacks sets the fonts, in order of preference, that are
+// used to measure and draw characters missing from the font with
+// the given name. The fallbacks of the fallback fonts are not used.
+// An error is returned if a fallback font cannot be loaded, in
+// which case the fallbacks are left unchanged.
+func SetFallbacks(name string, fallback ...string) error {
+	for _, fb := range fallback {
+		if _, err := getFont(fb); err != nil {
+			return err
+		}
+	}
+	fontLock.Lock()
+	if len(fallback) == 0 {
+		delete(fallbacks, name)
+	} else {
+		fallbacks[name] = append([]string(nil), fallback...)
+	}
+	atomic.StoreInt32(&nFallbacks, int32(len(fallbacks)))
+	fontLock.Unlock()
+	return nil
+}
+
+// Fallbacks returns the names of the fallback
+// fonts of the font with the given name.
+func Fallbacks(name string) []string {
+	fontLock.RLock()
+	defer fontLock.RUnlock()
+	return append([]string(nil), fallbacks[name]...)
+}
+
 // AddFont associates a truetype.Font with the given name.
 func AddFont(name string, font *truetype.Font) {
 	fontLock.Lock()
 	loadedFonts[name] = font
+	delete(fontData, name)
 	fontLock.Unlock()
 }
 
+// AddFontData parses TrueType font data and associates the
+// font with the given name, making it available to MakeFont
+// without reading from FontDirs. The data is embedded in
+// vector output formats and must not be modified.
+func AddFontData(name string, data []byte) error {
+	font, err := freetype.ParseFont(data)
+	if err != nil {
+		return errors.New("Failed to parse font data: " + err.Error())
+	}
+	fontLock.Lock()
+	loadedFonts[name] = font
+	fontData[name] = data
+	fontLock.Unlock()
+	return nil
+}
+
+// ReadFont reads TrueType font data from r and associates the
+// font with the given name as for AddFontData.
+func ReadFont(name string, r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return errors.New("Failed to read font data: " + err.Error())
+	}
+	return AddFontData(name, data)
+}
+
 // getFont returns the truetype.Font for the given font name or an error.
 func getFont(name string) (*truetype.Font, error) {
 	fontLock.RLock()
@@ -210,6 +401,7 @@ func getFont(name string) (*truetype.Font, error) {
 	if err == nil {
 		fontLock.Lock()
 		loadedFonts[name] = font
+		fontData[name] = bytes
 		fontLock.Unlock()
 	} else {
 		err = errors.New("Failed to parse font file: " + err.Error())
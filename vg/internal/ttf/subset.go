@@ -0,0 +1,245 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ttf provides subsetting of TrueType font data
+// for the vector graphics backends that embed fonts.
+package ttf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"code.google.com/p/freetype-go/freetype/truetype"
+)
+
+// OutlineTables are the TrueType tables needed to render
+// glyphs by index. They include the cmap table needed to
+// parse the font as a TrueType font, but not the tables
+// used for layout and naming.
+var OutlineTables = map[string]bool{
+	"cmap": true, "cvt ": true, "fpgm": true, "glyf": true, "head": true,
+	"hhea": true, "hmtx": true, "loca": true, "maxp": true, "prep": true,
+}
+
+// Subset returns the TrueType font data with the outlines
+// of all glyphs except .notdef, the given glyphs and their
+// components removed. Glyph indices are unchanged, so that
+// the glyphs of the subset may be addressed as in the whole
+// font. Only the tables in the given set are kept, and the
+// glyph names are removed from the post table.
+func Subset(data []byte, glyphs []truetype.Index, tables map[string]bool) ([]byte, error) {
+	if len(data) < 12 || binary.BigEndian.Uint32(data) != 0x00010000 {
+		return nil, errors.New("ttf: invalid TrueType data")
+	}
+	n := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+16*n {
+		return nil, errors.New("ttf: invalid TrueType data")
+	}
+	kept := make(map[string][]byte)
+	for i := 0; i < n; i++ {
+		rec := data[12+16*i:]
+		tag := string(rec[:4])
+		off, length := binary.BigEndian.Uint32(rec[8:]), binary.BigEndian.Uint32(rec[12:])
+		if uint64(off)+uint64(length) > uint64(len(data)) {
+			return nil, fmt.Errorf("ttf: invalid TrueType table %q", tag)
+		}
+		if tables[tag] {
+			kept[tag] = data[off : off+length]
+		}
+	}
+	head, maxp, loca, glyf := kept["head"], kept["maxp"], kept["loca"], kept["glyf"]
+	if len(head) < 54 || len(maxp) < 6 || loca == nil || glyf == nil {
+		return nil, errors.New("ttf: missing TrueType outline tables")
+	}
+
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+	long := binary.BigEndian.Uint16(head[50:]) != 0
+	offsets := make([]uint32, numGlyphs+1)
+	for i := range offsets {
+		switch {
+		case long && len(loca) >= 4*(i+1):
+			offsets[i] = binary.BigEndian.Uint32(loca[4*i:])
+		case !long && len(loca) >= 2*(i+1):
+			offsets[i] = 2 * uint32(binary.BigEndian.Uint16(loca[2*i:]))
+		default:
+			return nil, errors.New("ttf: invalid TrueType loca table")
+		}
+	}
+	outline := func(i int) ([]byte, error) {
+		start, end := offsets[i], offsets[i+1]
+		if start > end || end > uint32(len(glyf)) {
+			return nil, errors.New("ttf: invalid TrueType loca table")
+		}
+		return glyf[start:end], nil
+	}
+
+	keep := make([]bool, numGlyphs)
+	stack := []int{0}
+	for _, g := range glyphs {
+		stack = append(stack, int(g))
+	}
+	for len(stack) > 0 {
+		g := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if g >= numGlyphs || keep[g] {
+			continue
+		}
+		keep[g] = true
+		b, err := outline(g)
+		if err != nil {
+			return nil, err
+		}
+		stack = append(stack, components(b)...)
+	}
+
+	var newGlyf bytes.Buffer
+	newLoca := make([]byte, 4*(numGlyphs+1))
+	for i := 0; i < numGlyphs; i++ {
+		binary.BigEndian.PutUint32(newLoca[4*i:], uint32(newGlyf.Len()))
+		if !keep[i] {
+			continue
+		}
+		b, err := outline(i)
+		if err != nil {
+			return nil, err
+		}
+		newGlyf.Write(b)
+		for newGlyf.Len()%4 != 0 {
+			newGlyf.WriteByte(0)
+		}
+	}
+	binary.BigEndian.PutUint32(newLoca[4*numGlyphs:], uint32(newGlyf.Len()))
+
+	newHead := append([]byte(nil), head...)
+	binary.BigEndian.PutUint32(newHead[8:], 0)  // checkSumAdjustment
+	binary.BigEndian.PutUint16(newHead[50:], 1) // indexToLocFormat
+	kept["head"] = newHead
+	kept["loca"] = newLoca
+	kept["glyf"] = newGlyf.Bytes()
+	if post := kept["post"]; len(post) >= 32 {
+		// Drop the glyph names, which are
+		// not needed to render the glyphs.
+		newPost := append([]byte(nil), post[:32]...)
+		binary.BigEndian.PutUint32(newPost, 0x00030000)
+		kept["post"] = newPost
+	}
+
+	return writeFont(kept), nil
+}
+
+// components returns the indices of the glyphs that the
+// compound glyph with the given outline is composed of.
+func components(b []byte) []int {
+	const (
+		argsAreWords   = 0x0001
+		haveScale      = 0x0008
+		moreComponents = 0x0020
+		haveXYScale    = 0x0040
+		haveTwoByTwo   = 0x0080
+	)
+	if len(b) < 10 || int16(binary.BigEndian.Uint16(b)) >= 0 {
+		return nil
+	}
+	var idx []int
+	for off := 10; off+4 <= len(b); {
+		flags := binary.BigEndian.Uint16(b[off:])
+		idx = append(idx, int(binary.BigEndian.Uint16(b[off+2:])))
+		off += 4
+		if flags&argsAreWords != 0 {
+			off += 4
+		} else {
+			off += 2
+		}
+		switch {
+		case flags&haveScale != 0:
+			off += 2
+		case flags&haveXYScale != 0:
+			off += 4
+		case flags&haveTwoByTwo != 0:
+			off += 8
+		}
+		if flags&moreComponents == 0 {
+			break
+		}
+	}
+	return idx
+}
+
+// writeFont returns TrueType font data holding the
+// given tables, which must include the head table.
+func writeFont(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	n := len(tags)
+	sel := 0
+	for 1<<uint(sel+1) <= n {
+		sel++
+	}
+	var buf bytes.Buffer
+	hdr := make([]byte, 12+16*n)
+	binary.BigEndian.PutUint32(hdr, 0x00010000)
+	binary.BigEndian.PutUint16(hdr[4:], uint16(n))
+	binary.BigEndian.PutUint16(hdr[6:], uint16(16<<uint(sel)))
+	binary.BigEndian.PutUint16(hdr[8:], uint16(sel))
+	binary.BigEndian.PutUint16(hdr[10:], uint16(16*n-16<<uint(sel)))
+	buf.Write(hdr)
+
+	var headOff int
+	for i, tag := range tags {
+		t := tables[tag]
+		rec := hdr[12+16*i:]
+		copy(rec, tag)
+		binary.BigEndian.PutUint32(rec[4:], checksum(t))
+		binary.BigEndian.PutUint32(rec[8:], uint32(buf.Len()))
+		binary.BigEndian.PutUint32(rec[12:], uint32(len(t)))
+		if tag == "head" {
+			headOff = buf.Len()
+		}
+		buf.Write(t)
+		for buf.Len()%4 != 0 {
+			buf.WriteByte(0)
+		}
+	}
+	font := buf.Bytes()
+	copy(font, hdr)
+	binary.BigEndian.PutUint32(font[headOff+8:], 0xb1b0afba-checksum(font))
+	return font
+}
+
+// checksum returns the TrueType checksum of b, the sum of
+// its big-endian uint32 words with the last word zero padded.
+func checksum(b []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(b); i += 4 {
+		var w [4]byte
+		copy(w[:], b[i:])
+		sum += binary.BigEndian.Uint32(w[:])
+	}
+	return sum
+}
+
+// Tag returns a six letter tag identifying a font subset
+// with the given glyphs, as used to prefix the names of
+// subset fonts.
+func Tag(glyphs []truetype.Index) string {
+	h := fnv.New32a()
+	for _, g := range glyphs {
+		h.Write([]byte{byte(g >> 8), byte(g)})
+	}
+	v := h.Sum32()
+	tag := make([]byte, 6)
+	for i := range tag {
+		tag[i] = 'A' + byte(v%26)
+		v /= 26
+	}
+	return string(tag)
+}
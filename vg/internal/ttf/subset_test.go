@@ -0,0 +1,72 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ttf
+
+import (
+	"reflect"
+	"testing"
+
+	"code.google.com/p/freetype-go/freetype/truetype"
+	"github.com/gonum/plot/vg"
+)
+
+const glyphScale = 1000
+
+func TestSubset(t *testing.T) {
+	fnt, err := vg.MakeFont("Times-Roman", 12)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	orig := fnt.Font()
+
+	// The Cyrillic А is a compound glyph made
+	// of the Latin A, which is not shown.
+	const shown = "éА"
+	var glyphs []truetype.Index
+	for _, r := range shown {
+		glyphs = append(glyphs, orig.Index(r))
+	}
+	data, err := Subset(fnt.Data(), glyphs, OutlineTables)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data) >= len(fnt.Data())/4 {
+		t.Errorf("subset too large: got:%d bytes from %d", len(data), len(fnt.Data()))
+	}
+	if sum := checksum(data); sum != 0xb1b0afba {
+		t.Errorf("unexpected font checksum: got:%#x want:0xb1b0afba", sum)
+	}
+	sub, err := truetype.Parse(data)
+	if err != nil {
+		t.Fatalf("failed to parse subset: %v", err)
+	}
+
+	want, got := truetype.NewGlyphBuf(), truetype.NewGlyphBuf()
+	for _, r := range shown {
+		i := orig.Index(r)
+		if err := want.Load(orig, glyphScale, i, nil); err != nil {
+			t.Fatalf("failed to load glyph for %q: %v", r, err)
+		}
+		if err := got.Load(sub, glyphScale, i, nil); err != nil {
+			t.Fatalf("failed to load subset glyph for %q: %v", r, err)
+		}
+		if !reflect.DeepEqual(got.Point, want.Point) || !reflect.DeepEqual(got.End, want.End) {
+			t.Errorf("subset glyph for %q differs from the original", r)
+		}
+		if got, want := sub.HMetric(glyphScale, i), orig.HMetric(glyphScale, i); got != want {
+			t.Errorf("unexpected metrics for %q: got:%v want:%v", r, got, want)
+		}
+	}
+	if err := got.Load(sub, glyphScale, orig.Index('B'), nil); err != nil {
+		t.Fatalf("failed to load removed glyph: %v", err)
+	}
+	if len(got.Point) != 0 {
+		t.Errorf("unexpected outline for removed glyph: %d points", len(got.Point))
+	}
+
+	if _, err := Subset([]byte("not a font"), glyphs, OutlineTables); err == nil {
+		t.Error("expected error subsetting invalid data")
+	}
+}
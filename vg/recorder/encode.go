@@ -0,0 +1,1015 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recorder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gonum/plot/vg"
+)
+
+// Format specifies the serialization format of a recorded
+// action stream.
+type Format int
+
+const (
+	// Text is a line oriented, human readable format with
+	// one action per line.
+	Text Format = iota
+
+	// Binary is a compact binary format.
+	Binary
+)
+
+const (
+	// textMagic and binaryMagic start every text and binary
+	// recording respectively.
+	textMagic   = "vgrec"
+	binaryMagic = "\x89VGR"
+
+	// version is the current version of the recording formats.
+	version = 1
+)
+
+// Binary action opcodes. If the opcode has the hasCaller bit
+// set, the action is followed by a caller location.
+const (
+	opFont byte = iota + 1
+	opSetLineWidth
+	opSetLineDash
+	opSetColor
+	opRotate
+	opTranslate
+	opScale
+	opPush
+	opPop
+	opStroke
+	opFill
+	opFillString
+	opDPI
+	opComment
+	opDrawImage
+	opSetLineCap
+	opSetLineJoin
+	opSetMiterLimit
+
+	hasCaller byte = 0x80
+)
+
+// Color tags used by both formats to retain the concrete
+// type of a recorded color.
+var colorTags = []string{
+	"nil",
+	"rgba",
+	"nrgba",
+	"rgba64",
+	"nrgba64",
+	"gray",
+	"gray16",
+	"alpha",
+	"alpha16",
+}
+
+// colorComponents holds the number of components of each
+// color tag.
+var colorComponents = [...]int{0, 4, 4, 4, 4, 1, 1, 1, 1}
+
+// Encode writes the recorded actions to w in the given format.
+// Fonts are written as name and size pairs before the first action
+// using them, and caller locations are written for actions that
+// have them.
+func (c *Canvas) Encode(w io.Writer, f Format) error {
+	var e actionEncoder
+	switch f {
+	case Text:
+		e = &textEncoder{w: bufio.NewWriter(w), fonts: make(map[fontID]int)}
+	case Binary:
+		e = &binaryEncoder{w: bufio.NewWriter(w), fonts: make(map[fontID]int)}
+	default:
+		return fmt.Errorf("recorder: unknown format: %d", f)
+	}
+	e.header(c.Resolution)
+	for _, a := range c.Actions {
+		if err := e.encode(a); err != nil {
+			return err
+		}
+	}
+	return e.flush()
+}
+
+// Decode returns a Canvas holding the Resolution and Actions of the
+// recording read from r. The format of the recording is detected
+// from its header.
+//
+// Colors are decoded to the concrete color type that was recorded
+// for the standard image/color types. Other color types are decoded
+// as color.RGBA64. Images are decoded from their PNG encoding and
+// have the type returned by image/png.
+func Decode(r io.Reader) (*Canvas, error) {
+	b := bufio.NewReader(r)
+	magic, err := b.Peek(len(binaryMagic))
+	if err != nil {
+		return nil, fmt.Errorf("recorder: failed to read header: %v", err)
+	}
+	switch {
+	case string(magic) == binaryMagic:
+		return decodeBinary(b)
+	case strings.HasPrefix(textMagic, string(magic)):
+		return decodeText(b)
+	default:
+		return nil, errors.New("recorder: unknown recording format")
+	}
+}
+
+// actionEncoder is implemented by the format encoders.
+type actionEncoder interface {
+	header(dpi float64)
+	encode(Action) error
+	flush() error
+}
+
+// colorTag returns the tag index and the components of clr.
+// Components are 8 bit values for the 8 bit color types and 16 bit
+// values otherwise.
+func colorTag(clr color.Color) (int, []uint16) {
+	switch c := clr.(type) {
+	case nil:
+		return 0, nil
+	case color.RGBA:
+		return 1, []uint16{uint16(c.R), uint16(c.G), uint16(c.B), uint16(c.A)}
+	case color.NRGBA:
+		return 2, []uint16{uint16(c.R), uint16(c.G), uint16(c.B), uint16(c.A)}
+	case color.NRGBA64:
+		return 4, []uint16{c.R, c.G, c.B, c.A}
+	case color.Gray:
+		return 5, []uint16{uint16(c.Y)}
+	case color.Gray16:
+		return 6, []uint16{c.Y}
+	case color.Alpha:
+		return 7, []uint16{uint16(c.A)}
+	case color.Alpha16:
+		return 8, []uint16{c.A}
+	default:
+		r, g, b, a := c.RGBA()
+		return 3, []uint16{uint16(r), uint16(g), uint16(b), uint16(a)}
+	}
+}
+
+// makeColor returns the color for the given tag index and components.
+func makeColor(tag int, v []uint16) (color.Color, error) {
+	if tag < 0 || tag >= len(colorComponents) {
+		return nil, fmt.Errorf("unknown color tag: %d", tag)
+	}
+	if len(v) != colorComponents[tag] {
+		return nil, fmt.Errorf("wrong number of components for %s color: %d", colorTags[tag], len(v))
+	}
+	if tag == 1 || tag == 2 || tag == 5 || tag == 7 {
+		for _, c := range v {
+			if c > math.MaxUint8 {
+				return nil, fmt.Errorf("component out of range for %s color: %d", colorTags[tag], c)
+			}
+		}
+	}
+	switch tag {
+	case 0:
+		return nil, nil
+	case 1:
+		return color.RGBA{R: uint8(v[0]), G: uint8(v[1]), B: uint8(v[2]), A: uint8(v[3])}, nil
+	case 2:
+		return color.NRGBA{R: uint8(v[0]), G: uint8(v[1]), B: uint8(v[2]), A: uint8(v[3])}, nil
+	case 3:
+		return color.RGBA64{R: v[0], G: v[1], B: v[2], A: v[3]}, nil
+	case 4:
+		return color.NRGBA64{R: v[0], G: v[1], B: v[2], A: v[3]}, nil
+	case 5:
+		return color.Gray{Y: uint8(v[0])}, nil
+	case 6:
+		return color.Gray16{Y: v[0]}, nil
+	case 7:
+		return color.Alpha{A: uint8(v[0])}, nil
+	default:
+		return color.Alpha16{A: v[0]}, nil
+	}
+}
+
+// encodePNG returns the PNG encoding of img. Images are
+// recorded as PNG in both formats, so the concrete type of
+// a decoded image may differ from the recorded image.
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: failed to encode image: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// textEncoder writes the text recording format.
+type textEncoder struct {
+	w     *bufio.Writer
+	fonts map[fontID]int
+}
+
+func (e *textEncoder) header(dpi float64) {
+	fmt.Fprintf(e.w, "%s %d text\ndpi %s\n", textMagic, version, formatFloat(dpi))
+}
+
+func (e *textEncoder) flush() error {
+	return e.w.Flush()
+}
+
+func (e *textEncoder) encode(a Action) error {
+	var line []string
+	switch a := a.(type) {
+	case *SetLineWidth:
+		line = []string{"SetLineWidth", formatLength(a.Width)}
+	case *SetLineDash:
+		line = []string{"SetLineDash", strconv.Itoa(len(a.Dashes))}
+		for _, d := range a.Dashes {
+			line = append(line, formatLength(d))
+		}
+		line = append(line, formatLength(a.Offsets))
+	case *SetLineCap:
+		line = []string{"SetLineCap", strconv.Itoa(int(a.Cap))}
+	case *SetLineJoin:
+		line = []string{"SetLineJoin", strconv.Itoa(int(a.Join))}
+	case *SetMiterLimit:
+		line = []string{"SetMiterLimit", formatFloat(a.Limit)}
+	case *SetColor:
+		tag, v := colorTag(a.Color)
+		line = []string{"SetColor", colorTags[tag]}
+		for _, c := range v {
+			line = append(line, strconv.Itoa(int(c)))
+		}
+	case *Rotate:
+		line = []string{"Rotate", formatFloat(a.Angle)}
+	case *Translate:
+		line = []string{"Translate", formatLength(a.X), formatLength(a.Y)}
+	case *Scale:
+		line = []string{"Scale", formatFloat(a.X), formatFloat(a.Y)}
+	case *Push:
+		line = []string{"Push"}
+	case *Pop:
+		line = []string{"Pop"}
+	case *Stroke:
+		line = append([]string{"Stroke"}, textPath(a.Path)...)
+	case *Fill:
+		line = append([]string{"Fill"}, textPath(a.Path)...)
+	case *FillString:
+		id, ok := e.fonts[fontID{name: a.Font, size: a.Size}]
+		if !ok {
+			id = len(e.fonts)
+			e.fonts[fontID{name: a.Font, size: a.Size}] = id
+			fmt.Fprintf(e.w, "font %d %s %s\n", id, strconv.Quote(a.Font), formatLength(a.Size))
+		}
+		line = []string{"FillString", strconv.Itoa(id), formatLength(a.X), formatLength(a.Y), strconv.Quote(a.String)}
+	case *DrawImage:
+		data, err := encodePNG(a.Image)
+		if err != nil {
+			return err
+		}
+		line = []string{"DrawImage", formatLength(a.X), formatLength(a.Y), formatLength(a.Width), formatLength(a.Height),
+			strconv.Itoa(int(a.Interpolation)), base64.StdEncoding.EncodeToString(data)}
+	case *DPI:
+		line = []string{"DPI"}
+	case *Comment:
+		line = []string{"Comment", strconv.Quote(a.Text)}
+	default:
+		return fmt.Errorf("recorder: cannot encode action type %T", a)
+	}
+	if l := a.callerLocation(); l.haveCaller {
+		fmt.Fprintf(e.w, "@%s:%d ", strconv.Quote(l.file), l.line)
+	}
+	_, err := fmt.Fprintln(e.w, strings.Join(line, " "))
+	return err
+}
+
+// textPath returns the fields of the text representation of a path.
+// Each component is written as a single letter followed by its
+// coordinates: M x y, L x y, A x y radius start angle, and Z.
+func textPath(p vg.Path) []string {
+	var f []string
+	for _, c := range p {
+		switch c.Type {
+		case vg.MoveComp:
+			f = append(f, "M", formatLength(c.X), formatLength(c.Y))
+		case vg.LineComp:
+			f = append(f, "L", formatLength(c.X), formatLength(c.Y))
+		case vg.ArcComp:
+			f = append(f, "A", formatLength(c.X), formatLength(c.Y), formatLength(c.Radius),
+				formatFloat(c.Start), formatFloat(c.Angle))
+		case vg.CloseComp:
+			f = append(f, "Z")
+		default:
+			f = append(f, "?"+strconv.Itoa(c.Type))
+		}
+	}
+	return f
+}
+
+// formatFloat returns the shortest representation of v that
+// decodes to exactly v.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func formatLength(l vg.Length) string {
+	return formatFloat(float64(l))
+}
+
+// decodeText decodes a text recording.
+func decodeText(r *bufio.Reader) (*Canvas, error) {
+	d := textDecoder{fonts: make(map[int]fontID)}
+	c := new(Canvas)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<30)
+	for sc.Scan() {
+		d.line++
+		f, err := fields(sc.Text())
+		if err != nil {
+			return nil, d.errorf("%v", err)
+		}
+		if len(f) == 0 {
+			continue
+		}
+		if d.line == 1 {
+			if len(f) != 3 || f[0] != textMagic || f[2] != "text" {
+				return nil, d.errorf("invalid header")
+			}
+			if v, err := strconv.Atoi(f[1]); err != nil || v != version {
+				return nil, d.errorf("unsupported version: %s", f[1])
+			}
+			continue
+		}
+		d.fields = f
+		d.n = 1
+		switch f[0] {
+		case "dpi":
+			c.Resolution = d.float(1)
+		case "font":
+			d.fonts[int(d.int(1))] = fontID{name: d.str(2), size: d.length(3)}
+		default:
+			a, err := d.action()
+			if err != nil {
+				return nil, err
+			}
+			c.Actions = append(c.Actions, a)
+		}
+		if d.err != nil {
+			return nil, d.err
+		}
+		if d.n != len(d.fields) {
+			return nil, d.errorf("unexpected trailing fields: %q", d.fields[d.n:])
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if d.line == 0 {
+		return nil, errors.New("recorder: empty recording")
+	}
+	return c, nil
+}
+
+// textDecoder holds the state of a text recording decoding.
+type textDecoder struct {
+	line   int
+	fields []string
+	n      int // n is the number of consumed fields.
+	fonts  map[int]fontID
+	err    error
+}
+
+func (d *textDecoder) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("recorder: line %d: %s", d.line, fmt.Sprintf(format, args...))
+}
+
+func (d *textDecoder) field(i int) string {
+	if d.err != nil {
+		return ""
+	}
+	if i >= len(d.fields) {
+		d.err = d.errorf("missing field %d of %s", i, d.fields[0])
+		return ""
+	}
+	if i+1 > d.n {
+		d.n = i + 1
+	}
+	return d.fields[i]
+}
+
+func (d *textDecoder) float(i int) float64 {
+	s := d.field(i)
+	if d.err != nil {
+		return 0
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		d.err = d.errorf("invalid number: %q", s)
+	}
+	return v
+}
+
+func (d *textDecoder) length(i int) vg.Length {
+	return vg.Length(d.float(i))
+}
+
+func (d *textDecoder) int(i int) int64 {
+	s := d.field(i)
+	if d.err != nil {
+		return 0
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		d.err = d.errorf("invalid integer: %q", s)
+	}
+	return v
+}
+
+func (d *textDecoder) str(i int) string {
+	s := d.field(i)
+	if d.err != nil {
+		return ""
+	}
+	v, err := strconv.Unquote(s)
+	if err != nil {
+		d.err = d.errorf("invalid string: %s", s)
+	}
+	return v
+}
+
+// action decodes the action held in the current fields.
+func (d *textDecoder) action() (Action, error) {
+	var l callerLocation
+	if strings.HasPrefix(d.fields[0], "@") {
+		loc := d.fields[0][1:]
+		i := strings.LastIndex(loc, ":")
+		if i < 0 {
+			return nil, d.errorf("invalid caller location: %s", loc)
+		}
+		file, err := strconv.Unquote(loc[:i])
+		if err != nil {
+			return nil, d.errorf("invalid caller file: %s", loc[:i])
+		}
+		line, err := strconv.Atoi(loc[i+1:])
+		if err != nil {
+			return nil, d.errorf("invalid caller line: %s", loc[i+1:])
+		}
+		l = callerLocation{haveCaller: true, file: file, line: line}
+		d.fields = d.fields[1:]
+		if len(d.fields) == 0 {
+			return nil, d.errorf("missing action")
+		}
+	}
+	d.n = 1
+
+	var a Action
+	switch d.fields[0] {
+	case "SetLineWidth":
+		a = &SetLineWidth{Width: d.length(1)}
+	case "SetLineDash":
+		n := int(d.int(1))
+		if n < 0 || n > len(d.fields) {
+			return nil, d.errorf("invalid dash count: %d", n)
+		}
+		var dashes []vg.Length
+		if n > 0 {
+			dashes = make([]vg.Length, n)
+		}
+		for i := range dashes {
+			dashes[i] = d.length(2 + i)
+		}
+		a = &SetLineDash{Dashes: dashes, Offsets: d.length(2 + n)}
+	case "SetLineCap":
+		a = &SetLineCap{Cap: vg.LineCap(d.int(1))}
+	case "SetLineJoin":
+		a = &SetLineJoin{Join: vg.LineJoin(d.int(1))}
+	case "SetMiterLimit":
+		a = &SetMiterLimit{Limit: d.float(1)}
+	case "SetColor":
+		tag := -1
+		name := d.field(1)
+		for i, t := range colorTags {
+			if t == name {
+				tag = i
+			}
+		}
+		if tag < 0 {
+			return nil, d.errorf("unknown color type: %q", name)
+		}
+		var v []uint16
+		for i := 2; i < len(d.fields); i++ {
+			c := d.int(i)
+			if c < 0 || c > math.MaxUint16 {
+				return nil, d.errorf("color component out of range: %d", c)
+			}
+			v = append(v, uint16(c))
+		}
+		clr, err := makeColor(tag, v)
+		if err != nil {
+			return nil, d.errorf("%v", err)
+		}
+		a = &SetColor{Color: clr}
+	case "Rotate":
+		a = &Rotate{Angle: d.float(1)}
+	case "Translate":
+		a = &Translate{X: d.length(1), Y: d.length(2)}
+	case "Scale":
+		a = &Scale{X: d.float(1), Y: d.float(2)}
+	case "Push":
+		a = &Push{}
+	case "Pop":
+		a = &Pop{}
+	case "Stroke":
+		a = &Stroke{Path: d.path()}
+	case "Fill":
+		a = &Fill{Path: d.path()}
+	case "FillString":
+		id := int(d.int(1))
+		f, ok := d.fonts[id]
+		if !ok && d.err == nil {
+			return nil, d.errorf("undefined font: %d", id)
+		}
+		a = &FillString{
+			Font:   f.name,
+			Size:   f.size,
+			X:      d.length(2),
+			Y:      d.length(3),
+			String: d.str(4),
+		}
+	case "DrawImage":
+		img := &DrawImage{
+			X:             d.length(1),
+			Y:             d.length(2),
+			Width:         d.length(3),
+			Height:        d.length(4),
+			Interpolation: vg.Interpolation(d.int(5)),
+		}
+		data, err := base64.StdEncoding.DecodeString(d.field(6))
+		if err != nil && d.err == nil {
+			return nil, d.errorf("invalid image data: %v", err)
+		}
+		if d.err == nil {
+			img.Image, err = png.Decode(bytes.NewReader(data))
+			if err != nil {
+				return nil, d.errorf("invalid image: %v", err)
+			}
+		}
+		a = img
+	case "DPI":
+		a = &DPI{}
+	case "Comment":
+		a = &Comment{Text: d.str(1)}
+	default:
+		return nil, d.errorf("unknown action: %s", d.fields[0])
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	*a.callerLocation() = l
+	return a, nil
+}
+
+// path decodes a path from the fields following the action name.
+func (d *textDecoder) path() vg.Path {
+	var p vg.Path
+	for i := 1; i < len(d.fields) && d.err == nil; {
+		switch d.field(i) {
+		case "M":
+			p.Move(d.length(i+1), d.length(i+2))
+			i += 3
+		case "L":
+			p.Line(d.length(i+1), d.length(i+2))
+			i += 3
+		case "A":
+			p.Arc(d.length(i+1), d.length(i+2), d.length(i+3), d.float(i+4), d.float(i+5))
+			i += 6
+		case "Z":
+			p.Close()
+			i++
+		default:
+			d.err = d.errorf("unknown path component: %q", d.fields[i])
+		}
+	}
+	return p
+}
+
+// fields splits a line into space separated fields. Double quoted
+// fields may contain spaces and are returned with their quotes.
+func fields(line string) ([]string, error) {
+	var f []string
+	for {
+		line = strings.TrimLeft(line, " \t")
+		if line == "" {
+			return f, nil
+		}
+		end := 0
+		quoted := false
+		for end < len(line) && (quoted || (line[end] != ' ' && line[end] != '\t')) {
+			switch line[end] {
+			case '\\':
+				if quoted {
+					end++
+				}
+			case '"':
+				quoted = !quoted
+			}
+			end++
+		}
+		if quoted {
+			return nil, errors.New("unterminated string")
+		}
+		if end > len(line) {
+			end = len(line)
+		}
+		f = append(f, line[:end])
+		line = line[end:]
+	}
+}
+
+// binaryEncoder writes the binary recording format.
+//
+// All floating point values are written as little endian IEEE 754
+// doubles, integers and string lengths as unsigned varints and
+// strings as their UTF-8 bytes.
+type binaryEncoder struct {
+	w     *bufio.Writer
+	fonts map[fontID]int
+	buf   [binary.MaxVarintLen64]byte
+}
+
+func (e *binaryEncoder) header(dpi float64) {
+	e.w.WriteString(binaryMagic)
+	e.uint(version)
+	e.float(dpi)
+}
+
+func (e *binaryEncoder) flush() error {
+	return e.w.Flush()
+}
+
+func (e *binaryEncoder) uint(v uint64) {
+	n := binary.PutUvarint(e.buf[:], v)
+	e.w.Write(e.buf[:n])
+}
+
+func (e *binaryEncoder) float(v float64) {
+	binary.LittleEndian.PutUint64(e.buf[:8], math.Float64bits(v))
+	e.w.Write(e.buf[:8])
+}
+
+func (e *binaryEncoder) length(l vg.Length) {
+	e.float(float64(l))
+}
+
+func (e *binaryEncoder) string(s string) {
+	e.uint(uint64(len(s)))
+	e.w.WriteString(s)
+}
+
+func (e *binaryEncoder) op(op byte, a Action) {
+	l := a.callerLocation()
+	if l.haveCaller {
+		op |= hasCaller
+	}
+	e.w.WriteByte(op)
+	if l.haveCaller {
+		e.string(l.file)
+		e.uint(uint64(l.line))
+	}
+}
+
+func (e *binaryEncoder) path(p vg.Path) {
+	e.uint(uint64(len(p)))
+	for _, c := range p {
+		e.w.WriteByte(byte(c.Type))
+		switch c.Type {
+		case vg.MoveComp, vg.LineComp:
+			e.length(c.X)
+			e.length(c.Y)
+		case vg.ArcComp:
+			e.length(c.X)
+			e.length(c.Y)
+			e.length(c.Radius)
+			e.float(c.Start)
+			e.float(c.Angle)
+		}
+	}
+}
+
+func (e *binaryEncoder) encode(a Action) error {
+	switch a := a.(type) {
+	case *SetLineWidth:
+		e.op(opSetLineWidth, a)
+		e.length(a.Width)
+	case *SetLineDash:
+		e.op(opSetLineDash, a)
+		e.uint(uint64(len(a.Dashes)))
+		for _, d := range a.Dashes {
+			e.length(d)
+		}
+		e.length(a.Offsets)
+	case *SetLineCap:
+		e.op(opSetLineCap, a)
+		e.uint(uint64(a.Cap))
+	case *SetLineJoin:
+		e.op(opSetLineJoin, a)
+		e.uint(uint64(a.Join))
+	case *SetMiterLimit:
+		e.op(opSetMiterLimit, a)
+		e.float(a.Limit)
+	case *SetColor:
+		e.op(opSetColor, a)
+		tag, v := colorTag(a.Color)
+		e.w.WriteByte(byte(tag))
+		for _, c := range v {
+			e.uint(uint64(c))
+		}
+	case *Rotate:
+		e.op(opRotate, a)
+		e.float(a.Angle)
+	case *Translate:
+		e.op(opTranslate, a)
+		e.length(a.X)
+		e.length(a.Y)
+	case *Scale:
+		e.op(opScale, a)
+		e.float(a.X)
+		e.float(a.Y)
+	case *Push:
+		e.op(opPush, a)
+	case *Pop:
+		e.op(opPop, a)
+	case *Stroke:
+		e.op(opStroke, a)
+		e.path(a.Path)
+	case *Fill:
+		e.op(opFill, a)
+		e.path(a.Path)
+	case *FillString:
+		id, ok := e.fonts[fontID{name: a.Font, size: a.Size}]
+		if !ok {
+			id = len(e.fonts)
+			e.fonts[fontID{name: a.Font, size: a.Size}] = id
+			e.w.WriteByte(opFont)
+			e.string(a.Font)
+			e.length(a.Size)
+		}
+		e.op(opFillString, a)
+		e.uint(uint64(id))
+		e.length(a.X)
+		e.length(a.Y)
+		e.string(a.String)
+	case *DrawImage:
+		data, err := encodePNG(a.Image)
+		if err != nil {
+			return err
+		}
+		e.op(opDrawImage, a)
+		e.length(a.X)
+		e.length(a.Y)
+		e.length(a.Width)
+		e.length(a.Height)
+		e.uint(uint64(a.Interpolation))
+		e.string(string(data))
+	case *DPI:
+		e.op(opDPI, a)
+	case *Comment:
+		e.op(opComment, a)
+		e.string(a.Text)
+	default:
+		return fmt.Errorf("recorder: cannot encode action type %T", a)
+	}
+	return nil
+}
+
+// binaryDecoder reads the binary recording format.
+type binaryDecoder struct {
+	r   *bufio.Reader
+	err error
+}
+
+// decodeBinary decodes a binary recording.
+func decodeBinary(r *bufio.Reader) (*Canvas, error) {
+	d := binaryDecoder{r: r}
+	magic := make([]byte, len(binaryMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return nil, err
+	}
+	if v := d.uint(); d.err == nil && v != version {
+		return nil, fmt.Errorf("recorder: unsupported version: %d", v)
+	}
+	c := &Canvas{Resolution: d.float()}
+	var fonts []fontID
+	for d.err == nil {
+		op, err := r.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var l callerLocation
+		if op&hasCaller != 0 {
+			l = callerLocation{haveCaller: true, file: d.string(), line: int(d.uint())}
+			op &^= hasCaller
+		}
+
+		var a Action
+		switch op {
+		case opFont:
+			fonts = append(fonts, fontID{name: d.string(), size: d.length()})
+			continue
+		case opSetLineWidth:
+			a = &SetLineWidth{Width: d.length()}
+		case opSetLineDash:
+			// The dashes are appended as they are read so that
+			// a corrupt count cannot allocate more than the
+			// input holds.
+			n := d.count()
+			var dashes []vg.Length
+			for i := 0; i < n && d.err == nil; i++ {
+				dashes = append(dashes, d.length())
+			}
+			a = &SetLineDash{Dashes: dashes, Offsets: d.length()}
+		case opSetLineCap:
+			a = &SetLineCap{Cap: vg.LineCap(d.uint())}
+		case opSetLineJoin:
+			a = &SetLineJoin{Join: vg.LineJoin(d.uint())}
+		case opSetMiterLimit:
+			a = &SetMiterLimit{Limit: d.float()}
+		case opSetColor:
+			tag, err := r.ReadByte()
+			if err != nil {
+				return nil, unexpected(err)
+			}
+			if int(tag) >= len(colorTags) {
+				return nil, fmt.Errorf("recorder: unknown color tag: %d", tag)
+			}
+			v := make([]uint16, colorComponents[tag])
+			for i := range v {
+				c := d.uint()
+				if c > math.MaxUint16 {
+					return nil, fmt.Errorf("recorder: color component out of range: %d", c)
+				}
+				v[i] = uint16(c)
+			}
+			clr, err := makeColor(int(tag), v)
+			if err != nil {
+				return nil, fmt.Errorf("recorder: %v", err)
+			}
+			a = &SetColor{Color: clr}
+		case opRotate:
+			a = &Rotate{Angle: d.float()}
+		case opTranslate:
+			a = &Translate{X: d.length(), Y: d.length()}
+		case opScale:
+			a = &Scale{X: d.float(), Y: d.float()}
+		case opPush:
+			a = &Push{}
+		case opPop:
+			a = &Pop{}
+		case opStroke:
+			a = &Stroke{Path: d.path()}
+		case opFill:
+			a = &Fill{Path: d.path()}
+		case opFillString:
+			id := d.uint()
+			if d.err == nil && id >= uint64(len(fonts)) {
+				return nil, fmt.Errorf("recorder: undefined font: %d", id)
+			}
+			a = &FillString{X: d.length(), Y: d.length(), String: d.string()}
+			if d.err == nil {
+				a.(*FillString).Font = fonts[id].name
+				a.(*FillString).Size = fonts[id].size
+			}
+		case opDrawImage:
+			img := &DrawImage{
+				X:             d.length(),
+				Y:             d.length(),
+				Width:         d.length(),
+				Height:        d.length(),
+				Interpolation: vg.Interpolation(d.uint()),
+			}
+			data := d.string()
+			if d.err == nil {
+				img.Image, err = png.Decode(strings.NewReader(data))
+				if err != nil {
+					return nil, fmt.Errorf("recorder: invalid image: %v", err)
+				}
+			}
+			a = img
+		case opDPI:
+			a = &DPI{}
+		case opComment:
+			a = &Comment{Text: d.string()}
+		default:
+			return nil, fmt.Errorf("recorder: unknown opcode: %#x", op)
+		}
+		*a.callerLocation() = l
+		c.Actions = append(c.Actions, a)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return c, nil
+}
+
+// unexpected converts an io.EOF to an io.ErrUnexpectedEOF.
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func (d *binaryDecoder) uint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		d.err = unexpected(err)
+	}
+	return v
+}
+
+// count returns a decoded element count. The count is not
+// checked against the length of the input, so callers must
+// read the elements one at a time rather than allocating
+// space for all of them up front.
+func (d *binaryDecoder) count() int {
+	n := d.uint()
+	if d.err == nil && n > math.MaxInt32 {
+		d.err = fmt.Errorf("recorder: invalid count: %d", n)
+	}
+	return int(n)
+}
+
+func (d *binaryDecoder) float() float64 {
+	if d.err != nil {
+		return 0
+	}
+	var b [8]byte
+	if _, err := io.ReadFull(d.r, b[:]); err != nil {
+		d.err = unexpected(err)
+		return 0
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(b[:]))
+}
+
+func (d *binaryDecoder) length() vg.Length {
+	return vg.Length(d.float())
+}
+
+func (d *binaryDecoder) string() string {
+	n := d.count()
+	if d.err != nil {
+		return ""
+	}
+	var b bytes.Buffer
+	if _, err := io.CopyN(&b, d.r, int64(n)); err != nil {
+		d.err = unexpected(err)
+		return ""
+	}
+	return b.String()
+}
+
+func (d *binaryDecoder) path() vg.Path {
+	n := d.count()
+	var p vg.Path
+	for i := 0; i < n && d.err == nil; i++ {
+		typ, err := d.r.ReadByte()
+		if err != nil {
+			d.err = unexpected(err)
+			break
+		}
+		switch int(typ) {
+		case vg.MoveComp:
+			p.Move(d.length(), d.length())
+		case vg.LineComp:
+			p.Line(d.length(), d.length())
+		case vg.ArcComp:
+			p.Arc(d.length(), d.length(), d.length(), d.float(), d.float())
+		case vg.CloseComp:
+			p.Close()
+		default:
+			d.err = fmt.Errorf("recorder: unknown path component: %d", typ)
+		}
+	}
+	return p
+}
@@ -0,0 +1,120 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recorder
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/gonum/plot/vg"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	rec := New(72)
+	rec.Actions = append(rec.Actions, &FillString{Font: "Times-Roman", Size: 12, X: 0, Y: 10, String: "Text"})
+	rec.Comment("End of \"preamble\"\n")
+	rec.Scale(1, 2)
+	rec.Rotate(math.Pi / 3)
+	rec.KeepCaller = true
+	rec.Stroke(vg.Path{{Type: vg.MoveComp, X: 3, Y: 4}, {Type: vg.ArcComp, X: 1, Y: 2, Radius: 0.1, Start: 0.3, Angle: -2}})
+	rec.Push()
+	rec.Pop()
+	rec.Translate(3, 4)
+	rec.KeepCaller = false
+	rec.DPI()
+	rec.SetLineWidth(100)
+	rec.SetLineDash([]vg.Length{2, 5}, 6)
+	rec.SetLineDash(nil, 0)
+	rec.SetLineCap(vg.RoundCap)
+	rec.SetLineJoin(vg.BevelJoin)
+	rec.SetMiterLimit(2.5)
+	rec.SetColor(color.RGBA{R: 0x65, G: 0x23, B: 0xf2})
+	rec.SetColor(color.Gray16{Y: 0x1234})
+	rec.SetColor(color.YCbCr{Y: 10, Cb: 20, Cr: 30})
+	rec.SetColor(nil)
+	rec.Actions = append(rec.Actions, &FillString{Font: "Helvetica", Size: 10, X: 1.5, Y: -3, String: "αβγ \"quoted\" text"})
+	rec.Actions = append(rec.Actions, &FillString{Font: "Times-Roman", Size: 12, X: 0, Y: 0, String: ""})
+	rec.Fill(vg.Path{{Type: vg.MoveComp, X: 3, Y: 4}, {Type: vg.LineComp, X: 2, Y: 3}, {Type: vg.CloseComp}})
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(1, 0, color.NRGBA{R: 0xff, A: 0x80})
+	img.Set(2, 1, color.NRGBA{G: 0xff, B: 0x10, A: 0xff})
+	rec.DrawImage(1, 2, 30, 20, img, vg.Bilinear)
+
+	for _, f := range []Format{Text, Binary} {
+		var buf bytes.Buffer
+		err := rec.Encode(&buf, f)
+		if err != nil {
+			t.Fatalf("unexpected error encoding format %d: %v", f, err)
+		}
+		got, err := Decode(&buf)
+		if err != nil {
+			t.Fatalf("unexpected error decoding format %d: %v", f, err)
+		}
+		if got.Resolution != rec.Resolution {
+			t.Errorf("unexpected resolution for format %d: got:%v want:%v", f, got.Resolution, rec.Resolution)
+		}
+		if len(got.Actions) != len(rec.Actions) {
+			t.Fatalf("unexpected number of actions for format %d: got:%d want:%d", f, len(got.Actions), len(rec.Actions))
+		}
+		for i, a := range rec.Actions {
+			want := a.Call()
+			if sc, ok := a.(*SetColor); ok {
+				if c, ok := sc.Color.(color.YCbCr); ok {
+					// Non-standard color types are decoded as color.RGBA64.
+					r, g, b, a := c.RGBA()
+					want = (&SetColor{Color: color.RGBA64{R: uint16(r), G: uint16(g), B: uint16(b), A: uint16(a)}}).Call()
+				}
+			}
+			if got := got.Actions[i].Call(); got != want {
+				t.Errorf("unexpected action %d for format %d:\n\tgot: %#v\n\twant: %#v", i, f, got, want)
+			}
+			if a, ok := a.(*DrawImage); ok {
+				gotImg := got.Actions[i].(*DrawImage).Image
+				b := a.Image.Bounds()
+				for y := b.Min.Y; y < b.Max.Y; y++ {
+					for x := b.Min.X; x < b.Max.X; x++ {
+						if got, want := color.NRGBAModel.Convert(gotImg.At(x, y)), a.Image.At(x, y); got != want {
+							t.Errorf("unexpected pixel %d,%d for format %d: got:%v want:%v", x, y, f, got, want)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: "recorder: failed to read header"},
+		{in: "junk data", want: "recorder: unknown recording format"},
+		{in: "vgrec 2 text\n", want: "recorder: line 1: unsupported version: 2"},
+		{in: "vgrec 1 text\nRotate\n", want: "recorder: line 2: missing field 1 of Rotate"},
+		{in: "vgrec 1 text\nRotate 1 2\n", want: "recorder: line 2: unexpected trailing fields"},
+		{in: "vgrec 1 text\nFillString 0 1 2 \"x\"\n", want: "recorder: line 2: undefined font: 0"},
+		{in: "vgrec 1 text\nFill M 1\n", want: "recorder: line 2: missing field 3 of Fill"},
+		{in: "vgrec 1 text\nComment \"x\n", want: "recorder: line 2: unterminated string"},
+		{in: "vgrec 1 text\nSetColor rgba 1 2 3 256\n", want: "recorder: line 2: component out of range"},
+		{in: "vgrec 1 text\nJump 1\n", want: "recorder: line 2: unknown action: Jump"},
+		{in: "\x89VGR\x01\x00\x00\x00\x00\x00\x00\x52\x40\x7f", want: "recorder: unknown opcode: 0x7f"},
+		{in: "\x89VGR\x01\x00\x00\x00\x00\x00\x00\x52\x40\x05\x00", want: "unexpected EOF"},
+		{in: "\x89VGR\x01\x00\x00\x00\x00\x00\x00\x52\x40\x03\xff", want: "unexpected EOF"},
+		{in: "\x89VGR\x01\x00\x00\x00\x00\x00\x00\x52\x40\x03\xff\xff\xff\xff\x07", want: "unexpected EOF"},
+		{in: "\x89VGR\x01\x00\x00\x00\x00\x00\x00\x52\x40\x03\xff\xff\xff\xff\x0f", want: "recorder: invalid count"},
+		{in: "\x89VGR\x01\x00\x00\x00\x00\x00\x00\x52\x40\x0a\xff\xff\xff\xff\x07", want: "unexpected EOF"},
+		{in: "\x89VGR\x01\x00\x00\x00\x00\x00\x00\x52\x40\x0e\xff\xff\xff\xff\x07", want: "unexpected EOF"},
+	} {
+		_, err := Decode(strings.NewReader(test.in))
+		if err == nil || !strings.HasPrefix(err.Error(), test.want) {
+			t.Errorf("unexpected error for %q:\n\tgot: %v\n\twant prefix: %s", test.in, err, test.want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ package recorder
 
 import (
 	"fmt"
+	"image"
 	"image/color"
 	"runtime"
 
@@ -175,6 +176,84 @@ func (a *SetLineDash) callerLocation() *callerLocation {
 	return &a.l
 }
 
+// SetLineCap corresponds to the vg.Canvas.SetLineCap method.
+type SetLineCap struct {
+	Cap vg.LineCap
+
+	l callerLocation
+}
+
+// SetLineCap implements the SetLineCap method of the vg.Canvas interface.
+func (c *Canvas) SetLineCap(cap vg.LineCap) {
+	c.append(&SetLineCap{Cap: cap})
+}
+
+// Call returns the method call that generated the action.
+func (a *SetLineCap) Call() string {
+	return fmt.Sprintf("%sSetLineCap(%v)", a.l, a.Cap)
+}
+
+// ApplyTo applies the action to the given vg.Canvas.
+func (a *SetLineCap) ApplyTo(c vg.Canvas) {
+	c.SetLineCap(a.Cap)
+}
+
+func (a *SetLineCap) callerLocation() *callerLocation {
+	return &a.l
+}
+
+// SetLineJoin corresponds to the vg.Canvas.SetLineJoin method.
+type SetLineJoin struct {
+	Join vg.LineJoin
+
+	l callerLocation
+}
+
+// SetLineJoin implements the SetLineJoin method of the vg.Canvas interface.
+func (c *Canvas) SetLineJoin(join vg.LineJoin) {
+	c.append(&SetLineJoin{Join: join})
+}
+
+// Call returns the method call that generated the action.
+func (a *SetLineJoin) Call() string {
+	return fmt.Sprintf("%sSetLineJoin(%v)", a.l, a.Join)
+}
+
+// ApplyTo applies the action to the given vg.Canvas.
+func (a *SetLineJoin) ApplyTo(c vg.Canvas) {
+	c.SetLineJoin(a.Join)
+}
+
+func (a *SetLineJoin) callerLocation() *callerLocation {
+	return &a.l
+}
+
+// SetMiterLimit corresponds to the vg.Canvas.SetMiterLimit method.
+type SetMiterLimit struct {
+	Limit float64
+
+	l callerLocation
+}
+
+// SetMiterLimit implements the SetMiterLimit method of the vg.Canvas interface.
+func (c *Canvas) SetMiterLimit(limit float64) {
+	c.append(&SetMiterLimit{Limit: limit})
+}
+
+// Call returns the method call that generated the action.
+func (a *SetMiterLimit) Call() string {
+	return fmt.Sprintf("%sSetMiterLimit(%v)", a.l, a.Limit)
+}
+
+// ApplyTo applies the action to the given vg.Canvas.
+func (a *SetMiterLimit) ApplyTo(c vg.Canvas) {
+	c.SetMiterLimit(a.Limit)
+}
+
+func (a *SetMiterLimit) callerLocation() *callerLocation {
+	return &a.l
+}
+
 // SetColor corresponds to the vg.Canvas.SetColor method.
 type SetColor struct {
 	Color color.Color
@@ -415,6 +494,44 @@ func (a *FillString) callerLocation() *callerLocation {
 	return &a.l
 }
 
+// DrawImage corresponds to the vg.Canvas.DrawImage method.
+type DrawImage struct {
+	X, Y, Width, Height vg.Length
+	Image               image.Image
+	Interpolation       vg.Interpolation
+
+	l callerLocation
+}
+
+// DrawImage implements the DrawImage method of the vg.Canvas interface.
+func (c *Canvas) DrawImage(x, y, w, h vg.Length, img image.Image, interp vg.Interpolation) {
+	c.append(&DrawImage{
+		X: x, Y: y,
+		Width: w, Height: h,
+		Image:         img,
+		Interpolation: interp,
+	})
+}
+
+// ApplyTo applies the action to the given vg.Canvas.
+func (a *DrawImage) ApplyTo(c vg.Canvas) {
+	c.DrawImage(a.X, a.Y, a.Width, a.Height, a.Image, a.Interpolation)
+}
+
+// Call returns the pseudo method call that generated the action.
+func (a *DrawImage) Call() string {
+	var size image.Point
+	if a.Image != nil {
+		size = a.Image.Bounds().Size()
+	}
+	return fmt.Sprintf("%sDrawImage(%v, %v, %v, %v, %dx%d image, %v)",
+		a.l, a.X, a.Y, a.Width, a.Height, size.X, size.Y, a.Interpolation)
+}
+
+func (a *DrawImage) callerLocation() *callerLocation {
+	return &a.l
+}
+
 // DPI corresponds to the vg.Canvas.DPI method.
 type DPI struct {
 	l callerLocation
@@ -0,0 +1,127 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// vgreplay replays a recording made by a recorder.Canvas and
+// encoded with the recorder Encode method onto one of the vg
+// backends.
+//
+// Usage:
+//
+//	vgreplay [flags] <recording> <output>
+//
+// The output format is selected by the extension of the output
+// file name and may be one of ansi, eps, jpg, jpeg, kitty, pdf,
+// png, sixel, svg, tif, tiff or txt. The recording format does not store the
+// dimensions of the drawing, so these must be given with the -w
+// and -h flags.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/recorder"
+	"github.com/gonum/plot/vg/vgeps"
+	"github.com/gonum/plot/vg/vgimg"
+	"github.com/gonum/plot/vg/vgpdf"
+	"github.com/gonum/plot/vg/vgsvg"
+	"github.com/gonum/plot/vg/vgterm"
+)
+
+func main() {
+	var (
+		width  = flag.Float64("w", 4, "width of the output in inches")
+		height = flag.Float64("h", 4, "height of the output in inches")
+		dpi    = flag.Int("dpi", vgimg.DefaultDPI, "resolution of raster output in dots per inch")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] <recording> <output>\n", filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	err := replay(flag.Arg(0), flag.Arg(1), vg.Length(*width)*vg.Inch, vg.Length(*height)*vg.Inch, *dpi)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "vgreplay: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// replay decodes the recording in the file src and writes it to
+// the file dst using the backend selected by the extension of dst.
+func replay(src, dst string, w, h vg.Length, dpi int) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	rec, err := recorder.Decode(in)
+	if err != nil {
+		return err
+	}
+
+	c, err := canvasFor(filepath.Ext(dst), w, h, dpi)
+	if err != nil {
+		return err
+	}
+	err = rec.ReplayOn(c)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	_, err = c.WriteTo(out)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// canvasWriterTo is a vg.Canvas that can be written to an io.Writer.
+type canvasWriterTo interface {
+	vg.Canvas
+	io.WriterTo
+}
+
+// canvasFor returns a canvas of the given size for the format
+// specified by the file name extension ext.
+func canvasFor(ext string, w, h vg.Length, dpi int) (canvasWriterTo, error) {
+	switch format := strings.ToLower(strings.TrimPrefix(ext, ".")); format {
+	case "ansi":
+		return vgterm.New(w, h), nil
+	case "eps":
+		return vgeps.New(w, h), nil
+	case "jpg", "jpeg":
+		return vgimg.JpegCanvas{Canvas: vgimg.NewWith(vgimg.UseWH(w, h), vgimg.UseDPI(dpi))}, nil
+	case "kitty":
+		return vgimg.KittyCanvas{Canvas: vgimg.NewWith(vgimg.UseWH(w, h), vgimg.UseDPI(dpi))}, nil
+	case "pdf":
+		return vgpdf.New(w, h), nil
+	case "png":
+		return vgimg.PngCanvas{Canvas: vgimg.NewWith(vgimg.UseWH(w, h), vgimg.UseDPI(dpi))}, nil
+	case "sixel":
+		return vgimg.SixelCanvas{Canvas: vgimg.NewWith(vgimg.UseWH(w, h), vgimg.UseDPI(dpi))}, nil
+	case "svg":
+		return vgsvg.New(w, h), nil
+	case "tif", "tiff":
+		return vgimg.TiffCanvas{Canvas: vgimg.NewWith(vgimg.UseWH(w, h), vgimg.UseDPI(dpi))}, nil
+	case "txt":
+		return vgterm.NewWith(w, h, vgterm.UseColors(vgterm.NoColor)), nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %q", format)
+	}
+}
@@ -8,6 +8,7 @@
 package vg
 
 import (
+	"image"
 	"image/color"
 )
 
@@ -30,6 +31,27 @@ type Canvas interface {
 	// The initial dash pattern is a solid line.
 	SetLineDash(pattern []Length, offset Length)
 
+	// SetLineCap sets the shape drawn at the
+	// ends of stroked lines and dashes.
+	//
+	// The initial cap is ButtCap.
+	SetLineCap(LineCap)
+
+	// SetLineJoin sets the shape drawn at the
+	// corners where the segments of stroked
+	// paths meet.
+	//
+	// The initial join is MiterJoin.
+	SetLineJoin(LineJoin)
+
+	// SetMiterLimit sets the limit on the ratio
+	// of the length of a mitered corner to the
+	// line width, above which the corner is
+	// beveled instead.
+	//
+	// The initial miter limit is DefaultMiterLimit.
+	SetMiterLimit(float64)
+
 	// SetColor sets the current drawing color.
 	// Note that fill color and stroke color are
 	// the same so if you want different fill
@@ -74,11 +96,108 @@ type Canvas interface {
 	// location using the given font.
 	FillString(f Font, x, y Length, text string)
 
+	// DrawImage draws the image scaled to fill the
+	// rectangle with its lower left corner at x, y
+	// and the given width and height.  Pixels are
+	// resampled using the given interpolation.
+	DrawImage(x, y, w, h Length, img image.Image, interp Interpolation)
+
 	// DPI returns the number of canvas dots in
 	// an inch.
 	DPI() float64
 }
 
+// Interpolation specifies how the pixels of an
+// image are resampled when it is drawn at a size
+// other than its own.
+type Interpolation int
+
+const (
+	// NearestNeighbor draws each pixel of the image
+	// as a rectangle of its color.
+	NearestNeighbor Interpolation = iota
+
+	// Bilinear blends the colors of neighboring
+	// pixels, giving smooth transitions.
+	Bilinear
+)
+
+// String returns the name of the interpolation.
+func (i Interpolation) String() string {
+	switch i {
+	case NearestNeighbor:
+		return "NearestNeighbor"
+	case Bilinear:
+		return "Bilinear"
+	}
+	return "Interpolation(invalid)"
+}
+
+// LineCap is the shape drawn at the ends
+// of stroked lines and dashes.
+type LineCap int
+
+const (
+	// ButtCap ends lines squarely at
+	// their end points.
+	ButtCap LineCap = iota
+
+	// RoundCap ends lines with a semicircle
+	// centered on their end points.
+	RoundCap
+
+	// SquareCap ends lines squarely, half
+	// the line width beyond their end points.
+	SquareCap
+)
+
+// String returns the name of the line cap.
+func (c LineCap) String() string {
+	switch c {
+	case ButtCap:
+		return "ButtCap"
+	case RoundCap:
+		return "RoundCap"
+	case SquareCap:
+		return "SquareCap"
+	}
+	return "LineCap(invalid)"
+}
+
+// LineJoin is the shape drawn at the corners
+// where the segments of stroked paths meet.
+type LineJoin int
+
+const (
+	// MiterJoin extends the outer edges of
+	// the segments until they meet.
+	MiterJoin LineJoin = iota
+
+	// RoundJoin rounds the corners with an
+	// arc centered on the corner point.
+	RoundJoin
+
+	// BevelJoin cuts the corners off with
+	// a straight edge.
+	BevelJoin
+)
+
+// String returns the name of the line join.
+func (j LineJoin) String() string {
+	switch j {
+	case MiterJoin:
+		return "MiterJoin"
+	case RoundJoin:
+		return "RoundJoin"
+	case BevelJoin:
+		return "BevelJoin"
+	}
+	return "LineJoin(invalid)"
+}
+
+// DefaultMiterLimit is the initial miter limit of a Canvas.
+const DefaultMiterLimit = 10
+
 // CanvasSizer is a Canvas with a defined size.
 type CanvasSizer interface {
 	Canvas
@@ -90,6 +209,9 @@ type CanvasSizer interface {
 func Initialize(c Canvas) {
 	c.SetLineWidth(Points(1))
 	c.SetLineDash([]Length{}, 0)
+	c.SetLineCap(ButtCap)
+	c.SetLineJoin(MiterJoin)
+	c.SetMiterLimit(DefaultMiterLimit)
 	c.SetColor(color.Black)
 }
 
@@ -97,3 +97,155 @@ func lines(w vg.Length) (*plot.Plot, error) {
 
 	return p, nil
 }
+
+func TestFontData(t *testing.T) {
+	fnt, err := vg.MakeFont("Courier", 12)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	data := fnt.Data()
+	if len(data) == 0 {
+		t.Fatal("missing font data")
+	}
+
+	// Fonts added without data have none.
+	vg.AddFont("CourierData", fnt.Font())
+	fnt, err = vg.MakeFont("CourierData", 12)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	if fnt.Data() != nil {
+		t.Error("unexpected data for added font")
+	}
+}
+
+func TestAddFontData(t *testing.T) {
+	fnt, err := vg.MakeFont("Helvetica", 12)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	err = vg.AddFontData("InMemory", fnt.Data())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = vg.ReadFont("InMemoryReader", bytes.NewReader(fnt.Data()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"InMemory", "InMemoryReader"} {
+		got, err := vg.MakeFont(name, 12)
+		if err != nil {
+			t.Fatalf("failed to make font %q: %v", name, err)
+		}
+		if !bytes.Equal(got.Data(), fnt.Data()) {
+			t.Errorf("unexpected data for font %q", name)
+		}
+		if got.Width("Hello") != fnt.Width("Hello") {
+			t.Errorf("unexpected width for font %q: got:%v want:%v", name, got.Width("Hello"), fnt.Width("Hello"))
+		}
+	}
+
+	if err := vg.AddFontData("Invalid", []byte("not a font")); err == nil {
+		t.Error("expected error for invalid font data")
+	}
+}
+
+func TestFontFamily(t *testing.T) {
+	fnt, err := vg.MakeFamilyFont("Times", vg.BoldItalic, 12)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	if fnt.Name() != "Times-BoldItalic" || fnt.Family() != "Times" || fnt.Style() != vg.BoldItalic {
+		t.Errorf("unexpected font: got:%s %s %v", fnt.Name(), fnt.Family(), fnt.Style())
+	}
+	if err := fnt.SetStyle(vg.Regular); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fnt.Name() != "Times-Roman" || fnt.Size != 12 {
+		t.Errorf("unexpected font after setting style: got:%s %v", fnt.Name(), fnt.Size)
+	}
+
+	err = vg.AddFontData("Family-Regular", fnt.Data())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = vg.AddFontFamily("Family", vg.Regular, "Family-Regular")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fnt, err = vg.MakeFamilyFont("Family", vg.Regular, 10)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	if err := fnt.SetStyle(vg.Bold); err == nil {
+		t.Error("expected error for missing style")
+	}
+	if fnt.Name() != "Family-Regular" {
+		t.Errorf("font changed after failed style change: got:%s", fnt.Name())
+	}
+	if _, err := vg.MakeFamilyFont("NoFamily", vg.Regular, 10); err == nil {
+		t.Error("expected error for unknown family")
+	}
+}
+
+func TestFallback(t *testing.T) {
+	helv, times := mustFont(t, "Helvetica"), mustFont(t, "Times-Roman")
+	err := vg.AddFontData("Primary", helv.Data())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = vg.SetFallbacks("Primary", "Courier", "Times-Roman")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := vg.SetFallbacks("Primary", "NoFont"); err == nil {
+		t.Error("expected error for unknown fallback")
+	}
+	if got := vg.Fallbacks("Primary"); len(got) != 2 {
+		t.Errorf("fallbacks changed after failed update: got:%v", got)
+	}
+
+	fnt, err := vg.MakeFont("Primary", 12)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	runs := fnt.Runs("aΩΩb")
+	want := []struct {
+		font, text string
+		offset     vg.Length
+	}{
+		{"Primary", "a", 0},
+		{"Times-Roman", "ΩΩ", helv.Width("a")},
+		{"Primary", "b", helv.Width("a") + times.Width("ΩΩ")},
+	}
+	if len(runs) != len(want) {
+		t.Fatalf("unexpected number of runs: got:%d want:%d", len(runs), len(want))
+	}
+	for i, r := range runs {
+		if r.Font.Name() != want[i].font || r.Text != want[i].text || r.Offset != want[i].offset || r.Font.Size != 12 {
+			t.Errorf("unexpected run %d: got:%s %q %v want:%s %q %v",
+				i, r.Font.Name(), r.Text, r.Offset, want[i].font, want[i].text, want[i].offset)
+		}
+	}
+	if got, want := fnt.Width("aΩΩb"), helv.Width("a")+times.Width("ΩΩ")+helv.Width("b"); got != want {
+		t.Errorf("unexpected width: got:%v want:%v", got, want)
+	}
+
+	if err := vg.SetFallbacks("Primary"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs := fnt.Runs("aΩb"); len(runs) != 1 {
+		t.Errorf("unexpected number of runs without fallbacks: got:%d want:1", len(runs))
+	}
+	if n := testing.AllocsPerRun(10, func() { fnt.Width("aΩb") }); n != 0 {
+		t.Errorf("unexpected allocations measuring without fallbacks: got:%v want:0", n)
+	}
+}
+
+func mustFont(t *testing.T, name string) vg.Font {
+	fnt, err := vg.MakeFont(name, 12)
+	if err != nil {
+		t.Fatalf("failed to make font %q: %v", name, err)
+	}
+	return fnt
+}
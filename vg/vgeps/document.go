@@ -0,0 +1,162 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgeps
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+)
+
+// Document is a PostScript document conforming to the
+// Document Structuring Conventions with pages of
+// independent sizes. Fonts used on several pages are
+// defined once in the document prolog. The zero value
+// is an empty document.
+//
+// The document information and bookmarks are written
+// using pdfmark operators, which are used when the
+// document is converted to PDF and are ignored by
+// printers.
+type Document struct {
+	// Title and Author are recorded in the document
+	// if they are not empty. Characters that are not
+	// printable ASCII are replaced by '?'.
+	Title, Author string
+
+	// Created is the creation date recorded in
+	// the document. No creation date is recorded
+	// if Created is the zero time.
+	Created time.Time
+
+	fonts *fontSet
+	pages []page
+}
+
+// page is a page of a document.
+type page struct {
+	canvas *Canvas
+
+	// bookmark is the title of the outline
+	// entry for the page, if not empty.
+	bookmark string
+}
+
+// AddPage adds a page with the given size to the end of
+// the document and returns a canvas for drawing on it.
+// If bookmark is not empty, an outline entry with the
+// bookmark as its title is added for the page.
+func (d *Document) AddPage(w, h vg.Length, bookmark string) draw.Canvas {
+	if d.fonts == nil {
+		d.fonts = newFontSet()
+	}
+	c := newPage(w, h, d.fonts)
+	d.pages = append(d.pages, page{canvas: c, bookmark: bookmark})
+	return draw.New(c)
+}
+
+// WriteTo writes the document to an io.Writer.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		return 0, fmt.Errorf("vgeps: document has no pages")
+	}
+	var buf bytes.Buffer
+	buf.WriteString("%!PS-Adobe-3.0\n")
+	buf.WriteString("%%Creator: github.com/gonum/plot/vg/vgeps\n")
+	if d.Title != "" {
+		fmt.Fprintf(&buf, "%%%%Title: %s\n", dscText(d.Title))
+	}
+	if d.Author != "" {
+		fmt.Fprintf(&buf, "%%%%For: %s\n", dscText(d.Author))
+	}
+	if !d.Created.IsZero() {
+		fmt.Fprintf(&buf, "%%%%CreationDate: %s\n", dscDate(d.Created))
+	}
+	var bw, bh vg.Length
+	for _, p := range d.pages {
+		if p.canvas.w > bw {
+			bw = p.canvas.w
+		}
+		if p.canvas.h > bh {
+			bh = p.canvas.h
+		}
+	}
+	fmt.Fprintf(&buf, "%%%%BoundingBox: 0 0 %.*g %.*g\n", pr, bw.Points(), pr, bh.Points())
+	fmt.Fprintf(&buf, "%%%%Pages: %d\n", len(d.pages))
+	buf.WriteString("%%EndComments\n")
+
+	buf.WriteString("%%BeginProlog\n")
+	buf.WriteString("/pdfmark where {pop} {userdict /pdfmark /cleartomark load put} ifelse\n")
+	d.fonts.writeResources(&buf)
+	buf.WriteString("%%EndProlog\n")
+
+	if d.Title != "" || d.Author != "" {
+		buf.WriteString("%%BeginSetup\n[")
+		if d.Title != "" {
+			fmt.Fprintf(&buf, " /Title %s", psString(d.Title))
+		}
+		if d.Author != "" {
+			fmt.Fprintf(&buf, " /Author %s", psString(d.Author))
+		}
+		buf.WriteString(" /DOCINFO pdfmark\n%%EndSetup\n")
+	}
+
+	for i, p := range d.pages {
+		w, h := p.canvas.w.Points(), p.canvas.h.Points()
+		fmt.Fprintf(&buf, "%%%%Page: %d %d\n", i+1, i+1)
+		fmt.Fprintf(&buf, "%%%%PageBoundingBox: 0 0 %.*g %.*g\n", pr, w, pr, h)
+		buf.WriteString("%%BeginPageSetup\n")
+		fmt.Fprintf(&buf, "<< /PageSize [%.*g %.*g] >> setpagedevice\n", pr, w, pr, h)
+		if p.bookmark != "" {
+			fmt.Fprintf(&buf, "[ /Title %s /OUT pdfmark\n", psString(p.bookmark))
+		}
+		buf.WriteString("%%EndPageSetup\n")
+		buf.Write(p.canvas.buf.Bytes())
+		buf.WriteString("showpage\n")
+	}
+	buf.WriteString("%%Trailer\n%%EOF\n")
+
+	b := bufio.NewWriter(w)
+	n, err := buf.WriteTo(b)
+	if err != nil {
+		return n, err
+	}
+	return n, b.Flush()
+}
+
+// dscDate returns t formatted for the CreationDate
+// comment in the date format of PDF, D:YYYYMMDDHHmmSS
+// followed by the offset from UTC.
+func dscDate(t time.Time) string {
+	s := "D:" + t.Format("20060102150405")
+	_, off := t.Zone()
+	if off == 0 {
+		return s + "Z"
+	}
+	sign := '+'
+	if off < 0 {
+		sign = '-'
+		off = -off
+	}
+	return fmt.Sprintf("%s%c%02d'%02d'", s, sign, off/3600, off%3600/60)
+}
+
+// dscText returns s with the characters that are
+// not printable ASCII replaced by '?', for use in
+// document structuring comments.
+func dscText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < ' ' || r > '~' {
+			return '?'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,304 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgeps
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"code.google.com/p/freetype-go/freetype/truetype"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/internal/ttf"
+)
+
+// psFont is a font used by a Canvas. Fonts with TrueType data
+// are embedded in the document as Type 42 fonts and their
+// glyphs are shown by name, so that any character in the font
+// can be shown. Only the glyphs that have been shown are
+// embedded. Other fonts are expected to be available to the
+// PostScript interpreter.
+type psFont struct {
+	name string
+	font *truetype.Font
+
+	// data is the TrueType data of the font.
+	// It is nil if the font is not embedded.
+	data []byte
+
+	// glyphs is the set of glyphs that
+	// have been shown.
+	glyphs map[truetype.Index]bool
+}
+
+// fontSet is the set of fonts used by the
+// canvases of a document.
+type fontSet struct {
+	byName map[string]*psFont
+	order  []*psFont
+}
+
+func newFontSet() *fontSet {
+	return &fontSet{byName: make(map[string]*psFont)}
+}
+
+// get returns the font in the set for fnt,
+// adding the font to the set if necessary.
+func (s *fontSet) get(fnt vg.Font) *psFont {
+	f, ok := s.byName[fnt.Name()]
+	if !ok {
+		f = newFont(fnt)
+		s.byName[fnt.Name()] = f
+		s.order = append(s.order, f)
+	}
+	return f
+}
+
+// writeResources writes the resource definitions
+// of the embedded fonts in the set to buf.
+func (s *fontSet) writeResources(buf *bytes.Buffer) {
+	for _, f := range s.order {
+		if f.data != nil {
+			f.writeResource(buf)
+		}
+	}
+}
+
+func newFont(fnt vg.Font) *psFont {
+	f := &psFont{
+		name:   fnt.Name(),
+		font:   fnt.Font(),
+		glyphs: make(map[truetype.Index]bool),
+	}
+	if data := fnt.Data(); data != nil {
+		// Fonts that cannot be split are
+		// referred to by name instead.
+		if _, err := sfnts(data); err == nil {
+			f.data = data
+		}
+	}
+	return f
+}
+
+// show returns the PostScript operations that
+// show str at the current point with the given
+// font size.
+func (f *psFont) show(str string, size vg.Length) string {
+	if f.data == nil {
+		return psString(str) + " show"
+	}
+
+	var buf bytes.Buffer
+	upem := f.font.FUnitsPerEm()
+	var prev truetype.Index
+	for i, r := range str {
+		idx := f.font.Index(r)
+		if i > 0 {
+			if k := f.font.Kerning(upem, prev, idx); k != 0 {
+				fmt.Fprintf(&buf, "%.*g 0 rmoveto ", pr, float64(k)/float64(upem)*size.Points())
+			}
+		}
+		prev = idx
+		f.glyphs[idx] = true
+		fmt.Fprintf(&buf, "/%s glyphshow ", glyphName(idx))
+	}
+	return buf.String()
+}
+
+// glyphName returns the name of the glyph with index idx
+// in the CharStrings dictionary of an embedded font.
+func glyphName(idx truetype.Index) string {
+	if idx == 0 {
+		return ".notdef"
+	}
+	return fmt.Sprintf("g%d", idx)
+}
+
+// writeResource writes the Type 42 font resource definition
+// of an embedded font to buf.
+func (f *psFont) writeResource(buf *bytes.Buffer) {
+	upem := float64(f.font.FUnitsPerEm())
+	b := f.font.Bounds(f.font.FUnitsPerEm())
+	idx := make([]int, 0, len(f.glyphs))
+	glyphs := make([]truetype.Index, 0, len(f.glyphs))
+	for i := range f.glyphs {
+		if i != 0 {
+			idx = append(idx, int(i))
+		}
+		glyphs = append(glyphs, i)
+	}
+	sort.Ints(idx)
+
+	// The whole font is embedded if its data cannot
+	// be subset. The data of the whole font is known
+	// to split, since it was checked by newFont.
+	data, err := ttf.Subset(f.data, glyphs, ttf.OutlineTables)
+	if err != nil {
+		data = f.data
+	}
+	strs, err := sfnts(data)
+	if err != nil {
+		strs, _ = sfnts(f.data)
+	}
+
+	fmt.Fprintf(buf, "%%%%BeginResource: font %s\n", f.name)
+	buf.WriteString("10 dict begin\n")
+	buf.WriteString("/FontType 42 def\n")
+	fmt.Fprintf(buf, "/FontName /%s def\n", f.name)
+	buf.WriteString("/PaintType 0 def\n")
+	buf.WriteString("/FontMatrix [1 0 0 1 0 0] def\n")
+	fmt.Fprintf(buf, "/FontBBox [%.*g %.*g %.*g %.*g] def\n",
+		pr, float64(b.XMin)/upem, pr, float64(b.YMin)/upem,
+		pr, float64(b.XMax)/upem, pr, float64(b.YMax)/upem)
+	buf.WriteString("/Encoding 256 array 0 1 255 {1 index exch /.notdef put} for def\n")
+	fmt.Fprintf(buf, "/CharStrings %d dict dup begin\n", len(idx)+1)
+	buf.WriteString("/.notdef 0 def\n")
+	for _, i := range idx {
+		fmt.Fprintf(buf, "/%s %d def\n", glyphName(truetype.Index(i)), i)
+	}
+	buf.WriteString("end readonly def\n")
+	buf.WriteString("/sfnts [\n")
+	for _, s := range strs {
+		buf.WriteString("<")
+		for i := 0; i < len(s); i += 32 {
+			end := i + 32
+			if end > len(s) {
+				end = len(s)
+			}
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+			fmt.Fprintf(buf, "%X", s[i:end])
+		}
+		// PostScript interpreters ignore the
+		// final byte of each string.
+		buf.WriteString("00>\n")
+	}
+	buf.WriteString("] def\n")
+	buf.WriteString("FontName currentdict end definefont pop\n")
+	buf.WriteString("%%EndResource\n")
+}
+
+// maxSfntsString is the maximum length of the TrueType
+// data in a string of the sfnts array, allowing for the
+// padding byte within the PostScript string length limit.
+const maxSfntsString = 65534
+
+// sfnts splits TrueType font data into strings for the
+// sfnts array of a Type 42 font. Strings are split only
+// at table boundaries or, within the glyf table, at glyph
+// boundaries.
+func sfnts(data []byte) ([][]byte, error) {
+	return splitSfnts(data, maxSfntsString)
+}
+
+func splitSfnts(data []byte, max int) ([][]byte, error) {
+	bounds, err := boundaries(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var strs [][]byte
+	start, last := 0, 0
+	for _, b := range bounds {
+		if b-start > max {
+			if last == start {
+				return nil, errors.New("vgeps: font table too large to embed")
+			}
+			strs = append(strs, data[start:last])
+			start = last
+			if b-start > max {
+				return nil, errors.New("vgeps: font table too large to embed")
+			}
+		}
+		last = b
+	}
+	if start < len(data) {
+		strs = append(strs, data[start:])
+	}
+	return strs, nil
+}
+
+// boundaries returns the sorted offsets in data of the
+// tables of a TrueType font and of the glyphs in its glyf
+// table, followed by the length of the data.
+func boundaries(data []byte) ([]int, error) {
+	if len(data) < 12 {
+		return nil, errors.New("vgeps: invalid TrueType data")
+	}
+	n := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+16*n {
+		return nil, errors.New("vgeps: invalid TrueType data")
+	}
+	tables := make(map[string][]byte)
+	offsets := make(map[string]int)
+	set := map[int]bool{len(data): true}
+	for i := 0; i < n; i++ {
+		rec := data[12+16*i:]
+		tag := string(rec[:4])
+		off := int(binary.BigEndian.Uint32(rec[8:]))
+		length := int(binary.BigEndian.Uint32(rec[12:]))
+		if off < 0 || length < 0 || off+length > len(data) {
+			return nil, fmt.Errorf("vgeps: invalid TrueType table %q", tag)
+		}
+		tables[tag] = data[off : off+length]
+		offsets[tag] = off
+		set[off] = true
+	}
+
+	head, maxp, loca := tables["head"], tables["maxp"], tables["loca"]
+	if _, ok := tables["glyf"]; !ok || len(head) < 54 || len(maxp) < 6 || loca == nil {
+		return nil, errors.New("vgeps: missing TrueType outline tables")
+	}
+	long := binary.BigEndian.Uint16(head[50:]) != 0
+	glyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+	glyf := offsets["glyf"]
+	for i := 0; i <= glyphs; i++ {
+		var off int
+		switch {
+		case long && 4*i+4 <= len(loca):
+			off = int(binary.BigEndian.Uint32(loca[4*i:]))
+		case !long && 2*i+2 <= len(loca):
+			off = 2 * int(binary.BigEndian.Uint16(loca[2*i:]))
+		default:
+			return nil, errors.New("vgeps: invalid TrueType loca table")
+		}
+		if off > len(tables["glyf"]) {
+			return nil, errors.New("vgeps: invalid TrueType loca table")
+		}
+		set[glyf+off] = true
+	}
+
+	bounds := make([]int, 0, len(set))
+	for b := range set {
+		if b > 0 {
+			bounds = append(bounds, b)
+		}
+	}
+	sort.Ints(bounds)
+	return bounds, nil
+}
+
+// psString returns str as a PostScript string. Characters
+// outside the ASCII range are replaced with '?'.
+func psString(str string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('(')
+	for _, r := range str {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case r < ' ' || r > '~':
+			buf.WriteByte('?')
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
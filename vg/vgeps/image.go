@@ -0,0 +1,53 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgeps
+
+import (
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/gonum/plot/vg"
+)
+
+// hexLine is the number of image bytes written
+// on each line of hexadecimal image data.
+const hexLine = 36
+
+// DrawImage implements the vg.Canvas.DrawImage method.
+// PostScript images have no alpha channel, so images
+// that are not opaque are composited over white.
+func (e *Canvas) DrawImage(x, y, w, h vg.Length, img image.Image, interp vg.Interpolation) {
+	b := img.Bounds()
+	if b.Empty() {
+		return
+	}
+	fmt.Fprintf(e.buf, "gsave\n%.*g %.*g translate\n%.*g %.*g scale\n/DeviceRGB setcolorspace\n",
+		pr, x.Dots(e), pr, y.Dots(e), pr, w.Dots(e), pr, h.Dots(e))
+	fmt.Fprintf(e.buf, "<< /ImageType 1 /Width %d /Height %d /BitsPerComponent 8\n"+
+		"/Decode [0 1 0 1 0 1] /ImageMatrix [%d 0 0 %d 0 %d] /Interpolate %t\n"+
+		"/DataSource currentfile /ASCIIHexDecode filter >> image\n",
+		b.Dx(), b.Dy(), b.Dx(), -b.Dy(), b.Dy(), interp == vg.Bilinear)
+
+	line := make([]byte, 0, hexLine)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.RGBA64Model.Convert(img.At(x, y)).(color.RGBA64)
+			line = append(line, overWhite(c.R, c.A), overWhite(c.G, c.A), overWhite(c.B, c.A))
+			if len(line) == hexLine {
+				e.buf.WriteString(hex.EncodeToString(line) + "\n")
+				line = line[:0]
+			}
+		}
+	}
+	e.buf.WriteString(hex.EncodeToString(line) + ">\ngrestore\n")
+}
+
+// overWhite returns the 8 bit value of a premultiplied
+// 16 bit color component composited over white.
+func overWhite(v, a uint16) uint8 {
+	return uint8((uint32(v) + 0xffff - uint32(a)) >> 8)
+}
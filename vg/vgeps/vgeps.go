@@ -22,6 +22,12 @@ type Canvas struct {
 	stk  []ctx
 	w, h vg.Length
 	buf  *bytes.Buffer
+
+	// hdr is the length of the document
+	// header at the start of buf.
+	hdr int
+
+	fonts *fontSet
 }
 
 type ctx struct {
@@ -29,10 +35,17 @@ type ctx struct {
 	width  vg.Length
 	dashes []vg.Length
 	offs   vg.Length
+	cap    vg.LineCap
+	join   vg.LineJoin
+	miter  float64
 	font   string
 	fsize  vg.Length
 }
 
+// initialCtx is the context of the initial
+// PostScript graphics state.
+var initialCtx = ctx{miter: 10}
+
 // pr is the amount of precision to use when outputting float64s.
 const pr = 5
 
@@ -44,10 +57,12 @@ func New(w, h vg.Length) *Canvas {
 // NewTitle returns a new Canvas with the given title string.
 func NewTitle(w, h vg.Length, title string) *Canvas {
 	c := &Canvas{
-		stk: []ctx{ctx{}},
+		stk: []ctx{initialCtx},
 		w:   w,
 		h:   h,
 		buf: new(bytes.Buffer),
+
+		fonts: newFontSet(),
 	}
 	c.buf.WriteString("%%!PS-Adobe-3.0 EPSF-3.0\n")
 	c.buf.WriteString("%%Creator github.com/gonum/plot/vg/vgeps\n")
@@ -58,11 +73,27 @@ func NewTitle(w, h vg.Length, title string) *Canvas {
 	c.buf.WriteString(fmt.Sprintf("%%%%CreationDate: %s\n", time.Now()))
 	c.buf.WriteString("%%Orientation: Portrait\n")
 	c.buf.WriteString("%%EndComments\n")
+	c.hdr = c.buf.Len()
 	c.buf.WriteString("\n")
 	vg.Initialize(c)
 	return c
 }
 
+// newPage returns a new Canvas for a page of a
+// document, adding the fonts it uses to the given
+// set.
+func newPage(w, h vg.Length, fonts *fontSet) *Canvas {
+	c := &Canvas{
+		stk:   []ctx{initialCtx},
+		w:     w,
+		h:     h,
+		buf:   new(bytes.Buffer),
+		fonts: fonts,
+	}
+	vg.Initialize(c)
+	return c
+}
+
 func (c *Canvas) Size() (w, h vg.Length) {
 	return c.w, c.h
 }
@@ -99,6 +130,33 @@ func (e *Canvas) SetLineDash(dashes []vg.Length, o vg.Length) {
 	}
 }
 
+func (e *Canvas) SetLineCap(cap vg.LineCap) {
+	if cap < vg.ButtCap || cap > vg.SquareCap {
+		cap = vg.ButtCap
+	}
+	if e.cur().cap != cap {
+		e.cur().cap = cap
+		fmt.Fprintf(e.buf, "%d setlinecap\n", cap)
+	}
+}
+
+func (e *Canvas) SetLineJoin(join vg.LineJoin) {
+	if join < vg.MiterJoin || join > vg.BevelJoin {
+		join = vg.MiterJoin
+	}
+	if e.cur().join != join {
+		e.cur().join = join
+		fmt.Fprintf(e.buf, "%d setlinejoin\n", join)
+	}
+}
+
+func (e *Canvas) SetMiterLimit(limit float64) {
+	if e.cur().miter != limit {
+		e.cur().miter = limit
+		fmt.Fprintf(e.buf, "%.*g setmiterlimit\n", pr, limit)
+	}
+}
+
 func (e *Canvas) SetColor(c color.Color) {
 	if c == nil {
 		c = color.Black
@@ -174,14 +232,17 @@ func (e *Canvas) trace(path vg.Path) {
 }
 
 func (e *Canvas) FillString(fnt vg.Font, x, y vg.Length, str string) {
-	if e.cur().font != fnt.Name() || e.cur().fsize != fnt.Size {
-		e.cur().font = fnt.Name()
-		e.cur().fsize = fnt.Size
-		fmt.Fprintf(e.buf, "/%s findfont %.*g scalefont setfont\n",
-			fnt.Name(), pr, fnt.Size)
+	for _, run := range fnt.Runs(str) {
+		f := e.fonts.get(run.Font)
+		if e.cur().font != f.name || e.cur().fsize != fnt.Size {
+			e.cur().font = f.name
+			e.cur().fsize = fnt.Size
+			fmt.Fprintf(e.buf, "/%s findfont %.*g scalefont setfont\n",
+				f.name, pr, fnt.Size)
+		}
+		fmt.Fprintf(e.buf, "%.*g %.*g moveto\n", pr, (x + run.Offset).Dots(e), pr, y.Dots(e))
+		fmt.Fprintf(e.buf, "%s\n", f.show(run.Text, fnt.Size))
 	}
-	fmt.Fprintf(e.buf, "%.*g %.*g moveto\n", pr, x.Dots(e), pr, y.Dots(e))
-	fmt.Fprintf(e.buf, "(%s) show\n", str)
 }
 
 func (e *Canvas) DPI() float64 {
@@ -189,16 +250,34 @@ func (e *Canvas) DPI() float64 {
 }
 
 // WriteTo writes the canvas to an io.Writer.
+// Fonts with TrueType data available are embedded
+// in the document prolog.
 func (e *Canvas) WriteTo(w io.Writer) (int64, error) {
+	var prolog bytes.Buffer
+	e.fonts.writeResources(&prolog)
+
 	b := bufio.NewWriter(w)
-	n, err := e.buf.WriteTo(b)
-	if err != nil {
-		return n, err
-	}
-	m, err := fmt.Fprintln(b, "showpage")
-	n += int64(m)
-	if err != nil {
-		return n, err
+	var n int64
+	for _, p := range [][]byte{
+		e.buf.Bytes()[:e.hdr],
+		wrapProlog(prolog.Bytes()),
+		e.buf.Bytes()[e.hdr:],
+		[]byte("showpage\n"),
+	} {
+		m, err := b.Write(p)
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
 	}
 	return n, b.Flush()
 }
+
+// wrapProlog returns the document prolog
+// containing the given resources.
+func wrapProlog(res []byte) []byte {
+	if len(res) == 0 {
+		return nil
+	}
+	return []byte("%%BeginProlog\n" + string(res) + "%%EndProlog\n")
+}
@@ -0,0 +1,225 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgeps
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gonum/plot/vg"
+)
+
+func TestSplitSfnts(t *testing.T) {
+	fnt, err := vg.MakeFont("Helvetica", 12)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	data := fnt.Data()
+	bounds, err := boundaries(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Splitting must succeed with the
+	// largest gap between boundaries.
+	var gap, prev int
+	for _, b := range bounds {
+		if b-prev > gap {
+			gap = b - prev
+		}
+		prev = b
+	}
+
+	for _, max := range []int{maxSfntsString, gap} {
+		strs, err := splitSfnts(data, max)
+		if err != nil {
+			t.Fatalf("unexpected error for max %d: %v", max, err)
+		}
+		if max < len(data) && len(strs) < 2 {
+			t.Errorf("expected data to be split for max %d", max)
+		}
+		var off int
+		for i, s := range strs {
+			if len(s) > max {
+				t.Errorf("string %d too long for max %d: %d", i, max, len(s))
+			}
+			off += len(s)
+			if j := sort.SearchInts(bounds, off); j == len(bounds) || bounds[j] != off {
+				t.Errorf("string %d for max %d does not end on a boundary", i, max)
+			}
+		}
+		if !bytes.Equal(bytes.Join(strs, nil), data) {
+			t.Errorf("split data does not match font data for max %d", max)
+		}
+	}
+
+	if _, err := splitSfnts(data, gap-1); err == nil {
+		t.Error("expected error for tables longer than maximum")
+	}
+	if _, err := sfnts(data[:100]); err == nil {
+		t.Error("expected error for truncated font data")
+	}
+}
+
+func TestEmbedFont(t *testing.T) {
+	fnt, err := vg.MakeFont("Helvetica", 12)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	c := New(vg.Inch, vg.Inch)
+	c.FillString(fnt, 0, 0, "AV")
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	eps := buf.String()
+
+	a, v := fnt.Font().Index('A'), fnt.Font().Index('V')
+	for _, want := range []string{
+		"%%EndComments\n%%BeginProlog\n%%BeginResource: font Helvetica\n",
+		"/FontType 42 def\n",
+		"/CharStrings 3 dict dup begin\n",
+		fmt.Sprintf("/g%d %d def\n", a, a),
+		fmt.Sprintf("/g%d %d def\n", v, v),
+		"%%EndResource\n%%EndProlog\n",
+		"/Helvetica findfont 12 scalefont setfont\n",
+		fmt.Sprintf("/g%d glyphshow ", a),
+	} {
+		if !strings.Contains(eps, want) {
+			t.Errorf("missing %q in document", want)
+		}
+	}
+	if !strings.HasSuffix(eps, fmt.Sprintf("glyphshow /g%d glyphshow \nshowpage\n", v)) {
+		t.Errorf("unexpected end of document: %q", eps[len(eps)-80:])
+	}
+
+	// The sfnts strings hold the hex encoded font data
+	// with a padding byte, so the embedded font is about
+	// twice the size of the subset font data.
+	i, j := strings.Index(eps, "/sfnts ["), strings.Index(eps, "] def\nFontName")
+	if i < 0 || j < i {
+		t.Fatal("missing sfnts array in document")
+	}
+	if n := j - i; n >= len(fnt.Data()) {
+		t.Errorf("embedded font not subset: got length %d for font data of length %d", n, len(fnt.Data()))
+	}
+}
+
+func TestPSString(t *testing.T) {
+	if got, want := psString(`a(b)\é`), `(a\(b\)\\?)`; got != want {
+		t.Errorf("unexpected string: got:%q want:%q", got, want)
+	}
+}
+
+func TestDrawImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.NRGBA{R: 0xff, A: 0xff})
+	img.Set(1, 0, color.NRGBA{A: 0})
+	c := New(vg.Inch, vg.Inch)
+	c.DrawImage(10, 20, 30, 40, img, vg.NearestNeighbor)
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	eps := buf.String()
+
+	want := "gsave\n10 20 translate\n30 40 scale\n/DeviceRGB setcolorspace\n" +
+		"<< /ImageType 1 /Width 2 /Height 1 /BitsPerComponent 8\n" +
+		"/Decode [0 1 0 1 0 1] /ImageMatrix [2 0 0 -1 0 1] /Interpolate false\n" +
+		"/DataSource currentfile /ASCIIHexDecode filter >> image\n" +
+		"ff0000ffffff>\ngrestore\n"
+	if !strings.Contains(eps, want) {
+		t.Errorf("missing image in document:\n%s", eps)
+	}
+}
+
+func TestDocument(t *testing.T) {
+	fnt, err := vg.MakeFont("Helvetica", 12)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	d := Document{
+		Title:   "Report",
+		Author:  "Gonum Ünited",
+		Created: time.Date(2015, 6, 7, 8, 9, 10, 0, time.UTC),
+	}
+	c1 := d.AddPage(3*vg.Inch, 2*vg.Inch, "First")
+	c1.FillString(fnt, 10, 10, "a")
+	c2 := d.AddPage(4*vg.Inch, 5*vg.Inch, "")
+	c2.FillString(fnt, 10, 10, "b")
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ps := buf.String()
+
+	for _, want := range []string{
+		"%!PS-Adobe-3.0\n%%Creator: github.com/gonum/plot/vg/vgeps\n%%Title: Report\n%%For: Gonum ?nited\n",
+		"%%CreationDate: D:20150607080910Z\n",
+		"%%BoundingBox: 0 0 288 360\n%%Pages: 2\n%%EndComments\n",
+		"%%BeginSetup\n[ /Title (Report) /Author (Gonum ?nited) /DOCINFO pdfmark\n%%EndSetup\n",
+		"%%Page: 1 1\n%%PageBoundingBox: 0 0 216 144\n%%BeginPageSetup\n<< /PageSize [216 144] >> setpagedevice\n" +
+			"[ /Title (First) /OUT pdfmark\n%%EndPageSetup\n",
+		"%%Page: 2 2\n%%PageBoundingBox: 0 0 288 360\n%%BeginPageSetup\n<< /PageSize [288 360] >> setpagedevice\n" +
+			"%%EndPageSetup\n",
+	} {
+		if !strings.Contains(ps, want) {
+			t.Errorf("missing %q in document", want)
+		}
+	}
+	if n := strings.Count(ps, "%%BeginResource: font Helvetica\n"); n != 1 {
+		t.Errorf("unexpected number of font resources: got:%d want:1", n)
+	}
+	if n := strings.Count(ps, "showpage\n"); n != 2 {
+		t.Errorf("unexpected number of pages shown: got:%d want:2", n)
+	}
+	if !strings.HasSuffix(ps, "showpage\n%%Trailer\n%%EOF\n") {
+		t.Errorf("unexpected end of document: %q", ps[len(ps)-40:])
+	}
+}
+
+func TestCreationDate(t *testing.T) {
+	d := Document{Created: time.Now()}
+	d.AddPage(vg.Inch, vg.Inch, "")
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	re := regexp.MustCompile(`(?m)^%%CreationDate: D:[0-9]{14}(Z|[+-][0-9]{2}'[0-9]{2}')$`)
+	if !re.Match(buf.Bytes()) {
+		t.Errorf("unexpected creation date in document:\n%s", buf.Bytes())
+	}
+
+	east := time.FixedZone("", 5*3600+30*60)
+	if got, want := dscDate(time.Date(2015, 6, 7, 8, 9, 10, 0, east)), "D:20150607080910+05'30'"; got != want {
+		t.Errorf("unexpected date: got:%s want:%s", got, want)
+	}
+}
+
+func TestLineStyle(t *testing.T) {
+	c := New(vg.Inch, vg.Inch)
+	n := c.buf.Len()
+	c.SetLineCap(vg.ButtCap)
+	c.SetLineJoin(vg.MiterJoin)
+	c.SetMiterLimit(vg.DefaultMiterLimit)
+	if got := c.buf.String()[n:]; got != "" {
+		t.Errorf("unexpected change of default line style:\n%s", got)
+	}
+	c.SetLineCap(vg.SquareCap)
+	c.SetLineJoin(vg.RoundJoin)
+	c.SetMiterLimit(2.5)
+	c.SetLineCap(vg.SquareCap)
+	want := "2 setlinecap\n1 setlinejoin\n2.5 setmiterlimit\n"
+	if got := c.buf.String(); !strings.HasSuffix(got, want) {
+		t.Errorf("unexpected line style operators:\ngot:\n%s\nwant suffix:\n%s", got, want)
+	}
+}
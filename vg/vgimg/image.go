@@ -0,0 +1,117 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgimg
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/gonum/plot/vg"
+)
+
+// DrawImage implements the vg.Canvas.DrawImage method.
+func (c *Canvas) DrawImage(x, y, w, h vg.Length, img image.Image, interp vg.Interpolation) {
+	b := img.Bounds()
+	if b.Empty() {
+		return
+	}
+
+	// The transform from image pixel coordinates, with
+	// the origin at the top left of the image, to the
+	// device coordinates of the canvas image.
+	sx := w.Dots(c) / float64(b.Dx())
+	sy := -h.Dots(c) / float64(b.Dy())
+	m := c.gc.GetMatrixTransform()
+	tr := [6]float64{
+		m[0] * sx, m[1] * sx,
+		m[2] * sy, m[3] * sy,
+		m[0]*x.Dots(c) + m[2]*(y+h).Dots(c) + m[4],
+		m[1]*x.Dots(c) + m[3]*(y+h).Dots(c) + m[5],
+	}
+	det := tr[0]*tr[3] - tr[1]*tr[2]
+	if det == 0 {
+		return
+	}
+
+	// Find the device pixels covered by the image.
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range [4][2]float64{{0, 0}, {float64(b.Dx()), 0}, {0, float64(b.Dy())}, {float64(b.Dx()), float64(b.Dy())}} {
+		dx := tr[0]*p[0] + tr[2]*p[1] + tr[4]
+		dy := tr[1]*p[0] + tr[3]*p[1] + tr[5]
+		minX, maxX = math.Min(minX, dx), math.Max(maxX, dx)
+		minY, maxY = math.Min(minY, dy), math.Max(maxY, dy)
+	}
+	r := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+	r = r.Intersect(c.img.Bounds())
+	if r.Empty() {
+		return
+	}
+
+	// Sample the image at the center of each device
+	// pixel using the inverse of the transform.
+	dst := image.NewRGBA(r)
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			dx := float64(px) + 0.5 - tr[4]
+			dy := float64(py) + 0.5 - tr[5]
+			u := (tr[3]*dx - tr[2]*dy) / det
+			v := (tr[0]*dy - tr[1]*dx) / det
+			if u < 0 || u >= float64(b.Dx()) || v < 0 || v >= float64(b.Dy()) {
+				continue
+			}
+			dst.Set(px, py, sample(img, u, v, interp))
+		}
+	}
+	draw.Draw(c.img, r, dst, r.Min, draw.Over)
+}
+
+// sample returns the color of img at the point (u, v)
+// relative to the top left corner of its bounds.
+func sample(img image.Image, u, v float64, interp vg.Interpolation) color.Color {
+	bnd := img.Bounds()
+	if interp != vg.Bilinear {
+		return img.At(bnd.Min.X+int(u), bnd.Min.Y+int(v))
+	}
+
+	// Bilinear interpolation between the centers of the
+	// four nearest pixels, clamped at the image edges.
+	u, v = u-0.5, v-0.5
+	x0, y0 := math.Floor(u), math.Floor(v)
+	fx, fy := u-x0, v-y0
+	clamp := func(i, n int) int {
+		switch {
+		case i < 0:
+			return 0
+		case i >= n:
+			return n - 1
+		}
+		return i
+	}
+	var sum [4]float64
+	for _, p := range [4]struct {
+		x, y int
+		w    float64
+	}{
+		{int(x0), int(y0), (1 - fx) * (1 - fy)},
+		{int(x0) + 1, int(y0), fx * (1 - fy)},
+		{int(x0), int(y0) + 1, (1 - fx) * fy},
+		{int(x0) + 1, int(y0) + 1, fx * fy},
+	} {
+		r, g, b, a := img.At(bnd.Min.X+clamp(p.x, bnd.Dx()), bnd.Min.Y+clamp(p.y, bnd.Dy())).RGBA()
+		sum[0] += p.w * float64(r)
+		sum[1] += p.w * float64(g)
+		sum[2] += p.w * float64(b)
+		sum[3] += p.w * float64(a)
+	}
+	return color.RGBA64{
+		R: uint16(sum[0] + 0.5),
+		G: uint16(sum[1] + 0.5),
+		B: uint16(sum[2] + 0.5),
+		A: uint16(sum[3] + 0.5),
+	}
+}
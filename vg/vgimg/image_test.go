@@ -0,0 +1,39 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgimg
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/gonum/plot/vg"
+)
+
+func TestSample(t *testing.T) {
+	img := image.NewGray16(image.Rect(5, 5, 7, 6))
+	img.SetGray16(5, 5, color.Gray16{Y: 0})
+	img.SetGray16(6, 5, color.Gray16{Y: 0xfffe})
+
+	for _, test := range []struct {
+		u, v   float64
+		interp vg.Interpolation
+		want   uint16
+	}{
+		{u: 0.9, v: 0.5, interp: vg.NearestNeighbor, want: 0},
+		{u: 1.1, v: 0.5, interp: vg.NearestNeighbor, want: 0xfffe},
+		{u: 0.5, v: 0.5, interp: vg.Bilinear, want: 0},
+		{u: 1, v: 0.5, interp: vg.Bilinear, want: 0x7fff},
+		{u: 1.25, v: 0.2, interp: vg.Bilinear, want: 0xbffe},
+		{u: 0.1, v: 0.9, interp: vg.Bilinear, want: 0},
+		{u: 1.9, v: 0.1, interp: vg.Bilinear, want: 0xfffe},
+	} {
+		r, _, _, _ := sample(img, test.u, test.v, test.interp).RGBA()
+		if uint16(r) != test.want {
+			t.Errorf("unexpected sample at (%v, %v) with %v: got:%#x want:%#x",
+				test.u, test.v, test.interp, r, test.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgimg
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+// MedianCut is an image/draw.Quantizer that builds a palette
+// using the median cut algorithm. The colors of the image are
+// repeatedly divided at the pixel count median of the color
+// channel with the largest range until the palette is full.
+// Each palette entry is the pixel weighted mean of its colors.
+type MedianCut struct{}
+
+// Quantize appends up to cap(p)-len(p) colors to p and returns
+// the updated palette suitable for converting m to a paletted
+// image. If m has no more distinct colors than there is room
+// for in p, those colors are appended exactly.
+func (MedianCut) Quantize(p color.Palette, m image.Image) color.Palette {
+	n := cap(p) - len(p)
+	if n <= 0 {
+		return p
+	}
+
+	hist := make(map[color.RGBA]int)
+	b := m.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			hist[color.RGBAModel.Convert(m.At(x, y)).(color.RGBA)]++
+		}
+	}
+	if len(hist) == 0 {
+		return p
+	}
+	entries := make([]colorCount, 0, len(hist))
+	for c, cnt := range hist {
+		entries = append(entries, colorCount{color: c, n: cnt})
+	}
+	sort.Sort(byColor{entries, -1})
+
+	if len(entries) <= n {
+		for _, e := range entries {
+			p = append(p, e.color)
+		}
+		return p
+	}
+
+	boxes := []colorBox{newColorBox(entries)}
+	for len(boxes) < n {
+		// Split the box with the widest channel range.
+		i := -1
+		for j, bx := range boxes {
+			if len(bx.entries) > 1 && (i < 0 || bx.span > boxes[i].span) {
+				i = j
+			}
+		}
+		if i < 0 {
+			break
+		}
+		lo, hi := boxes[i].split()
+		boxes[i] = lo
+		boxes = append(boxes, hi)
+	}
+	for _, bx := range boxes {
+		p = append(p, bx.mean())
+	}
+	return p
+}
+
+// colorCount is a color and the number of pixels with that color.
+type colorCount struct {
+	color color.RGBA
+	n     int
+}
+
+// channel returns the value of channel i of c, in the order R, G, B, A.
+func channel(c color.RGBA, i int) uint8 {
+	switch i {
+	case 0:
+		return c.R
+	case 1:
+		return c.G
+	case 2:
+		return c.B
+	default:
+		return c.A
+	}
+}
+
+// byColor sorts colors by a channel, breaking ties by the
+// remaining channels. A negative channel sorts by all channels.
+type byColor struct {
+	entries []colorCount
+	channel int
+}
+
+func (s byColor) Len() int      { return len(s.entries) }
+func (s byColor) Swap(i, j int) { s.entries[i], s.entries[j] = s.entries[j], s.entries[i] }
+func (s byColor) Less(i, j int) bool {
+	a, b := s.entries[i].color, s.entries[j].color
+	if s.channel >= 0 {
+		ca, cb := channel(a, s.channel), channel(b, s.channel)
+		if ca != cb {
+			return ca < cb
+		}
+	}
+	for k := 0; k < 4; k++ {
+		ca, cb := channel(a, k), channel(b, k)
+		if ca != cb {
+			return ca < cb
+		}
+	}
+	return false
+}
+
+// colorBox is a set of colors and the channel
+// with the largest range of values in the set.
+type colorBox struct {
+	entries []colorCount
+	channel int
+	span    int
+}
+
+func newColorBox(entries []colorCount) colorBox {
+	bx := colorBox{entries: entries}
+	for k := 0; k < 4; k++ {
+		min, max := uint8(255), uint8(0)
+		for _, e := range entries {
+			v := channel(e.color, k)
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+		if span := int(max) - int(min); span > bx.span {
+			bx.channel, bx.span = k, span
+		}
+	}
+	return bx
+}
+
+// split divides the box at the pixel count median of its widest
+// channel. Both returned boxes are non-empty.
+func (bx colorBox) split() (lo, hi colorBox) {
+	sort.Sort(byColor{bx.entries, bx.channel})
+	var total int
+	for _, e := range bx.entries {
+		total += e.n
+	}
+	i, sum := 0, 0
+	for ; i < len(bx.entries)-2; i++ {
+		sum += bx.entries[i].n
+		if 2*sum >= total {
+			break
+		}
+	}
+	return newColorBox(bx.entries[:i+1]), newColorBox(bx.entries[i+1:])
+}
+
+// mean returns the pixel weighted mean color of the box.
+func (bx colorBox) mean() color.Color {
+	var r, g, b, a, n int
+	for _, e := range bx.entries {
+		r += int(e.color.R) * e.n
+		g += int(e.color.G) * e.n
+		b += int(e.color.B) * e.n
+		a += int(e.color.A) * e.n
+		n += e.n
+	}
+	return color.RGBA{
+		R: uint8((r + n/2) / n),
+		G: uint8((g + n/2) / n),
+		B: uint8((b + n/2) / n),
+		A: uint8((a + n/2) / n),
+	}
+}
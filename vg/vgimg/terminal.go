@@ -0,0 +1,203 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgimg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+// DefaultSixelColors is the number of palette entries used
+// when writing a Sixel image if the number is not specified.
+const DefaultSixelColors = 256
+
+// A SixelCanvas is an image canvas with a WriteTo method that
+// writes a Sixel image for display on a terminal.
+type SixelCanvas struct {
+	*Canvas
+
+	// Colors is the maximum number of palette
+	// entries used in the Sixel image. If Colors
+	// is zero, DefaultSixelColors is used.
+	Colors int
+}
+
+// WriteTo implements the io.WriterTo interface, writing a Sixel image.
+func (c SixelCanvas) WriteTo(w io.Writer) (int64, error) {
+	n := c.Colors
+	if n == 0 {
+		n = DefaultSixelColors
+	}
+	wc := writerCounter{Writer: w}
+	b := bufio.NewWriter(&wc)
+	if err := encodeSixel(b, c.img, n); err != nil {
+		return wc.n, err
+	}
+	err := b.Flush()
+	return wc.n, err
+}
+
+// encodeSixel writes img to w as a Sixel image using a palette
+// of at most n colors quantized with MedianCut. Pixels that are
+// mostly transparent are left unpainted.
+func encodeSixel(w io.Writer, img image.Image, n int) error {
+	if n < 1 || n > 256 {
+		return fmt.Errorf("vgimg: invalid number of sixel colors: %d", n)
+	}
+	b := img.Bounds()
+	p, ok := img.(*image.Paletted)
+	if !ok || len(p.Palette) > n {
+		pal := MedianCut{}.Quantize(make(color.Palette, 0, n), img)
+		p = image.NewPaletted(b, pal)
+		draw.Draw(p, b, img, b.Min, draw.Src)
+	}
+
+	var buf bytes.Buffer
+
+	// Use pixel aspect ratio 1:1 and leave
+	// unpainted pixels transparent.
+	fmt.Fprintf(&buf, "\x1bP0;1;0q\"1;1;%d;%d", b.Dx(), b.Dy())
+	for i, c := range p.Palette {
+		r, g, bl, _ := color.NRGBAModel.Convert(c).RGBA()
+		fmt.Fprintf(&buf, "#%d;2;%d;%d;%d", i, percent(r), percent(g), percent(bl))
+	}
+
+	used := make([]bool, len(p.Palette))
+	row := make([]byte, b.Dx())
+	for y0 := b.Min.Y; y0 < b.Max.Y; y0 += 6 {
+		for i := range used {
+			used[i] = false
+		}
+		for y := y0; y < y0+6 && y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				used[p.ColorIndexAt(x, y)] = true
+			}
+		}
+
+		first := true
+		for idx := range p.Palette {
+			if !used[idx] || !opaque(p.Palette[idx]) {
+				continue
+			}
+			for x := b.Min.X; x < b.Max.X; x++ {
+				var bits byte
+				for k := 0; k < 6 && y0+k < b.Max.Y; k++ {
+					if int(p.ColorIndexAt(x, y0+k)) == idx {
+						bits |= 1 << uint(k)
+					}
+				}
+				row[x-b.Min.X] = '?' + bits
+			}
+			if !first {
+				// Return to the start of the band.
+				buf.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&buf, "#%d", idx)
+			writeSixelRuns(&buf, row)
+		}
+		if y0+6 < b.Max.Y {
+			// Move to the next band.
+			buf.WriteByte('-')
+		}
+	}
+	buf.WriteString("\x1b\\")
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// writeSixelRuns writes the sixel characters in row using run
+// length encoding for runs longer than three characters. Trailing
+// empty sixels are omitted.
+func writeSixelRuns(buf *bytes.Buffer, row []byte) {
+	end := len(row)
+	for end > 0 && row[end-1] == '?' {
+		end--
+	}
+	for i := 0; i < end; {
+		j := i + 1
+		for j < end && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(buf, "!%d%c", n, row[i])
+		} else {
+			for ; i < j; i++ {
+				buf.WriteByte(row[i])
+			}
+		}
+		i = j
+	}
+}
+
+// percent returns the 16 bit color component v as a percentage.
+func percent(v uint32) int {
+	return int((v*100 + 0x7fff) / 0xffff)
+}
+
+// opaque returns whether c is mostly opaque.
+func opaque(c color.Color) bool {
+	_, _, _, a := c.RGBA()
+	return a >= 0x8000
+}
+
+// kittyChunkSize is the maximum number of base64
+// encoded bytes sent in each Kitty graphics command.
+const kittyChunkSize = 4096
+
+// A KittyCanvas is an image canvas with a WriteTo method that
+// writes an image using the Kitty terminal graphics protocol.
+type KittyCanvas struct {
+	*Canvas
+}
+
+// WriteTo implements the io.WriterTo interface, writing the image
+// as a PNG transferred using the Kitty terminal graphics protocol.
+func (c KittyCanvas) WriteTo(w io.Writer) (int64, error) {
+	wc := writerCounter{Writer: w}
+	b := bufio.NewWriter(&wc)
+	if err := encodeKitty(b, c.img); err != nil {
+		return wc.n, err
+	}
+	err := b.Flush()
+	return wc.n, err
+}
+
+// encodeKitty writes img to w as a PNG image transmitted and
+// displayed using the Kitty terminal graphics protocol. The
+// base64 encoded image is sent in chunks of kittyChunkSize bytes.
+func encodeKitty(w io.Writer, img image.Image) error {
+	var pngBuf bytes.Buffer
+	if err := png.Encode(&pngBuf, img); err != nil {
+		return err
+	}
+	data := base64.StdEncoding.EncodeToString(pngBuf.Bytes())
+
+	var buf bytes.Buffer
+	for i := 0; i < len(data) || i == 0; i += kittyChunkSize {
+		end := i + kittyChunkSize
+		more := 1
+		if end >= len(data) {
+			end = len(data)
+			more = 0
+		}
+		buf.WriteString("\x1b_G")
+		if i == 0 {
+			// Transmit and display a PNG image.
+			buf.WriteString("a=T,f=100,")
+		}
+		fmt.Fprintf(&buf, "m=%d;%s\x1b\\", more, data[i:end])
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
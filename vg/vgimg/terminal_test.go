@@ -0,0 +1,197 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgimg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeSixel(t *testing.T) {
+	img := image.NewPaletted(image.Rect(0, 0, 5, 8), color.Palette{color.Black, color.White})
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 5; x++ {
+			if x != 0 && y != 7 {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	err := encodeSixel(&buf, img, 256)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "\x1bP0;1;0q\"1;1;5;8" +
+		"#0;2;0;0;0#1;2;100;100;100" +
+		"#0~$#1?!4~-" +
+		"#0B!4A$#1?!4@" +
+		"\x1b\\"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected sixel stream:\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestEncodeSixelQuantized(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 1))
+	img.Set(0, 0, color.RGBA{R: 250, A: 255})
+	img.Set(1, 0, color.RGBA{R: 240, A: 255})
+	img.Set(2, 0, color.RGBA{B: 250, A: 255})
+	img.Set(3, 0, color.RGBA{B: 240, A: 255})
+
+	var buf bytes.Buffer
+	err := encodeSixel(&buf, img, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "\x1bP0;1;0q\"1;1;4;1" +
+		"#0;2;0;0;96#1;2;96;0;0" +
+		"#0??@@$#1@@" +
+		"\x1b\\"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected sixel stream:\ngot:  %q\nwant: %q", got, want)
+	}
+
+	for _, n := range []int{0, 257} {
+		if err := encodeSixel(&buf, img, n); err == nil {
+			t.Errorf("expected error for %d colors", n)
+		}
+	}
+}
+
+func TestMedianCut(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 1))
+	img.Set(0, 0, color.RGBA{R: 250, A: 255})
+	img.Set(1, 0, color.RGBA{R: 240, A: 255})
+	img.Set(2, 0, color.RGBA{B: 250, A: 255})
+	img.Set(3, 0, color.RGBA{B: 240, A: 255})
+
+	for _, test := range []struct {
+		n    int
+		want color.Palette
+	}{
+		{
+			n: 2,
+			want: color.Palette{
+				color.RGBA{B: 245, A: 255},
+				color.RGBA{R: 245, A: 255},
+			},
+		},
+		{
+			n: 3,
+			want: color.Palette{
+				color.RGBA{B: 240, A: 255},
+				color.RGBA{R: 245, A: 255},
+				color.RGBA{B: 250, A: 255},
+			},
+		},
+		{
+			n: 4,
+			want: color.Palette{
+				color.RGBA{B: 240, A: 255},
+				color.RGBA{B: 250, A: 255},
+				color.RGBA{R: 240, A: 255},
+				color.RGBA{R: 250, A: 255},
+			},
+		},
+		{
+			n: 8,
+			want: color.Palette{
+				color.RGBA{B: 240, A: 255},
+				color.RGBA{B: 250, A: 255},
+				color.RGBA{R: 240, A: 255},
+				color.RGBA{R: 250, A: 255},
+			},
+		},
+	} {
+		got := MedianCut{}.Quantize(make(color.Palette, 0, test.n), img)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected palette for %d colors:\ngot:  %v\nwant: %v", test.n, got, test.want)
+		}
+	}
+}
+
+func TestMedianCutDominant(t *testing.T) {
+	// The last color in channel order holds more than
+	// half of the pixels, so the median falls in the
+	// last entry of the box being split.
+	img := image.NewRGBA(image.Rect(0, 0, 10, 1))
+	draw.Draw(img, img.Bounds(), image.White, image.ZP, draw.Src)
+	img.Set(0, 0, color.Black)
+	img.Set(1, 0, color.RGBA{R: 128, G: 128, B: 128, A: 255})
+
+	got := MedianCut{}.Quantize(make(color.Palette, 0, 2), img)
+	want := color.Palette{
+		color.RGBA{R: 64, G: 64, B: 64, A: 255},
+		color.RGBA{R: 255, G: 255, B: 255, A: 255},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected palette:\ngot:  %v\nwant: %v", got, want)
+	}
+}
+
+func TestEncodeKitty(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, size := range []int{2, 64} {
+		img := image.NewRGBA(image.Rect(0, 0, size, size))
+		rnd.Read(img.Pix)
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 0xff
+		}
+
+		var buf bytes.Buffer
+		err := encodeKitty(&buf, img)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var data string
+		cmds := strings.SplitAfter(buf.String(), "\x1b\\")
+		if cmds[len(cmds)-1] != "" {
+			t.Fatalf("unterminated command in stream for size %d", size)
+		}
+		cmds = cmds[:len(cmds)-1]
+		if size > 2 && len(cmds) < 2 {
+			t.Errorf("expected chunked stream for size %d", size)
+		}
+		for i, cmd := range cmds {
+			prefix := "\x1b_Gm=1;"
+			if i == 0 {
+				prefix = "\x1b_Ga=T,f=100,m=1;"
+			}
+			if i == len(cmds)-1 {
+				prefix = strings.Replace(prefix, "m=1", "m=0", 1)
+			}
+			if !strings.HasPrefix(cmd, prefix) {
+				t.Fatalf("unexpected command %d for size %d: %q", i, size, cmd[:len(prefix)])
+			}
+			chunk := strings.TrimSuffix(strings.TrimPrefix(cmd, prefix), "\x1b\\")
+			if i < len(cmds)-1 && len(chunk) != kittyChunkSize {
+				t.Errorf("unexpected chunk length for command %d for size %d: got:%d want:%d", i, size, len(chunk), kittyChunkSize)
+			}
+			data += chunk
+		}
+
+		b, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			t.Fatalf("failed to decode base64 data for size %d: %v", size, err)
+		}
+		got, err := png.Decode(bytes.NewReader(b))
+		if err != nil {
+			t.Fatalf("failed to decode png for size %d: %v", size, err)
+		}
+		if !reflect.DeepEqual(got, img) {
+			t.Errorf("image mismatch for size %d", size)
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"image/jpeg"
 	"image/png"
 	"io"
+	"sync"
 
 	"github.com/llgcode/draw2d"
 	"golang.org/x/image/tiff"
@@ -178,6 +179,32 @@ func (c *Canvas) SetLineDash(ds []vg.Length, offs vg.Length) {
 	c.gc.SetLineDash(dashes, offs.Dots(c))
 }
 
+func (c *Canvas) SetLineCap(cap vg.LineCap) {
+	if cap < vg.ButtCap || cap > vg.SquareCap {
+		cap = vg.ButtCap
+	}
+	c.gc.SetLineCap(lineCaps[cap])
+}
+
+func (c *Canvas) SetLineJoin(join vg.LineJoin) {
+	if join < vg.MiterJoin || join > vg.BevelJoin {
+		join = vg.MiterJoin
+	}
+	c.gc.SetLineJoin(lineJoins[join])
+}
+
+// SetMiterLimit implements the vg.Canvas.SetMiterLimit
+// method. The limit is ignored because draw2d does not
+// support miter limits.
+func (c *Canvas) SetMiterLimit(limit float64) {}
+
+// lineCaps and lineJoins are the draw2d
+// line caps and joins.
+var (
+	lineCaps  = [...]draw2d.LineCap{vg.ButtCap: draw2d.ButtCap, vg.RoundCap: draw2d.RoundCap, vg.SquareCap: draw2d.SquareCap}
+	lineJoins = [...]draw2d.LineJoin{vg.MiterJoin: draw2d.MiterJoin, vg.RoundJoin: draw2d.RoundJoin, vg.BevelJoin: draw2d.BevelJoin}
+)
+
 func (c *Canvas) SetColor(clr color.Color) {
 	if clr == nil {
 		clr = color.Black
@@ -251,17 +278,32 @@ func (c *Canvas) DPI() float64 {
 }
 
 func (c *Canvas) FillString(font vg.Font, x, y vg.Length, str string) {
+	for _, run := range font.Runs(str) {
+		c.fillString(run.Font, x+run.Offset, y, run.Text)
+	}
+}
+
+// fillString draws str using the font, without using its fallbacks.
+func (c *Canvas) fillString(font vg.Font, x, y vg.Length, str string) {
 	c.gc.Save()
 	defer c.gc.Restore()
 
 	data, ok := fontMap[font.Name()]
 	if !ok {
-		panic(fmt.Sprintf("Font name %s is unknown", font.Name()))
-	}
-	if !registeredFont[font.Name()] {
-		draw2d.RegisterFont(data, font.Font())
-		registeredFont[font.Name()] = true
+		// Fonts added to vg are registered with
+		// draw2d under their own names.
+		data = draw2d.FontData{
+			Name:   font.Name(),
+			Family: draw2d.FontFamilySans,
+			Style:  draw2d.FontStyleNormal,
+		}
 	}
+	registerFont(font.Name(), data, font)
+
+	// Hold the read lock while drawing so that draw2d's
+	// font cache is not written during the font lookup.
+	registeredFontLock.RLock()
+	defer registeredFontLock.RUnlock()
 	c.gc.SetFontData(data)
 	c.gc.SetFontSize(font.Size.Points())
 	c.gc.Translate(x.Dots(c), y.Dots(c))
@@ -269,11 +311,32 @@ func (c *Canvas) FillString(font vg.Font, x, y vg.Length, str string) {
 	c.gc.FillString(str)
 }
 
+// registerFont registers the font with draw2d if it has not
+// already been registered. It is safe for concurrent use.
+func registerFont(name string, data draw2d.FontData, font vg.Font) {
+	registeredFontLock.RLock()
+	ok := registeredFont[name]
+	registeredFontLock.RUnlock()
+	if ok {
+		return
+	}
+	registeredFontLock.Lock()
+	if !registeredFont[name] {
+		draw2d.RegisterFont(data, font.Font())
+		registeredFont[name] = true
+	}
+	registeredFontLock.Unlock()
+}
+
 var (
 	// RegisteredFont contains the set of font names
 	// that have already been registered with draw2d.
 	registeredFont = map[string]bool{}
 
+	// registeredFontLock protects access to the registeredFont
+	// map and draw2d's font cache.
+	registeredFontLock sync.RWMutex
+
 	// FontMap contains a mapping from vg's font
 	// names to draw2d.FontData for the corresponding
 	// font.  This is needed to register the  fonts with
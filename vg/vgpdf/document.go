@@ -0,0 +1,159 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgpdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/draw"
+)
+
+// Document is a PDF document with pages of independent
+// sizes. Fonts used on several pages are embedded in the
+// document once. The zero value is an empty document.
+type Document struct {
+	// Title, Author and Subject are recorded in
+	// the document information if they are not
+	// empty.
+	Title, Author, Subject string
+
+	// Created is the creation date recorded in
+	// the document information. No creation date
+	// is recorded if Created is the zero time.
+	Created time.Time
+
+	fonts *fontSet
+	pages []page
+}
+
+// page is a page of a document.
+type page struct {
+	canvas *Canvas
+
+	// bookmark is the title of the outline
+	// entry for the page, if not empty.
+	bookmark string
+}
+
+// AddPage adds a page with the given size to the end of
+// the document and returns a canvas for drawing on it.
+// If bookmark is not empty, an outline entry with the
+// bookmark as its title is added for the page.
+func (d *Document) AddPage(w, h vg.Length, bookmark string) draw.Canvas {
+	if d.fonts == nil {
+		d.fonts = newFontSet()
+	}
+	c := newCanvas(w, h, d.fonts)
+	d.pages = append(d.pages, page{canvas: c, bookmark: bookmark})
+	return draw.New(c)
+}
+
+// WriteTo writes the document to an io.Writer.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		return 0, fmt.Errorf("vgpdf: document has no pages")
+	}
+	var info bytes.Buffer
+	info.WriteString("<< /Producer (github.com/gonum/plot/vg/vgpdf)")
+	for _, e := range []struct{ key, val string }{
+		{"Title", d.Title},
+		{"Author", d.Author},
+		{"Subject", d.Subject},
+	} {
+		if e.val != "" {
+			fmt.Fprintf(&info, " /%s %s", e.key, textString(e.val))
+		}
+	}
+	if !d.Created.IsZero() {
+		fmt.Fprintf(&info, " /CreationDate %s", literal(date(d.Created)))
+	}
+	info.WriteString(" >>")
+	return write(w, d.fonts, d.pages, info.String())
+}
+
+// write writes a PDF document with the given fonts,
+// pages and document information dictionary to w.
+func write(w io.Writer, fonts *fontSet, pages []page, info string) (int64, error) {
+	var d document
+	catalog := d.reserve()
+	tree := d.reserve()
+
+	var marks []int
+	for i, p := range pages {
+		if p.bookmark != "" {
+			marks = append(marks, i)
+		}
+	}
+	var outlines int
+	if len(marks) > 0 {
+		outlines = d.reserve()
+	}
+
+	var res bytes.Buffer
+	for _, f := range fonts.order {
+		fmt.Fprintf(&res, " /%s %s", f.res, ref(f.write(&d)))
+	}
+	kids := make([]string, len(pages))
+	objs := make([]int, len(pages))
+	for i, p := range pages {
+		objs[i] = p.canvas.writePage(&d, tree, res.String())
+		kids[i] = ref(objs[i])
+	}
+	d.set(tree, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+
+	if outlines == 0 {
+		d.set(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %s >>", ref(tree)))
+	} else {
+		items := make([]int, len(marks))
+		for i := range items {
+			items[i] = d.reserve()
+		}
+		for i, m := range marks {
+			item := fmt.Sprintf("<< /Title %s /Parent %s /Dest [%s /Fit]",
+				textString(pages[m].bookmark), ref(outlines), ref(objs[m]))
+			if i > 0 {
+				item += " /Prev " + ref(items[i-1])
+			}
+			if i < len(items)-1 {
+				item += " /Next " + ref(items[i+1])
+			}
+			d.set(items[i], item+" >>")
+		}
+		d.set(outlines, fmt.Sprintf("<< /Type /Outlines /First %s /Last %s /Count %d >>",
+			ref(items[0]), ref(items[len(items)-1]), len(items)))
+		d.set(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %s /Outlines %s /PageMode /UseOutlines >>",
+			ref(tree), ref(outlines)))
+	}
+	infoObj := d.add(info)
+
+	wc := writerCounter{Writer: w}
+	b := bufio.NewWriter(&wc)
+	if err := d.writeTo(b, catalog, infoObj); err != nil {
+		return wc.n, err
+	}
+	err := b.Flush()
+	return wc.n, err
+}
+
+// date returns t formatted as a PDF date string.
+func date(t time.Time) string {
+	s := "D:" + t.Format("20060102150405")
+	_, off := t.Zone()
+	if off == 0 {
+		return s + "Z"
+	}
+	sign := '+'
+	if off < 0 {
+		sign = '-'
+		off = -off
+	}
+	return fmt.Sprintf("%s%c%02d'%02d'", s, sign, off/3600, off%3600/60)
+}
@@ -0,0 +1,247 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgpdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"unicode/utf16"
+
+	"code.google.com/p/freetype-go/freetype/truetype"
+	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/internal/ttf"
+)
+
+// glyphScale is the number of units per em in
+// PDF glyph space.
+const glyphScale = 1000
+
+// pdfFont is a font used by a Canvas. Fonts with TrueType
+// data are embedded as composite fonts addressing glyphs by
+// index, so that any character in the font can be shown.
+// Other fonts are referred to by name as standard Type 1
+// fonts using WinAnsiEncoding.
+type pdfFont struct {
+	// res is the name of the font in
+	// the page resource dictionary.
+	res string
+
+	name string
+	font *truetype.Font
+	data []byte
+
+	// glyphs maps the glyphs that have been
+	// shown to the characters they represent.
+	glyphs map[truetype.Index]rune
+}
+
+// fontSet is the set of fonts used by the canvases
+// of a document. Each font is written once and is
+// included in the resources of every page.
+type fontSet struct {
+	byName map[string]*pdfFont
+	order  []*pdfFont
+}
+
+func newFontSet() *fontSet {
+	return &fontSet{byName: make(map[string]*pdfFont)}
+}
+
+// get returns the font in the set for fnt,
+// adding the font to the set if necessary.
+func (s *fontSet) get(fnt vg.Font) *pdfFont {
+	f, ok := s.byName[fnt.Name()]
+	if !ok {
+		f = newFont(fnt, fmt.Sprintf("F%d", len(s.order)+1))
+		s.byName[fnt.Name()] = f
+		s.order = append(s.order, f)
+	}
+	return f
+}
+
+func newFont(fnt vg.Font, res string) *pdfFont {
+	return &pdfFont{
+		res:    res,
+		name:   fnt.Name(),
+		font:   fnt.Font(),
+		data:   fnt.Data(),
+		glyphs: make(map[truetype.Index]rune),
+	}
+}
+
+// show returns the content stream operation that shows
+// str, with character spacing adjusted for kerning.
+func (f *pdfFont) show(str string) string {
+	var (
+		buf  bytes.Buffer
+		run  []byte
+		prev truetype.Index
+	)
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if f.data != nil {
+			fmt.Fprintf(&buf, "<%X>", run)
+		} else {
+			buf.WriteString(literal(string(run)))
+		}
+		run = run[:0]
+	}
+
+	buf.WriteByte('[')
+	for i, r := range str {
+		idx := f.font.Index(r)
+		if i > 0 {
+			if k := f.font.Kerning(glyphScale, prev, idx); k != 0 {
+				flush()
+				// Positive adjustments move the next glyph left.
+				fmt.Fprintf(&buf, " %d ", -k)
+			}
+		}
+		prev = idx
+
+		if f.data == nil {
+			run = append(run, winAnsi(r))
+			continue
+		}
+		if _, ok := f.glyphs[idx]; !ok {
+			f.glyphs[idx] = r
+		}
+		run = append(run, byte(idx>>8), byte(idx))
+	}
+	flush()
+	buf.WriteString("] TJ")
+	return buf.String()
+}
+
+// write adds the objects describing the font to d and
+// returns the number of the font dictionary object.
+func (f *pdfFont) write(d *document) int {
+	if f.data == nil {
+		return d.add(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont %s /Encoding /WinAnsiEncoding >>",
+			name(f.name)))
+	}
+
+	// Only the glyphs that have been shown are embedded.
+	// Subset fonts are named with a tag derived from
+	// their glyphs so that different subsets of a font
+	// are not mistaken for each other. If the font data
+	// cannot be subset, the whole font is embedded.
+	idx := f.indices()
+	fontName := f.name
+	data, err := ttf.Subset(f.data, idx, ttf.OutlineTables)
+	if err == nil {
+		fontName = ttf.Tag(idx) + "+" + f.name
+	} else {
+		data = f.data
+	}
+
+	b := f.font.Bounds(glyphScale)
+	file := d.addStream(fmt.Sprintf(" /Length1 %d", len(data)), data, true)
+	desc := d.add(fmt.Sprintf("<< /Type /FontDescriptor /FontName %s /Flags 32 /FontBBox [%d %d %d %d] "+
+		"/ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %s >>",
+		name(fontName), b.XMin, b.YMin, b.XMax, b.YMax, b.YMax, b.YMin, b.YMax, ref(file)))
+
+	var w bytes.Buffer
+	for i, g := range idx {
+		if i > 0 {
+			w.WriteByte(' ')
+		}
+		fmt.Fprintf(&w, "%d [%d]", g, f.font.HMetric(glyphScale, g).AdvanceWidth)
+	}
+	cid := d.add(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont %s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor %s /W [%s] /CIDToGIDMap /Identity >>",
+		name(fontName), ref(desc), w.String()))
+
+	cmap := d.addStream("", f.toUnicode(), true)
+	return d.add(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont %s /Encoding /Identity-H "+
+		"/DescendantFonts [%s] /ToUnicode %s >>",
+		name(fontName), ref(cid), ref(cmap)))
+}
+
+// indices returns the indices of the glyphs
+// that have been shown, in increasing order.
+func (f *pdfFont) indices() []truetype.Index {
+	idx := make([]truetype.Index, 0, len(f.glyphs))
+	for i := range f.glyphs {
+		idx = append(idx, i)
+	}
+	sort.Sort(byIndex(idx))
+	return idx
+}
+
+type byIndex []truetype.Index
+
+func (s byIndex) Len() int           { return len(s) }
+func (s byIndex) Less(i, j int) bool { return s[i] < s[j] }
+func (s byIndex) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// maxBFChars is the maximum number of mappings
+// in a single bfchar section of a CMap.
+const maxBFChars = 100
+
+// toUnicode returns a CMap mapping the glyph indices that
+// have been shown to their characters, allowing text to be
+// searched and extracted from the document.
+func (f *pdfFont) toUnicode() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def
+/CMapName /Adobe-Identity-UCS def
+/CMapType 2 def
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+`)
+	idx := f.indices()
+	for len(idx) > 0 {
+		n := len(idx)
+		if n > maxBFChars {
+			n = maxBFChars
+		}
+		fmt.Fprintf(&buf, "%d beginbfchar\n", n)
+		for _, i := range idx[:n] {
+			fmt.Fprintf(&buf, "<%04X> <", i)
+			for _, u := range utf16.Encode([]rune{f.glyphs[i]}) {
+				fmt.Fprintf(&buf, "%04X", u)
+			}
+			buf.WriteString(">\n")
+		}
+		buf.WriteString("endbfchar\n")
+		idx = idx[n:]
+	}
+	buf.WriteString(`endcmap
+CMapName currentdict /CMap defineresource pop
+end
+end
+`)
+	return buf.Bytes()
+}
+
+// winAnsi returns the WinAnsiEncoding code for r,
+// or '?' if r is not in the encoding.
+func winAnsi(r rune) byte {
+	if r < 0x80 || (0xa0 <= r && r <= 0xff) {
+		return byte(r)
+	}
+	if c, ok := winAnsiHigh[r]; ok {
+		return c
+	}
+	return '?'
+}
+
+// winAnsiHigh maps the characters encoded in the range
+// 0x80-0x9f by WinAnsiEncoding to their codes.
+var winAnsiHigh = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
@@ -0,0 +1,49 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgpdf
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/gonum/plot/vg"
+)
+
+// pdfImage is an image drawn on a Canvas.
+type pdfImage struct {
+	// res is the resource name of the
+	// image XObject.
+	res string
+
+	img    image.Image
+	interp vg.Interpolation
+}
+
+// write adds the image XObject to the document and returns
+// its object number. Images that are not opaque are drawn
+// with a soft mask holding their alpha channel.
+func (im *pdfImage) write(d *document) int {
+	b := im.img.Bounds()
+	rgb := make([]byte, 0, 3*b.Dx()*b.Dy())
+	alpha := make([]byte, 0, b.Dx()*b.Dy())
+	opaque := true
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(im.img.At(x, y)).(color.NRGBA)
+			rgb = append(rgb, c.R, c.G, c.B)
+			alpha = append(alpha, c.A)
+			opaque = opaque && c.A == 0xff
+		}
+	}
+
+	entries := fmt.Sprintf(" /Type /XObject /Subtype /Image /Width %d /Height %d /BitsPerComponent 8 /Interpolate %t",
+		b.Dx(), b.Dy(), im.interp == vg.Bilinear)
+	if !opaque {
+		mask := d.addStream(entries+" /ColorSpace /DeviceGray", alpha, true)
+		entries += " /SMask " + ref(mask)
+	}
+	return d.addStream(entries+" /ColorSpace /DeviceRGB", rgb, true)
+}
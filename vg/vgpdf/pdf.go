@@ -0,0 +1,150 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgpdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// document is a PDF file under construction. Objects are
+// numbered from one in the order that they are reserved.
+type document struct {
+	objs [][]byte
+}
+
+// reserve returns the number of a new object whose
+// body is set later by a call to set.
+func (d *document) reserve() int {
+	d.objs = append(d.objs, nil)
+	return len(d.objs)
+}
+
+// set sets the body of object n.
+func (d *document) set(n int, body string) {
+	d.objs[n-1] = []byte(body)
+}
+
+// add adds an object with the given body and
+// returns its number.
+func (d *document) add(body string) int {
+	n := d.reserve()
+	d.set(n, body)
+	return n
+}
+
+// addStream adds a stream object with the given dictionary
+// entries and data and returns its number. The data is
+// compressed if compress is true.
+func (d *document) addStream(entries string, data []byte, compress bool) int {
+	if compress {
+		var buf bytes.Buffer
+		z := zlib.NewWriter(&buf)
+		z.Write(data)
+		z.Close()
+		data = buf.Bytes()
+		entries += " /Filter /FlateDecode"
+	}
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "<< /Length %d%s >>\nstream\n", len(data), entries)
+	body.Write(data)
+	body.WriteString("\nendstream")
+
+	n := d.reserve()
+	d.objs[n-1] = body.Bytes()
+	return n
+}
+
+// writeTo writes the document to w with the given
+// catalog and document information objects.
+func (d *document) writeTo(w io.Writer, root, info int) error {
+	var buf bytes.Buffer
+
+	// The comment of high bytes marks the file as binary.
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(d.objs))
+	for i, body := range d.objs {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", i+1)
+		buf.Write(body)
+		buf.WriteString("\nendobj\n")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(d.objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\n", len(d.objs)+1, root, info)
+	fmt.Fprintf(&buf, "startxref\n%d\n%%%%EOF\n", xref)
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// ref returns a reference to object n.
+func ref(n int) string {
+	return fmt.Sprintf("%d 0 R", n)
+}
+
+// name returns s as a PDF name object, escaping
+// delimiters, white space and non-ASCII bytes.
+func name(s string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('/')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c > '~' || c == '#' || strings.IndexByte("()<>[]{}/%", c) >= 0 {
+			fmt.Fprintf(&buf, "#%02X", c)
+			continue
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
+}
+
+// literal returns s as a PDF literal string.
+func literal(s string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('(')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '(', ')', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\n':
+			buf.WriteString(`\n`)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// textString returns s as a PDF text string. Strings
+// that are not ASCII are encoded as UTF-16 with a byte
+// order mark.
+func textString(s string) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			var buf bytes.Buffer
+			buf.WriteString("<FEFF")
+			for _, u := range utf16.Encode([]rune(s)) {
+				fmt.Fprintf(&buf, "%04X", u)
+			}
+			buf.WriteByte('>')
+			return buf.String()
+		}
+	}
+	return literal(s)
+}
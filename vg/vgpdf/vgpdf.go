@@ -3,38 +3,63 @@
 // license that can be found in the LICENSE file.
 
 // Package vgpdf implements the vg.Canvas interface
-// using gopdf (bitbucket.org/zombiezen/gopdf/pdf).
+// by writing PDF documents. Fonts with TrueType data
+// available are embedded in the document.
 package vgpdf
 
 import (
-	"bufio"
+	"bytes"
 	"fmt"
+	"image"
 	"image/color"
 	"io"
 	"math"
 
-	"bitbucket.org/zombiezen/gopdf/pdf"
 	"github.com/gonum/plot/vg"
 )
 
+// pr is the amount of precision to use when outputting float64s.
+const pr = 5
+
 // Canvas implements the vg.Canvas interface,
 // drawing to a PDF.
 type Canvas struct {
-	doc         *pdf.Document
-	w, h        vg.Length
-	page        *pdf.Canvas
+	w, h   vg.Length
+	buf    *bytes.Buffer
+	fonts  *fontSet
+	images []*pdfImage
+	stk    []ctx
+}
+
+// ctx is the part of the PDF graphics state
+// that is tracked so that only changes to it
+// are written to the content stream.
+type ctx struct {
 	lineVisible bool
+	cap         vg.LineCap
+	join        vg.LineJoin
+	miter       float64
 }
 
+// initialCtx is the context of the initial
+// PDF graphics state.
+var initialCtx = ctx{lineVisible: true, miter: 10}
+
 // New creates a new PDF Canvas.
 func New(w, h vg.Length) *Canvas {
+	return newCanvas(w, h, newFontSet())
+}
+
+// newCanvas returns a new Canvas adding
+// the fonts it uses to the given set.
+func newCanvas(w, h vg.Length, fonts *fontSet) *Canvas {
 	c := &Canvas{
-		doc:         pdf.New(),
-		w:           w,
-		h:           h,
-		lineVisible: true,
+		w:     w,
+		h:     h,
+		buf:   new(bytes.Buffer),
+		fonts: fonts,
+		stk:   []ctx{initialCtx},
 	}
-	c.page = c.doc.NewPage(unit(w), unit(h))
 	vg.Initialize(c)
 	return c
 }
@@ -43,84 +68,140 @@ func (c *Canvas) Size() (w, h vg.Length) {
 	return c.w, c.h
 }
 
+// cur returns the top context on the stack.
+func (c *Canvas) cur() *ctx {
+	return &c.stk[len(c.stk)-1]
+}
+
 func (c *Canvas) SetLineWidth(w vg.Length) {
-	c.page.SetLineWidth(unit(w))
-	c.lineVisible = w > 0
+	fmt.Fprintf(c.buf, "%.*g w\n", pr, w.Points())
+	c.cur().lineVisible = w > 0
 }
 
 func (c *Canvas) SetLineDash(dashes []vg.Length, offs vg.Length) {
-	ds := make([]pdf.Unit, len(dashes))
+	c.buf.WriteString("[")
 	for i, d := range dashes {
-		ds[i] = unit(d)
+		if i > 0 {
+			c.buf.WriteString(" ")
+		}
+		fmt.Fprintf(c.buf, "%.*g", pr, d.Points())
+	}
+	fmt.Fprintf(c.buf, "] %.*g d\n", pr, offs.Points())
+}
+
+func (c *Canvas) SetLineCap(cap vg.LineCap) {
+	if cap < vg.ButtCap || cap > vg.SquareCap {
+		cap = vg.ButtCap
+	}
+	if c.cur().cap != cap {
+		c.cur().cap = cap
+		fmt.Fprintf(c.buf, "%d J\n", cap)
+	}
+}
+
+func (c *Canvas) SetLineJoin(join vg.LineJoin) {
+	if join < vg.MiterJoin || join > vg.BevelJoin {
+		join = vg.MiterJoin
+	}
+	if c.cur().join != join {
+		c.cur().join = join
+		fmt.Fprintf(c.buf, "%d j\n", join)
+	}
+}
+
+func (c *Canvas) SetMiterLimit(limit float64) {
+	if c.cur().miter != limit {
+		c.cur().miter = limit
+		fmt.Fprintf(c.buf, "%.*g M\n", pr, limit)
 	}
-	c.page.SetLineDash(unit(offs), ds)
 }
 
 func (c *Canvas) SetColor(clr color.Color) {
-	c.page.SetStrokeColor(pdfColor(clr))
-	c.page.SetColor(pdfColor(clr))
+	r, g, b := pdfColor(clr)
+	fmt.Fprintf(c.buf, "%.*g %.*g %.*g RG %.*g %.*g %.*g rg\n", pr, r, pr, g, pr, b, pr, r, pr, g, pr, b)
 }
 
 func (c *Canvas) Rotate(r float64) {
-	c.page.Rotate(float32(r))
+	s, cos := math.Sin(r), math.Cos(r)
+	fmt.Fprintf(c.buf, "%.*g %.*g %.*g %.*g 0 0 cm\n", pr, cos, pr, s, pr, -s, pr, cos)
 }
 
 func (c *Canvas) Translate(x vg.Length, y vg.Length) {
-	c.page.Translate(unit(x), unit(y))
+	fmt.Fprintf(c.buf, "1 0 0 1 %.*g %.*g cm\n", pr, x.Points(), pr, y.Points())
 }
 
 func (c *Canvas) Scale(x float64, y float64) {
-	c.page.Scale(float32(x), float32(y))
+	fmt.Fprintf(c.buf, "%.*g 0 0 %.*g 0 0 cm\n", pr, x, pr, y)
 }
 
 func (c *Canvas) Push() {
-	c.page.Push()
+	c.stk = append(c.stk, *c.cur())
+	c.buf.WriteString("q\n")
 }
 
 func (c *Canvas) Pop() {
-	c.page.Pop()
+	c.stk = c.stk[:len(c.stk)-1]
+	c.buf.WriteString("Q\n")
 }
 
 func (c *Canvas) Stroke(p vg.Path) {
-	if c.lineVisible {
-		c.page.Stroke(pdfPath(c, p))
+	if c.cur().lineVisible {
+		c.trace(p)
+		c.buf.WriteString("S\n")
 	}
 }
 
 func (c *Canvas) Fill(p vg.Path) {
-	c.page.Fill(pdfPath(c, p))
+	c.trace(p)
+	c.buf.WriteString("f\n")
 }
 
 func (c *Canvas) FillString(fnt vg.Font, x, y vg.Length, str string) {
-	t := new(pdf.Text)
-	t.SetFont(fnt.Name(), unit(fnt.Size))
-	t.NextLineOffset(unit(x), unit(y))
-	t.Text(str)
-	c.page.DrawText(t)
+	for _, run := range fnt.Runs(str) {
+		f := c.fonts.get(run.Font)
+		fmt.Fprintf(c.buf, "BT\n/%s %.*g Tf\n%.*g %.*g Td\n%s\nET\n",
+			f.res, pr, fnt.Size.Points(), pr, (x + run.Offset).Points(), pr, y.Points(), f.show(run.Text))
+	}
+}
+
+// DrawImage implements the vg.Canvas.DrawImage method.
+func (c *Canvas) DrawImage(x, y, w, h vg.Length, img image.Image, interp vg.Interpolation) {
+	if img.Bounds().Empty() {
+		return
+	}
+	im := &pdfImage{res: fmt.Sprintf("Im%d", len(c.images)+1), img: img, interp: interp}
+	c.images = append(c.images, im)
+	fmt.Fprintf(c.buf, "q\n%.*g 0 0 %.*g %.*g %.*g cm\n/%s Do\nQ\n",
+		pr, w.Points(), pr, h.Points(), pr, x.Points(), pr, y.Points(), im.res)
 }
 
 func (*Canvas) DPI() float64 {
-	return float64(pdf.Inch)
+	return 72
 }
 
-// pdfPath returns a pdf.Path from a vg.Path.
-func pdfPath(c *Canvas, path vg.Path) *pdf.Path {
-	p := new(pdf.Path)
+// trace writes the path construction
+// operations for path to the content stream.
+func (c *Canvas) trace(path vg.Path) {
 	for _, comp := range path {
 		switch comp.Type {
 		case vg.MoveComp:
-			p.Move(pdfPoint(comp.X, comp.Y))
+			c.point(comp.X, comp.Y, "m")
 		case vg.LineComp:
-			p.Line(pdfPoint(comp.X, comp.Y))
+			c.point(comp.X, comp.Y, "l")
 		case vg.ArcComp:
-			arc(p, comp)
+			c.arc(comp)
 		case vg.CloseComp:
-			p.Close()
+			c.buf.WriteString("h\n")
 		default:
 			panic(fmt.Sprintf("Unknown path component type: %d\n", comp.Type))
 		}
 	}
-	return p
+}
+
+// point writes the coordinates x and y
+// followed by the operator op.
+func (c *Canvas) point(x, y vg.Length, op string) {
+	fmt.Fprintf(c.buf, "%.*g %.*g %s\n", pr, x.Points(), pr, y.Points(), op)
 }
 
 // Approximate a circular arc using multiple
@@ -128,10 +209,10 @@ func pdfPath(c *Canvas, path vg.Path) *pdf.Path {
 //
 // This is from:
 // 	http://hansmuller-flex.blogspot.com/2011/04/approximating-circular-arc-with-cubic.html
-func arc(p *pdf.Path, comp vg.PathComp) {
+func (c *Canvas) arc(comp vg.PathComp) {
 	x0 := comp.X + comp.Radius*vg.Length(math.Cos(comp.Start))
 	y0 := comp.Y + comp.Radius*vg.Length(math.Sin(comp.Start))
-	p.Line(pdfPoint(x0, y0))
+	c.point(x0, y0, "l")
 
 	a1 := comp.Start
 	end := a1 + comp.Angle
@@ -148,7 +229,7 @@ func arc(p *pdf.Path, comp vg.PathComp) {
 
 	for left > epsilon {
 		a2 := a1 + sign*math.Min(math.Pi/2, left)
-		partialArc(p, comp.X, comp.Y, comp.Radius, a1, a2)
+		c.partialArc(comp.X, comp.Y, comp.Radius, a1, a2)
 		left -= math.Abs(a2 - a1)
 		a1 = a2
 	}
@@ -156,7 +237,7 @@ func arc(p *pdf.Path, comp vg.PathComp) {
 
 // Approximate a circular arc of fewer than π/2
 // radians with cubic Bézier curve.
-func partialArc(p *pdf.Path, x, y, r vg.Length, a1, a2 float64) {
+func (c *Canvas) partialArc(x, y, r vg.Length, a1, a2 float64) {
 	a := (a2 - a1) / 2
 	x4 := r * vg.Length(math.Cos(a))
 	y4 := r * vg.Length(math.Sin(a))
@@ -180,26 +261,18 @@ func partialArc(p *pdf.Path, x, y, r vg.Length, a1, a2 float64) {
 	y3r := x3*sinar + y3*cosar + y
 	x4 = r*vg.Length(math.Cos(a2)) + x
 	y4 = r*vg.Length(math.Sin(a2)) + y
-	p.Curve(pdfPoint(x2r, y2r), pdfPoint(x3r, y3r), pdfPoint(x4, y4))
-}
-
-func pdfPoint(x, y vg.Length) pdf.Point {
-	return pdf.Point{X: unit(x), Y: unit(y)}
+	fmt.Fprintf(c.buf, "%.*g %.*g %.*g %.*g %.*g %.*g c\n",
+		pr, x2r.Points(), pr, y2r.Points(), pr, x3r.Points(), pr, y3r.Points(), pr, x4.Points(), pr, y4.Points())
 }
 
-func pdfColor(clr color.Color) (float32, float32, float32) {
+func pdfColor(clr color.Color) (r, g, b float64) {
 	if clr == nil {
 		clr = color.Black
 	}
-	r, g, b, _ := clr.RGBA()
-	return float32(r) / math.MaxUint16,
-		float32(g) / math.MaxUint16,
-		float32(b) / math.MaxUint16
-}
-
-// unit returns a pdf.Unit, converted from a vg.Length.
-func unit(l vg.Length) pdf.Unit {
-	return pdf.Unit(l.Points()) * pdf.Pt
+	cr, cg, cb, _ := clr.RGBA()
+	return float64(cr) / math.MaxUint16,
+		float64(cg) / math.MaxUint16,
+		float64(cb) / math.MaxUint16
 }
 
 // WriterCounter implements the io.Writer interface, and counts
@@ -216,15 +289,25 @@ func (w *writerCounter) Write(p []byte) (int, error) {
 }
 
 // WriteTo writes the Canvas to an io.Writer.
-// After calling Write, the canvas is closed
-// and may no longer be used for drawing.
 func (c *Canvas) WriteTo(w io.Writer) (int64, error) {
-	c.page.Close()
-	wc := writerCounter{Writer: w}
-	b := bufio.NewWriter(&wc)
-	if err := c.doc.Encode(b); err != nil {
-		return wc.n, err
+	return write(w, c.fonts, []page{{canvas: c}}, "<< /Producer (github.com/gonum/plot/vg/vgpdf) >>")
+}
+
+// writePage adds the page drawn on the canvas to
+// the document with the given parent page tree
+// and font resources, and returns its object number.
+func (c *Canvas) writePage(d *document, parent int, fonts string) int {
+	procs, images := "/PDF /Text", ""
+	if len(c.images) > 0 {
+		var buf bytes.Buffer
+		for _, im := range c.images {
+			fmt.Fprintf(&buf, " /%s %s", im.res, ref(im.write(d)))
+		}
+		procs += " /ImageC"
+		images = " /XObject <<" + buf.String() + " >>"
 	}
-	err := b.Flush()
-	return wc.n, err
+	content := d.addStream("", c.buf.Bytes(), true)
+	return d.add(fmt.Sprintf("<< /Type /Page /Parent %s /MediaBox [0 0 %.*g %.*g] "+
+		"/Resources << /ProcSet [%s] /Font <<%s >>%s >> /Contents %s >>",
+		ref(parent), pr, c.w.Points(), pr, c.h.Points(), procs, fonts, images, ref(content)))
 }
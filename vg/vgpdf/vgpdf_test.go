@@ -0,0 +1,333 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgpdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gonum/plot/vg"
+)
+
+var (
+	xrefEntry = regexp.MustCompile(`(\d{10}) 00000 n `)
+	stream    = regexp.MustCompile(`(?s)<< /Length (\d+)([^>]*)>>\nstream\n`)
+)
+
+// streams returns the decompressed contents of
+// the streams in a PDF document.
+func streams(t *testing.T, pdf []byte) []string {
+	var s []string
+	for _, m := range stream.FindAllSubmatchIndex(pdf, -1) {
+		n, _ := strconv.Atoi(string(pdf[m[2]:m[3]]))
+		data := pdf[m[1] : m[1]+n]
+		if bytes.Contains(pdf[m[4]:m[5]], []byte("/FlateDecode")) {
+			r, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("failed to decompress stream: %v", err)
+			}
+			data, err = ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("failed to decompress stream: %v", err)
+			}
+		}
+		s = append(s, string(data))
+	}
+	return s
+}
+
+func TestXref(t *testing.T) {
+	c := New(vg.Inch, vg.Inch)
+	c.Fill(vg.Path{{Type: vg.MoveComp}, {Type: vg.LineComp, X: 10, Y: 10}, {Type: vg.CloseComp}})
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pdf := buf.Bytes()
+
+	entries := xrefEntry.FindAllSubmatch(pdf, -1)
+	if len(entries) != 5 {
+		t.Fatalf("unexpected number of objects: got:%d want:5", len(entries))
+	}
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		want := fmt.Sprintf("%d 0 obj\n", i+1)
+		if !bytes.HasPrefix(pdf[off:], []byte(want)) {
+			t.Errorf("xref entry %d does not refer to object", i+1)
+		}
+	}
+	i := bytes.LastIndex(pdf, []byte("startxref\n"))
+	off, _ := strconv.Atoi(strings.Fields(string(pdf[i:]))[1])
+	if !bytes.HasPrefix(pdf[off:], []byte("xref\n")) {
+		t.Error("startxref does not refer to xref table")
+	}
+
+	content := streams(t, pdf)[0]
+	if want := "0 0 m\n10 10 l\nh\nf\n"; !strings.Contains(content, want) {
+		t.Errorf("missing path in content stream:\n%s", content)
+	}
+}
+
+func TestEmbedFont(t *testing.T) {
+	fnt, err := vg.MakeFont("Helvetica", 12)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	if fnt.Data() == nil {
+		t.Fatal("missing font data")
+	}
+	c := New(vg.Inch, vg.Inch)
+	c.FillString(fnt, 0, 0, "Aé(")
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pdf := buf.String()
+
+	for _, want := range []string{
+		"/Subtype /CIDFontType2",
+		"/CIDToGIDMap /Identity",
+	} {
+		if !strings.Contains(pdf, want) {
+			t.Errorf("missing %q in document", want)
+		}
+	}
+	if !regexp.MustCompile(`/Subtype /Type0 /BaseFont /[A-Z]{6}\+Helvetica /Encoding /Identity-H`).MatchString(pdf) {
+		t.Error("missing subset font name in document")
+	}
+	m := regexp.MustCompile(`/Length1 (\d+)`).FindStringSubmatch(pdf)
+	if m == nil {
+		t.Fatal("missing embedded font file")
+	}
+	if n, _ := strconv.Atoi(m[1]); n >= len(fnt.Data())/4 {
+		t.Errorf("embedded font not subset: got length %d of %d", n, len(fnt.Data()))
+	}
+
+	var text, cmap string
+	for _, s := range streams(t, buf.Bytes()) {
+		switch {
+		case strings.Contains(s, "begincmap"):
+			cmap = s
+		case strings.Contains(s, "BT\n"):
+			text = s
+		}
+	}
+	var want string
+	for _, r := range "Aé(" {
+		idx := fnt.Font().Index(r)
+		want += fmt.Sprintf("%04X", idx)
+		if m := fmt.Sprintf("<%04X> <%04X>\n", idx, r); !strings.Contains(cmap, m) {
+			t.Errorf("missing ToUnicode mapping %q", m)
+		}
+	}
+	if !strings.Contains(text, "/F1 12 Tf") || !strings.Contains(text, "<"+want+">") {
+		t.Errorf("unexpected text operations:\n%s", text)
+	}
+}
+
+func TestStandardFont(t *testing.T) {
+	fnt, err := vg.MakeFont("Times-Roman", 12)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	// Fonts without data are referred to by name.
+	f := newFont(fnt, "F1")
+	f.data = nil
+	if got, want := f.show("é(€)"), "[(\xe9\\(\x80\\))] TJ"; got != want {
+		t.Errorf("unexpected show operation: got:%q want:%q", got, want)
+	}
+	var d document
+	f.write(&d)
+	if got := string(d.objs[0]); !strings.Contains(got, "/Subtype /Type1 /BaseFont /Times-Roman /Encoding /WinAnsiEncoding") {
+		t.Errorf("unexpected font dictionary: %s", got)
+	}
+}
+
+func TestName(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+	}{
+		{"Helvetica", "/Helvetica"},
+		{"My Font#1", "/My#20Font#231"},
+		{"a(b)/c", "/a#28b#29#2Fc"},
+	} {
+		if got := name(test.in); got != test.want {
+			t.Errorf("unexpected name for %q: got:%q want:%q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestFallbackFont(t *testing.T) {
+	helv, err := vg.MakeFont("Helvetica", 10)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	if err := vg.AddFontData("PDFFallback", helv.Data()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := vg.SetFallbacks("PDFFallback", "Times-Roman"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fnt, err := vg.MakeFont("PDFFallback", 10)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+
+	c := New(vg.Inch, vg.Inch)
+	c.FillString(fnt, 1, 2, "aΩ")
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"PDFFallback", "Times-Roman"} {
+		if !regexp.MustCompile(`/BaseFont /[A-Z]{6}\+` + want + ` /Encoding`).Match(buf.Bytes()) {
+			t.Errorf("missing %s font in document", want)
+		}
+	}
+	var content string
+	for _, s := range streams(t, buf.Bytes()) {
+		if strings.Contains(s, "BT\n") {
+			content = s
+		}
+	}
+	want := fmt.Sprintf("/F1 10 Tf\n1 2 Td\n[<%04X>] TJ\nET\nBT\n/F2 10 Tf\n%.*g 2 Td\n",
+		helv.Font().Index('a'), pr, (1 + helv.Width("a")).Points())
+	if !strings.Contains(content, want) {
+		t.Errorf("unexpected text operations:\n%s", content)
+	}
+}
+
+func TestDrawImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.NRGBA{R: 0xff, A: 0xff})
+	img.Set(1, 0, color.NRGBA{B: 0xff, A: 0x80})
+	c := New(vg.Inch, vg.Inch)
+	c.DrawImage(10, 20, 30, 40, img, vg.Bilinear)
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pdf := buf.String()
+
+	for _, want := range []string{
+		"/XObject << /Im1 ",
+		"/Subtype /Image /Width 2 /Height 1 /BitsPerComponent 8 /Interpolate true /ColorSpace /DeviceGray",
+		"/Interpolate true /SMask ",
+		"/ColorSpace /DeviceRGB",
+	} {
+		if !strings.Contains(pdf, want) {
+			t.Errorf("missing %q in document", want)
+		}
+	}
+	var content string
+	var rgb, alpha bool
+	for _, s := range streams(t, buf.Bytes()) {
+		switch s {
+		case "\xff\x00\x00\x00\x00\xff":
+			rgb = true
+		case "\xff\x80":
+			alpha = true
+		default:
+			if strings.Contains(s, " Do\n") {
+				content = s
+			}
+		}
+	}
+	if !rgb || !alpha {
+		t.Errorf("missing image data: rgb=%t alpha=%t", rgb, alpha)
+	}
+	if !strings.Contains(content, "q\n30 0 0 40 10 20 cm\n/Im1 Do\nQ\n") {
+		t.Errorf("missing image operations in content stream:\n%s", content)
+	}
+}
+
+func TestDocument(t *testing.T) {
+	fnt, err := vg.MakeFont("Helvetica", 12)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	d := Document{
+		Title:   "Report",
+		Author:  "Gonum Ünited",
+		Created: time.Date(2015, 6, 7, 8, 9, 10, 0, time.FixedZone("", -(5*3600+30*60))),
+	}
+	c1 := d.AddPage(3*vg.Inch, 2*vg.Inch, "First")
+	c1.FillString(fnt, 10, 10, "a")
+	d.AddPage(4*vg.Inch, 5*vg.Inch, "")
+	c3 := d.AddPage(vg.Inch, vg.Inch, "Third")
+	c3.FillString(fnt, 10, 10, "b")
+
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pdf := buf.String()
+
+	for _, want := range []string{
+		"/Type /Pages /Kids [",
+		"/Count 3 >>",
+		"/MediaBox [0 0 216 144]",
+		"/MediaBox [0 0 288 360]",
+		"/MediaBox [0 0 72 72]",
+		"/PageMode /UseOutlines",
+		"/Type /Outlines",
+		"/Title (First) /Parent",
+		"/Title (Third) /Parent",
+		"/Title (Report)",
+		"/Author <FEFF0047006F006E0075006D002000DC006E0069007400650064>",
+		"/CreationDate (D:20150607080910-05'30')",
+	} {
+		if !strings.Contains(pdf, want) {
+			t.Errorf("missing %q in document", want)
+		}
+	}
+	if n := strings.Count(pdf, "/Type /Page "); n != 3 {
+		t.Errorf("unexpected number of pages: got:%d want:3", n)
+	}
+	if n := strings.Count(pdf, "/Subtype /Type0"); n != 1 {
+		t.Errorf("unexpected number of embedded fonts: got:%d want:1", n)
+	}
+	if n := strings.Count(pdf, "/Dest ["); n != 2 {
+		t.Errorf("unexpected number of outline entries: got:%d want:2", n)
+	}
+
+	var empty Document
+	if _, err := empty.WriteTo(&buf); err == nil {
+		t.Error("expected error writing empty document")
+	}
+}
+
+func TestLineStyle(t *testing.T) {
+	c := New(vg.Inch, vg.Inch)
+	if s := c.buf.String(); strings.Contains(s, " J\n") || strings.Contains(s, " j\n") || strings.Contains(s, " M\n") {
+		t.Errorf("unexpected line style in initial content stream:\n%s", s)
+	}
+
+	c.buf.Reset()
+	c.SetLineCap(vg.RoundCap)
+	c.SetLineCap(vg.RoundCap)
+	c.Push()
+	c.SetLineJoin(vg.BevelJoin)
+	c.SetMiterLimit(2.5)
+	c.Pop()
+	c.SetLineJoin(vg.BevelJoin)
+	c.SetLineCap(-1)
+	c.SetLineJoin(vg.LineJoin(10))
+	c.SetMiterLimit(vg.DefaultMiterLimit)
+
+	want := "1 J\nq\n2 j\n2.5 M\nQ\n2 j\n0 J\n0 j\n"
+	if got := c.buf.String(); got != want {
+		t.Errorf("unexpected content stream:\ngot: %q\nwant:%q", got, want)
+	}
+}
@@ -9,13 +9,19 @@ package vgsvg
 import (
 	"bufio"
 	"bytes"
+	"encoding/base64"
+	"encoding/xml"
 	"fmt"
+	"image"
 	"image/color"
+	"image/png"
 	"io"
 	"math"
 
+	"code.google.com/p/freetype-go/freetype/truetype"
 	svgo "github.com/ajstarks/svgo"
 	"github.com/gonum/plot/vg"
+	"github.com/gonum/plot/vg/internal/ttf"
 )
 
 const (
@@ -34,6 +40,27 @@ type Canvas struct {
 	buf  *bytes.Buffer
 	ht   float64
 	stk  []context
+
+	// hdr is the length of the opening
+	// svg element at the start of buf.
+	hdr int
+
+	// fonts holds the embedded fonts,
+	// keyed by font name.
+	fonts     map[string]*svgFont
+	fontOrder []string
+}
+
+// svgFont is a font embedded in the document.
+type svgFont struct {
+	// style is the CSS style selecting the font.
+	style string
+	data  []byte
+	font  *truetype.Font
+
+	// glyphs is the set of glyphs that
+	// have been shown.
+	glyphs map[truetype.Index]bool
 }
 
 type context struct {
@@ -41,6 +68,9 @@ type context struct {
 	dashArray  []vg.Length
 	dashOffset vg.Length
 	lineWidth  vg.Length
+	lineCap    vg.LineCap
+	lineJoin   vg.LineJoin
+	miterLimit float64
 	gEnds      int
 }
 
@@ -53,6 +83,8 @@ func New(w, h vg.Length) *Canvas {
 		buf: buf,
 		ht:  w.Points(),
 		stk: []context{context{}},
+
+		fonts: make(map[string]*svgFont),
 	}
 
 	// This is like svg.Start, except it uses floats
@@ -65,11 +97,16 @@ func New(w, h vg.Length) *Canvas {
 		pr, w/vg.Inch,
 		pr, h/vg.Inch,
 	)
+	c.hdr = buf.Len()
 
-	// Swap the origin to the bottom left.
+	// Swap the origin to the bottom left and set
+	// the initial miter limit, which is inherited
+	// by every path so that strokes need only give
+	// a limit that differs from it.
 	// This must be matched with a </g> when saving,
 	// before the closing </svg>.
-	c.svg.Gtransform(fmt.Sprintf("scale(1, -1) translate(0, -%.*g)", pr, h.Dots(c)))
+	fmt.Fprintf(buf, "<g transform=\"scale(1, -1) translate(0, -%.*g)\" style=\"stroke-miterlimit:%.*g\">\n",
+		pr, h.Dots(c), pr, float64(vg.DefaultMiterLimit))
 
 	vg.Initialize(c)
 	return c
@@ -92,6 +129,24 @@ func (c *Canvas) SetLineDash(dashes []vg.Length, offs vg.Length) {
 	c.cur().dashOffset = offs
 }
 
+func (c *Canvas) SetLineCap(cap vg.LineCap) {
+	if cap < vg.ButtCap || cap > vg.SquareCap {
+		cap = vg.ButtCap
+	}
+	c.cur().lineCap = cap
+}
+
+func (c *Canvas) SetLineJoin(join vg.LineJoin) {
+	if join < vg.MiterJoin || join > vg.BevelJoin {
+		join = vg.MiterJoin
+	}
+	c.cur().lineJoin = join
+}
+
+func (c *Canvas) SetMiterLimit(limit float64) {
+	c.cur().miterLimit = limit
+}
+
 func (c *Canvas) SetColor(clr color.Color) {
 	c.cur().color = clr
 }
@@ -135,9 +190,29 @@ func (c *Canvas) Stroke(path vg.Path) {
 			elm("stroke-opacity", "1", opacityString(c.cur().color)),
 			elm("stroke-width", "1", "%.*g", pr, c.cur().lineWidth.Dots(c)),
 			elm("stroke-dasharray", "none", dashArrayString(c)),
-			elm("stroke-dashoffset", "0", "%.*g", pr, c.cur().dashOffset.Dots(c))))
+			elm("stroke-dashoffset", "0", "%.*g", pr, c.cur().dashOffset.Dots(c)),
+			elm("stroke-linecap", "butt", lineCaps[c.cur().lineCap]),
+			elm("stroke-linejoin", "miter", lineJoins[c.cur().lineJoin]),
+			miterLimitString(c)))
 }
 
+// miterLimitString returns the stroke-miterlimit
+// style element, or the empty string if the limit
+// is unused or the one set at the root of the image.
+func miterLimitString(c *Canvas) string {
+	if c.cur().lineJoin != vg.MiterJoin {
+		return ""
+	}
+	return elm("stroke-miterlimit", fmt.Sprintf("%.*g", pr, float64(vg.DefaultMiterLimit)), "%.*g", pr, c.cur().miterLimit)
+}
+
+// lineCaps and lineJoins are the SVG names
+// of the line caps and joins.
+var (
+	lineCaps  = [...]string{vg.ButtCap: "butt", vg.RoundCap: "round", vg.SquareCap: "square"}
+	lineJoins = [...]string{vg.MiterJoin: "miter", vg.RoundJoin: "round", vg.BevelJoin: "bevel"}
+)
+
 func (c *Canvas) Fill(path vg.Path) {
 	c.svg.Path(c.pathData(path),
 		style(elm("fill", "#000000", colorString(c.cur().color)),
@@ -245,9 +320,36 @@ func large(a float64) int {
 }
 
 func (c *Canvas) FillString(font vg.Font, x, y vg.Length, str string) {
+	for _, run := range font.Runs(str) {
+		c.fillString(run.Font, x+run.Offset, y, run.Text)
+	}
+}
+
+// fillString draws str using the font, without using its fallbacks.
+func (c *Canvas) fillString(font vg.Font, x, y vg.Length, str string) {
+	data := font.Data()
 	fontStr, ok := fontMap[font.Name()]
 	if !ok {
-		panic(fmt.Sprintf("Unknown font: %s", font.Name()))
+		if data == nil {
+			panic(fmt.Sprintf("Unknown font: %s", font.Name()))
+		}
+		fontStr = "font-family:'" + font.Name() + "';font-weight:normal;font-style:normal"
+	}
+	if data != nil {
+		f, ok := c.fonts[font.Name()]
+		if !ok {
+			f = &svgFont{
+				style:  fontStr,
+				data:   data,
+				font:   font.Font(),
+				glyphs: make(map[truetype.Index]bool),
+			}
+			c.fonts[font.Name()] = f
+			c.fontOrder = append(c.fontOrder, font.Name())
+		}
+		for _, r := range str {
+			f.glyphs[f.font.Index(r)] = true
+		}
 	}
 	sty := style(fontStr,
 		elm("font-size", "medium", "%.*gpt", pr, font.Size.Points()),
@@ -255,8 +357,36 @@ func (c *Canvas) FillString(font vg.Font, x, y vg.Length, str string) {
 	if sty != "" {
 		sty = "\n\t" + sty
 	}
-	fmt.Fprintf(c.buf, `<text x="%.*g" y="%.*g" transform="scale(1, -1)"%s>%s</text>`+"\n",
-		pr, x.Dots(c), pr, -y.Dots(c), sty, str)
+	fmt.Fprintf(c.buf, `<text x="%.*g" y="%.*g" transform="scale(1, -1)"%s>`,
+		pr, x.Dots(c), pr, -y.Dots(c), sty)
+	xml.EscapeText(c.buf, []byte(str))
+	c.buf.WriteString("</text>\n")
+}
+
+// faceTables are the TrueType tables kept in embedded fonts.
+// Browsers require the OS/2, name and post tables as well as
+// the tables needed to render the glyphs.
+var faceTables = map[string]bool{
+	"OS/2": true, "cmap": true, "cvt ": true, "fpgm": true, "glyf": true,
+	"head": true, "hhea": true, "hmtx": true, "loca": true, "maxp": true,
+	"name": true, "post": true, "prep": true,
+}
+
+// face returns a CSS @font-face rule embedding the TrueType
+// font data for text drawn with the font. Only the glyphs
+// that have been shown are embedded, unless the font data
+// cannot be subset, in which case the whole font is embedded.
+func (f *svgFont) face() string {
+	idx := make([]truetype.Index, 0, len(f.glyphs))
+	for i := range f.glyphs {
+		idx = append(idx, i)
+	}
+	data, err := ttf.Subset(f.data, idx, faceTables)
+	if err != nil {
+		data = f.data
+	}
+	return "@font-face{" + f.style + ";src:url(data:font/ttf;base64," +
+		base64.StdEncoding.EncodeToString(data) + ") format('truetype')}"
 }
 
 var (
@@ -278,16 +408,53 @@ var (
 	}
 )
 
+// DrawImage implements the vg.Canvas.DrawImage method.
+// The image is embedded in the document as a PNG.
+func (c *Canvas) DrawImage(x, y, w, h vg.Length, img image.Image, interp vg.Interpolation) {
+	if img.Bounds().Empty() {
+		return
+	}
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode image: %v", err))
+	}
+	sty := ""
+	if interp == vg.NearestNeighbor {
+		sty = "\n\t" + style("image-rendering:optimizeSpeed", "image-rendering:pixelated")
+	}
+	fmt.Fprintf(c.buf, `<image x="%.*g" y="%.*g" width="%.*g" height="%.*g" transform="scale(1, -1)" preserveAspectRatio="none"%s
+	xlink:href="data:image/png;base64,%s"/>`+"\n",
+		pr, x.Dots(c), pr, -(y + h).Dots(c), pr, w.Dots(c), pr, h.Dots(c), sty,
+		base64.StdEncoding.EncodeToString(buf.Bytes()))
+}
+
 func (c *Canvas) DPI() float64 {
 	return dpi
 }
 
 // WriteTo writes the canvas to an io.Writer.
+// Fonts with TrueType data available are embedded
+// in the document using CSS @font-face rules, with
+// only the glyphs that have been shown.
 func (c *Canvas) WriteTo(w io.Writer) (int64, error) {
 	b := bufio.NewWriter(w)
-	n, err := c.buf.WriteTo(b)
-	if err != nil {
-		return n, err
+	var defs bytes.Buffer
+	if len(c.fontOrder) > 0 {
+		defs.WriteString("<defs>\n<style type=\"text/css\"><![CDATA[\n")
+		for _, name := range c.fontOrder {
+			defs.WriteString(c.fonts[name].face() + "\n")
+		}
+		defs.WriteString("]]></style>\n</defs>\n")
+	}
+
+	var n int64
+	for _, p := range [][]byte{c.buf.Bytes()[:c.hdr], defs.Bytes(), c.buf.Bytes()[c.hdr:]} {
+		m, err := b.Write(p)
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
 	}
 
 	// Close the groups and svg in the output buffer
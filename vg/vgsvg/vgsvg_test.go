@@ -0,0 +1,162 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgsvg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"code.google.com/p/freetype-go/freetype/truetype"
+	"github.com/gonum/plot/vg"
+)
+
+func TestEmbedFont(t *testing.T) {
+	fnt, err := vg.MakeFont("Helvetica-Bold", 12)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	c := New(vg.Inch, vg.Inch)
+	c.FillString(fnt, 0, 0, "a<b & c")
+	c.FillString(fnt, 0, 20, "d")
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svg := buf.String()
+
+	const prefix = "@font-face{font-family:Helvetica;font-weight:bold;font-style:normal;src:url(data:font/ttf;base64,"
+	if n := strings.Count(svg, "@font-face"); n != 1 {
+		t.Fatalf("unexpected number of font faces: got:%d want:1", n)
+	}
+	i := strings.Index(svg, prefix)
+	if i < 0 {
+		t.Fatalf("missing font face in document")
+	}
+	enc := svg[i+len(prefix):]
+	enc = enc[:strings.Index(enc, ")")]
+	data, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil {
+		t.Fatalf("failed to decode font data: %v", err)
+	}
+	if len(data) >= len(fnt.Data())/2 {
+		t.Errorf("embedded font not subset: got length %d of %d", len(data), len(fnt.Data()))
+	}
+	sub, err := truetype.Parse(data)
+	if err != nil {
+		t.Fatalf("failed to parse embedded font: %v", err)
+	}
+	orig := fnt.Font()
+	g := truetype.NewGlyphBuf()
+	for _, r := range "abcdz" {
+		if err := g.Load(sub, orig.FUnitsPerEm(), orig.Index(r), nil); err != nil {
+			t.Fatalf("failed to load glyph for %q: %v", r, err)
+		}
+		if shown := r != 'z'; shown != (len(g.Point) != 0) {
+			t.Errorf("unexpected outline for %q: shown:%t points:%d", r, shown, len(g.Point))
+		}
+	}
+	if !strings.Contains(svg, ">a&lt;b &amp; c</text>") {
+		t.Error("text not escaped")
+	}
+
+	// The document must remain well formed.
+	d := xml.NewDecoder(&buf)
+	for {
+		_, err := d.Token()
+		if err != nil {
+			if err.Error() != "EOF" {
+				t.Errorf("invalid document: %v", err)
+			}
+			break
+		}
+	}
+}
+
+func TestDrawImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.NRGBA{R: 0xff, A: 0xff})
+	img.Set(1, 0, color.NRGBA{B: 0xff, A: 0x80})
+	for _, test := range []struct {
+		interp    vg.Interpolation
+		pixelated bool
+	}{
+		{interp: vg.NearestNeighbor, pixelated: true},
+		{interp: vg.Bilinear, pixelated: false},
+	} {
+		c := New(vg.Inch, vg.Inch)
+		c.DrawImage(10, 20, 30, 40, img, test.interp)
+		var buf bytes.Buffer
+		if _, err := c.WriteTo(&buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		svg := buf.String()
+
+		const attrs = `<image x="12.5" y="-75" width="37.5" height="50" transform="scale(1, -1)" preserveAspectRatio="none"`
+		if !strings.Contains(svg, attrs) {
+			t.Errorf("missing image attributes for %v:\n%s", test.interp, svg)
+		}
+		if got := strings.Contains(svg, "image-rendering:pixelated"); got != test.pixelated {
+			t.Errorf("unexpected pixelated rendering for %v: got:%t want:%t", test.interp, got, test.pixelated)
+		}
+
+		const prefix = `xlink:href="data:image/png;base64,`
+		i := strings.Index(svg, prefix)
+		if i < 0 {
+			t.Fatalf("missing image data for %v", test.interp)
+		}
+		enc := svg[i+len(prefix):]
+		enc = enc[:strings.Index(enc, `"`)]
+		data, err := base64.StdEncoding.DecodeString(enc)
+		if err != nil {
+			t.Fatalf("failed to decode image data: %v", err)
+		}
+		got, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("failed to decode png: %v", err)
+		}
+		for x := 0; x < 2; x++ {
+			if g, w := color.NRGBAModel.Convert(got.At(x, 0)), img.At(x, 0); g != w {
+				t.Errorf("unexpected pixel %d: got:%v want:%v", x, g, w)
+			}
+		}
+	}
+}
+
+func TestLineStyle(t *testing.T) {
+	c := New(vg.Inch, vg.Inch)
+	var p vg.Path
+	p.Move(0, 0)
+	p.Line(10, 10)
+	c.Stroke(p)
+	c.SetLineCap(vg.RoundCap)
+	c.SetLineJoin(vg.BevelJoin)
+	c.SetMiterLimit(2.5)
+	c.Stroke(p)
+	c.SetLineCap(-1)
+	c.SetLineJoin(vg.MiterJoin)
+	c.Stroke(p)
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svg := buf.String()
+
+	for _, want := range []string{
+		`<g transform="scale(1, -1) translate(0, -90)" style="stroke-miterlimit:10">`,
+		`style="fill:none;stroke:#000000;stroke-width:1.25" />`,
+		`style="fill:none;stroke:#000000;stroke-width:1.25;stroke-linecap:round;stroke-linejoin:bevel" />`,
+		`style="fill:none;stroke:#000000;stroke-width:1.25;stroke-miterlimit:2.5" />`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("missing %q in document:\n%s", want, svg)
+		}
+	}
+}
@@ -0,0 +1,483 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgterm
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/gonum/plot/vg"
+)
+
+// affine is a 2D affine transform mapping (x, y)
+// to (a*x + c*y + e, b*x + d*y + f).
+type affine struct {
+	a, b, c, d, e, f float64
+}
+
+var identity = affine{a: 1, d: 1}
+
+// mul returns the transform that applies n and then m.
+func (m affine) mul(n affine) affine {
+	return affine{
+		a: m.a*n.a + m.c*n.b,
+		b: m.b*n.a + m.d*n.b,
+		c: m.a*n.c + m.c*n.d,
+		d: m.b*n.c + m.d*n.d,
+		e: m.a*n.e + m.c*n.f + m.e,
+		f: m.b*n.e + m.d*n.f + m.f,
+	}
+}
+
+// invert returns the inverse of the transform, and
+// false if the transform is not invertible.
+func (m affine) invert() (affine, bool) {
+	det := m.a*m.d - m.b*m.c
+	if det == 0 {
+		return affine{}, false
+	}
+	return affine{
+		a: m.d / det,
+		b: -m.b / det,
+		c: -m.c / det,
+		d: m.a / det,
+		e: (m.c*m.f - m.d*m.e) / det,
+		f: (m.b*m.e - m.a*m.f) / det,
+	}, true
+}
+
+// point is a location in dot coordinates, with the
+// origin at the top left of the canvas.
+type point struct {
+	x, y float64
+}
+
+// toDots returns the dot coordinates of the point (x, y)
+// transformed by m.
+func (c *Canvas) toDots(m affine, x, y float64) point {
+	tx := m.a*x + m.c*y + m.e
+	ty := m.b*x + m.d*y + m.f
+	return point{x: tx * c.sx, y: (float64(c.h) - ty) * c.sy}
+}
+
+// arcSegments is the number of line segments
+// used to approximate a full circle.
+const arcSegments = 32
+
+// flatten returns the subpaths of p as polylines
+// in dot coordinates.
+func (c *Canvas) flatten(p vg.Path) [][]point {
+	m := c.cur().m
+	var subs [][]point
+	var sub []point
+	for _, comp := range p {
+		switch comp.Type {
+		case vg.MoveComp:
+			if len(sub) > 0 {
+				subs = append(subs, sub)
+			}
+			sub = []point{c.toDots(m, float64(comp.X), float64(comp.Y))}
+
+		case vg.LineComp:
+			sub = append(sub, c.toDots(m, float64(comp.X), float64(comp.Y)))
+
+		case vg.ArcComp:
+			n := int(math.Ceil(math.Abs(comp.Angle) / (2 * math.Pi) * arcSegments))
+			if n < 1 {
+				n = 1
+			}
+			for i := 0; i <= n; i++ {
+				a := comp.Start + comp.Angle*float64(i)/float64(n)
+				x := float64(comp.X) + float64(comp.Radius)*math.Cos(a)
+				y := float64(comp.Y) + float64(comp.Radius)*math.Sin(a)
+				sub = append(sub, c.toDots(m, x, y))
+			}
+
+		case vg.CloseComp:
+			if len(sub) > 0 {
+				start := sub[0]
+				subs = append(subs, append(sub, start))
+				sub = []point{start}
+			}
+
+		default:
+			panic(fmt.Sprintf("vgterm: unknown path component: %d", comp.Type))
+		}
+	}
+	if len(sub) > 1 {
+		subs = append(subs, sub)
+	}
+	return subs
+}
+
+// dash splits the polyline sub into the dashes described by
+// the dash pattern d starting at offs. If d is empty or has no
+// length, sub is returned unchanged.
+func dash(sub []point, d []float64, offs float64) [][]point {
+	var total float64
+	for _, v := range d {
+		total += v
+	}
+	if len(d) == 0 || total <= 0 {
+		return [][]point{sub}
+	}
+
+	i, on, rem := 0, true, d[0]
+	offs = math.Mod(offs, total)
+	if offs < 0 {
+		offs += total
+	}
+	for offs > 0 {
+		if offs < rem {
+			rem -= offs
+			break
+		}
+		offs -= rem
+		i = (i + 1) % len(d)
+		on = !on
+		rem = d[i]
+	}
+
+	var dashes [][]point
+	var cur []point
+	if on {
+		cur = []point{sub[0]}
+	}
+	for k := 1; k < len(sub); k++ {
+		p0, p1 := sub[k-1], sub[k]
+		l := math.Hypot(p1.x-p0.x, p1.y-p0.y)
+		pos := 0.0
+		for l-pos > rem {
+			pos += rem
+			t := pos / l
+			q := point{x: p0.x + t*(p1.x-p0.x), y: p0.y + t*(p1.y-p0.y)}
+			if on {
+				dashes = append(dashes, append(cur, q))
+				cur = nil
+			} else {
+				cur = []point{q}
+			}
+			on = !on
+			i = (i + 1) % len(d)
+			rem = d[i]
+		}
+		rem -= l - pos
+		if on {
+			cur = append(cur, p1)
+		}
+	}
+	if on && len(cur) > 1 {
+		dashes = append(dashes, cur)
+	}
+	return dashes
+}
+
+// line draws a line from p0 to p1 with a pen of radius r.
+func (c *Canvas) line(p0, p1 point, r float64) {
+	dx, dy := p1.x-p0.x, p1.y-p0.y
+	n := int(math.Ceil(math.Max(math.Abs(dx), math.Abs(dy))))
+	for i := 0; i <= n; i++ {
+		t := 0.0
+		if n > 0 {
+			t = float64(i) / float64(n)
+		}
+		if i == n {
+			// Pull the end back so that a line ending on
+			// a dot boundary does not set the next dot.
+			t -= 1e-9
+		}
+		c.dot(point{x: p0.x + t*dx, y: p0.y + t*dy}, r)
+	}
+}
+
+// dot draws a pen of radius r at p. Pens with a radius
+// less than one dot set a single dot.
+func (c *Canvas) dot(p point, r float64) {
+	x, y := int(math.Floor(p.x)), int(math.Floor(p.y))
+
+	// Points on the right and bottom edges of the
+	// canvas belong to the last column and row.
+	if w := c.cols * c.cellW; x == w {
+		x--
+	}
+	if h := c.rows * c.cellH; y == h {
+		y--
+	}
+
+	if r < 1 {
+		c.set(x, y)
+		return
+	}
+	ri := int(math.Ceil(r))
+	for oy := -ri; oy <= ri; oy++ {
+		for ox := -ri; ox <= ri; ox++ {
+			if float64(ox*ox+oy*oy) <= r*r {
+				c.set(x+ox, y+oy)
+			}
+		}
+	}
+}
+
+// crossing is an intersection of a path edge with a scan line.
+type crossing struct {
+	x    float64
+	wind int
+}
+
+type crossings []crossing
+
+func (c crossings) Len() int           { return len(c) }
+func (c crossings) Less(i, j int) bool { return c[i].x < c[j].x }
+func (c crossings) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+// fill fills the polygons in subs using the non-zero winding rule,
+// setting the dots whose centers lie within the polygons. Polygons
+// too small to contain a dot center set the dot at their center.
+func (c *Canvas) fill(subs [][]point) {
+	w, h := c.cols*c.cellW, c.rows*c.cellH
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, sub := range subs {
+		for _, p := range sub {
+			minX, maxX = math.Min(minX, p.x), math.Max(maxX, p.x)
+			minY, maxY = math.Min(minY, p.y), math.Max(maxY, p.y)
+		}
+	}
+	if math.IsInf(minX, 0) {
+		return
+	}
+
+	filled := false
+	var xs crossings
+	y0 := int(math.Max(0, math.Floor(minY)))
+	y1 := int(math.Min(float64(h-1), math.Ceil(maxY)))
+	for y := y0; y <= y1; y++ {
+		cy := float64(y) + 0.5
+		xs = xs[:0]
+		for _, sub := range subs {
+			for i := range sub {
+				p0, p1 := sub[i], sub[(i+1)%len(sub)]
+				if p0.y == p1.y {
+					continue
+				}
+				wind := 1
+				if p0.y > p1.y {
+					p0, p1 = p1, p0
+					wind = -1
+				}
+				if cy < p0.y || cy >= p1.y {
+					continue
+				}
+				x := p0.x + (cy-p0.y)*(p1.x-p0.x)/(p1.y-p0.y)
+				xs = append(xs, crossing{x: x, wind: wind})
+			}
+		}
+		sort.Sort(xs)
+
+		var wind int
+		var start float64
+		for _, x := range xs {
+			prev := wind
+			wind += x.wind
+			switch {
+			case prev == 0 && wind != 0:
+				start = x.x
+			case prev != 0 && wind == 0:
+				from := int(math.Max(0, math.Ceil(start-0.5)))
+				to := int(math.Min(float64(w), math.Ceil(x.x-0.5)))
+				for i := from; i < to; i++ {
+					c.set(i, y)
+					filled = true
+				}
+			}
+		}
+	}
+
+	if !filled {
+		c.dot(point{x: (minX + maxX) / 2, y: (minY + maxY) / 2}, 0)
+	}
+}
+
+// set sets the dot at (x, y) to the current color. Dots
+// drawn in a color close to the background are cleared
+// and dots drawn in a mostly transparent color are left
+// unchanged.
+func (c *Canvas) set(x, y int) {
+	w, h := c.cols*c.cellW, c.rows*c.cellH
+	if x < 0 || x >= w || y < 0 || y >= h {
+		return
+	}
+	clr := c.cur().color
+	switch {
+	case clr.A < 0x80:
+		return
+	case near(clr, c.background):
+		c.dots[y*w+x] = color.NRGBA{}
+	default:
+		clr.A = 0xff
+		c.dots[y*w+x] = clr
+	}
+}
+
+// near returns whether the colors a and b are
+// indistinguishable on the terminal.
+func near(a, b color.NRGBA) bool {
+	d := func(x, y uint8) int {
+		if x > y {
+			return int(x - y)
+		}
+		return int(y - x)
+	}
+	return d(a.R, b.R)+d(a.G, b.G)+d(a.B, b.B) <= 24
+}
+
+// brailleBits holds the bit for each dot of a braille
+// character indexed by row and then column.
+var brailleBits = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// cell returns the character and the foreground and background
+// colors for the cell at (col, row). Transparent colors indicate
+// the terminal default.
+func (c *Canvas) cell(col, row int) (r rune, fg, bg color.NRGBA) {
+	if t := c.text[row*c.cols+col]; t.r != 0 {
+		return t.r, t.color, color.NRGBA{}
+	}
+
+	w := c.cols * c.cellW
+	x0, y0 := col*c.cellW, row*c.cellH
+	switch c.mode {
+	case Braille:
+		var (
+			bits   rune
+			colors [8]color.NRGBA
+			n      int
+		)
+		for dy := 0; dy < 4; dy++ {
+			for dx := 0; dx < 2; dx++ {
+				if d := c.dots[(y0+dy)*w+x0+dx]; d.A != 0 {
+					bits |= brailleBits[dy][dx]
+					colors[n] = d
+					n++
+				}
+			}
+		}
+		if bits == 0 {
+			return ' ', color.NRGBA{}, color.NRGBA{}
+		}
+		return 0x2800 + bits, dominant(colors[:n]), color.NRGBA{}
+
+	case Blocks:
+		top, bottom := c.dots[y0*w+x0], c.dots[(y0+1)*w+x0]
+		switch {
+		case top.A == 0 && bottom.A == 0:
+			return ' ', color.NRGBA{}, color.NRGBA{}
+		case bottom.A == 0:
+			return '▀', top, color.NRGBA{}
+		case top.A == 0:
+			return '▄', bottom, color.NRGBA{}
+		case top == bottom || c.colors == NoColor:
+			return '█', top, color.NRGBA{}
+		default:
+			return '▀', top, bottom
+		}
+
+	default:
+		panic(fmt.Sprintf("vgterm: unknown mode: %d", c.mode))
+	}
+}
+
+// dominant returns the most frequent color in colors,
+// preferring the earliest in case of a tie.
+func dominant(colors []color.NRGBA) color.NRGBA {
+	var best color.NRGBA
+	max := 0
+	for i, c := range colors {
+		n := 0
+		for _, o := range colors[i:] {
+			if o == c {
+				n++
+			}
+		}
+		if n > max {
+			best, max = c, n
+		}
+	}
+	return best
+}
+
+// writeColor writes an escape sequence to buf that resets the
+// terminal attributes and sets the given foreground and background
+// colors. Transparent colors leave the terminal default in place.
+func (c *Canvas) writeColor(buf *bytes.Buffer, fg, bg color.NRGBA) {
+	buf.WriteString("\x1b[0")
+	for _, v := range []struct {
+		code  int
+		color color.NRGBA
+	}{
+		{code: 38, color: fg},
+		{code: 48, color: bg},
+	} {
+		if v.color.A == 0 {
+			continue
+		}
+		switch c.colors {
+		case Color256:
+			fmt.Fprintf(buf, ";%d;5;%d", v.code, ansi256(v.color))
+		case TrueColor:
+			fmt.Fprintf(buf, ";%d;2;%d;%d;%d", v.code, v.color.R, v.color.G, v.color.B)
+		}
+	}
+	buf.WriteByte('m')
+}
+
+// cubeLevels are the intensities of the 6×6×6 color
+// cube in the ANSI 256 color palette.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// ansi256 returns the index of the ANSI 256 color palette
+// entry closest to clr, considering the color cube and the
+// grayscale ramp.
+func ansi256(clr color.NRGBA) int {
+	level := func(v uint8) int {
+		switch {
+		case v < 48:
+			return 0
+		case v < 115:
+			return 1
+		default:
+			return (int(v) - 35) / 40
+		}
+	}
+	dist := func(r, g, b int) int {
+		dr, dg, db := int(clr.R)-r, int(clr.G)-g, int(clr.B)-b
+		return dr*dr + dg*dg + db*db
+	}
+
+	r, g, b := level(clr.R), level(clr.G), level(clr.B)
+	cube := 16 + 36*r + 6*g + b
+	cubeDist := dist(cubeLevels[r], cubeLevels[g], cubeLevels[b])
+
+	avg := (int(clr.R) + int(clr.G) + int(clr.B)) / 3
+	gray := (avg - 3) / 10
+	switch {
+	case avg < 3:
+		gray = 0
+	case gray > 23:
+		gray = 23
+	}
+	v := 8 + 10*gray
+	if dist(v, v, v) < cubeDist {
+		return 232 + gray
+	}
+	return cube
+}
@@ -0,0 +1,396 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package vgterm implements the vg.Canvas interface by rasterising
+// to a grid of character cells that can be written to a terminal.
+//
+// Graphics are drawn using Unicode braille characters, giving
+// 2×4 dots per cell, or half block characters, giving 1×2 dots
+// per cell. Colors are written using ANSI 256 color or 24 bit
+// color escape sequences. Text is placed in the cells nearest
+// to its position on the canvas.
+package vgterm
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"unicode/utf8"
+
+	"github.com/gonum/plot/vg"
+)
+
+// Mode specifies the characters used to draw graphics.
+type Mode int
+
+const (
+	// Braille draws graphics using braille
+	// characters with 2×4 dots per cell.
+	Braille Mode = iota
+
+	// Blocks draws graphics using half block
+	// characters with 1×2 dots per cell.
+	Blocks
+)
+
+// ColorMode specifies the color escape sequences
+// written to the terminal.
+type ColorMode int
+
+const (
+	// NoColor writes plain text without any
+	// escape sequences.
+	NoColor ColorMode = iota
+
+	// Color256 writes colors using the ANSI
+	// 256 color palette.
+	Color256
+
+	// TrueColor writes colors using 24 bit
+	// color escape sequences.
+	TrueColor
+)
+
+// DefaultColumnsPerInch is the number of character columns
+// used for each inch of canvas width if the number of columns
+// is not specified. A cell is assumed to be twice as tall as
+// it is wide, so a 4×4 inch canvas is drawn using 80×40 cells.
+const DefaultColumnsPerInch = 20
+
+// Canvas implements the vg.Canvas interface,
+// drawing to a grid of character cells.
+type Canvas struct {
+	w, h vg.Length
+
+	mode       Mode
+	colors     ColorMode
+	background color.NRGBA
+
+	// cols and rows are the dimensions of
+	// the canvas in character cells.
+	cols, rows int
+
+	// cellW and cellH are the dimensions
+	// of a cell in dots.
+	cellW, cellH int
+
+	// sx and sy are the number of dots
+	// per point in each direction.
+	sx, sy float64
+
+	// dots holds the color of each dot in
+	// row major order starting at the top
+	// left. Unset dots are transparent.
+	dots []color.NRGBA
+
+	// text holds the text drawn in each cell.
+	text []textCell
+
+	stk []ctx
+}
+
+type ctx struct {
+	color  color.NRGBA
+	width  vg.Length
+	dashes []vg.Length
+	offs   vg.Length
+	m      affine
+}
+
+// textCell is a character of text drawn in a cell.
+type textCell struct {
+	r     rune
+	color color.NRGBA
+}
+
+// New returns a new terminal canvas with the given size
+// using braille characters and ANSI 256 color escapes.
+func New(w, h vg.Length) *Canvas {
+	return NewWith(w, h)
+}
+
+// NewWith returns a new terminal canvas with the given size
+// created according to the specified options. The currently
+// accepted options are UseMode, UseColors, UseColumns and
+// UseBackground.
+func NewWith(w, h vg.Length, o ...option) *Canvas {
+	if w <= 0 || h <= 0 {
+		panic("w and h must both be > 0.")
+	}
+	c := &Canvas{
+		w:          w,
+		h:          h,
+		mode:       Braille,
+		colors:     Color256,
+		background: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		stk:        []ctx{{m: identity}},
+	}
+	for _, opt := range o {
+		opt(c)
+	}
+	if c.cols == 0 {
+		c.cols = int(float64(w/vg.Inch)*DefaultColumnsPerInch + 0.5)
+		if c.cols < 1 {
+			c.cols = 1
+		}
+	}
+	cellW := float64(w) / float64(c.cols)
+	c.rows = int(float64(h)/(2*cellW) + 0.5)
+	if c.rows < 1 {
+		c.rows = 1
+	}
+	switch c.mode {
+	case Braille:
+		c.cellW, c.cellH = 2, 4
+	case Blocks:
+		c.cellW, c.cellH = 1, 2
+	default:
+		panic(fmt.Sprintf("vgterm: unknown mode: %d", c.mode))
+	}
+	c.sx = float64(c.cols*c.cellW) / float64(w)
+	c.sy = float64(c.rows*c.cellH) / float64(h)
+	c.dots = make([]color.NRGBA, c.cols*c.cellW*c.rows*c.cellH)
+	c.text = make([]textCell, c.cols*c.rows)
+	vg.Initialize(c)
+	return c
+}
+
+type option func(*Canvas)
+
+// UseMode specifies the characters used to draw graphics.
+// The default is Braille.
+func UseMode(m Mode) option {
+	return func(c *Canvas) {
+		c.mode = m
+	}
+}
+
+// UseColors specifies the color escape sequences used.
+// The default is Color256.
+func UseColors(m ColorMode) option {
+	return func(c *Canvas) {
+		c.colors = m
+	}
+}
+
+// UseColumns specifies the width of the canvas in
+// character columns. The number of rows is chosen
+// to preserve the aspect ratio of the canvas.
+func UseColumns(n int) option {
+	if n <= 0 {
+		panic("number of columns must be > 0.")
+	}
+	return func(c *Canvas) {
+		c.cols = n
+	}
+}
+
+// UseBackground specifies the background color of the
+// terminal. Drawing in a color close to the background
+// color clears the dots drawn. The default is white.
+func UseBackground(clr color.Color) option {
+	return func(c *Canvas) {
+		c.background = color.NRGBAModel.Convert(clr).(color.NRGBA)
+	}
+}
+
+// Size returns the size of the canvas.
+func (c *Canvas) Size() (w, h vg.Length) {
+	return c.w, c.h
+}
+
+// Cells returns the size of the canvas in character cells.
+func (c *Canvas) Cells() (cols, rows int) {
+	return c.cols, c.rows
+}
+
+// cur returns the top context on the stack.
+func (c *Canvas) cur() *ctx {
+	return &c.stk[len(c.stk)-1]
+}
+
+func (c *Canvas) SetLineWidth(w vg.Length) {
+	c.cur().width = w
+}
+
+func (c *Canvas) SetLineDash(ds []vg.Length, offs vg.Length) {
+	c.cur().dashes = append([]vg.Length(nil), ds...)
+	c.cur().offs = offs
+}
+
+// SetLineCap implements the vg.Canvas.SetLineCap method.
+// Line caps are not drawn at the resolution of a terminal,
+// so the cap is ignored.
+func (c *Canvas) SetLineCap(vg.LineCap) {}
+
+// SetLineJoin implements the vg.Canvas.SetLineJoin method.
+// Line joins are not drawn at the resolution of a terminal,
+// so the join is ignored.
+func (c *Canvas) SetLineJoin(vg.LineJoin) {}
+
+// SetMiterLimit implements the vg.Canvas.SetMiterLimit
+// method. The limit is ignored.
+func (c *Canvas) SetMiterLimit(float64) {}
+
+func (c *Canvas) SetColor(clr color.Color) {
+	if clr == nil {
+		clr = color.Black
+	}
+	c.cur().color = color.NRGBAModel.Convert(clr).(color.NRGBA)
+}
+
+func (c *Canvas) Rotate(t float64) {
+	sin, cos := math.Sincos(t)
+	c.cur().m = c.cur().m.mul(affine{a: cos, b: sin, c: -sin, d: cos})
+}
+
+func (c *Canvas) Translate(x, y vg.Length) {
+	c.cur().m = c.cur().m.mul(affine{a: 1, d: 1, e: float64(x), f: float64(y)})
+}
+
+func (c *Canvas) Scale(x, y float64) {
+	c.cur().m = c.cur().m.mul(affine{a: x, d: y})
+}
+
+func (c *Canvas) Push() {
+	top := *c.cur()
+	top.dashes = append([]vg.Length(nil), top.dashes...)
+	c.stk = append(c.stk, top)
+}
+
+func (c *Canvas) Pop() {
+	c.stk = c.stk[:len(c.stk)-1]
+}
+
+func (c *Canvas) Stroke(p vg.Path) {
+	if c.cur().width <= 0 {
+		return
+	}
+	r := float64(c.cur().width) * c.sx / 2
+	dashes := make([]float64, len(c.cur().dashes))
+	for i, d := range c.cur().dashes {
+		dashes[i] = float64(d) * c.sx
+	}
+	for _, sub := range c.flatten(p) {
+		for _, seg := range dash(sub, dashes, float64(c.cur().offs)*c.sx) {
+			if len(seg) == 1 {
+				c.dot(seg[0], r)
+			}
+			for i := 1; i < len(seg); i++ {
+				c.line(seg[i-1], seg[i], r)
+			}
+		}
+	}
+}
+
+func (c *Canvas) Fill(p vg.Path) {
+	c.fill(c.flatten(p))
+}
+
+// FillString draws text in the cells nearest to the position of
+// the text on the canvas. Text drawn with a rotation closer to
+// vertical than horizontal is written down a column.
+func (c *Canvas) FillString(font vg.Font, x, y vg.Length, str string) {
+	n := utf8.RuneCountInString(str)
+	if n == 0 {
+		return
+	}
+
+	// Anchor the text at its center so that it stays
+	// aligned however wide the terminal font is.
+	ext := font.Extents()
+	m := c.cur().m
+	mid := c.toDots(m, float64(x+font.Width(str)/2), float64(y+(ext.Ascent+ext.Descent)/2))
+	col := int(math.Floor(mid.x / float64(c.cellW)))
+	row := int(math.Floor(mid.y / float64(c.cellH)))
+
+	dc, dr := 1, 0
+	if math.Abs(m.b) > math.Abs(m.a) {
+		dc, dr = 0, 1
+	}
+	col -= dc * (n / 2)
+	row -= dr * (n / 2)
+	clr := c.cur().color
+	for _, r := range str {
+		if 0 <= col && col < c.cols && 0 <= row && row < c.rows {
+			c.text[row*c.cols+col] = textCell{r: r, color: clr}
+		}
+		col += dc
+		row += dr
+	}
+}
+
+// DrawImage draws the image by setting each dot covered by
+// the image to the color of the pixel at the center of the
+// dot. Dots are too coarse for interpolation to matter, so
+// the interpolation is ignored.
+func (c *Canvas) DrawImage(x, y, w, h vg.Length, img image.Image, _ vg.Interpolation) {
+	b := img.Bounds()
+	if b.Empty() || w == 0 || h == 0 {
+		return
+	}
+	m := c.cur().m
+	inv, ok := m.invert()
+	if !ok {
+		return
+	}
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range [...][2]vg.Length{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}} {
+		d := c.toDots(m, float64(p[0]), float64(p[1]))
+		minX, maxX = math.Min(minX, d.x), math.Max(maxX, d.x)
+		minY, maxY = math.Min(minY, d.y), math.Max(maxY, d.y)
+	}
+
+	save := c.cur().color
+	defer func() { c.cur().color = save }()
+	for py := int(math.Floor(minY)); py < int(math.Ceil(maxY)); py++ {
+		for px := int(math.Floor(minX)); px < int(math.Ceil(maxX)); px++ {
+			// Map the dot center back to the
+			// coordinates of the canvas.
+			cx := (float64(px) + 0.5) / c.sx
+			cy := float64(c.h) - (float64(py)+0.5)/c.sy
+			ux := inv.a*cx + inv.c*cy + inv.e
+			uy := inv.b*cx + inv.d*cy + inv.f
+			u := (ux - float64(x)) / float64(w)
+			v := (float64(y+h) - uy) / float64(h)
+			if u < 0 || u >= 1 || v < 0 || v >= 1 {
+				continue
+			}
+			clr := img.At(b.Min.X+int(u*float64(b.Dx())), b.Min.Y+int(v*float64(b.Dy())))
+			c.cur().color = color.NRGBAModel.Convert(clr).(color.NRGBA)
+			c.set(px, py)
+		}
+	}
+}
+
+// DPI returns the number of dots per inch across the canvas.
+func (c *Canvas) DPI() float64 {
+	return c.sx * vg.Inch.Points()
+}
+
+// WriteTo writes the canvas to w as lines of text, one line
+// for each row of cells.
+func (c *Canvas) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	for row := 0; row < c.rows; row++ {
+		var curFg, curBg color.NRGBA
+		for col := 0; col < c.cols; col++ {
+			r, fg, bg := c.cell(col, row)
+			if c.colors != NoColor && (fg != curFg || bg != curBg) {
+				c.writeColor(&buf, fg, bg)
+				curFg, curBg = fg, bg
+			}
+			buf.WriteRune(r)
+		}
+		if curFg.A != 0 || curBg.A != 0 {
+			buf.WriteString("\x1b[0m")
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.WriteTo(w)
+}
@@ -0,0 +1,203 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgterm
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/gonum/plot/vg"
+)
+
+func rect(x0, y0, x1, y1 vg.Length) vg.Path {
+	var p vg.Path
+	p.Move(x0, y0)
+	p.Line(x1, y0)
+	p.Line(x1, y1)
+	p.Line(x0, y1)
+	p.Close()
+	return p
+}
+
+func render(t *testing.T, c *Canvas) string {
+	var buf bytes.Buffer
+	_, err := c.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error writing canvas: %v", err)
+	}
+	return buf.String()
+}
+
+func TestCanvas(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		opts []option
+		draw func(c *Canvas)
+		want string
+	}{
+		{
+			name: "empty",
+			opts: []option{UseColors(NoColor)},
+			draw: func(c *Canvas) {},
+			want: "    \n    \n",
+		},
+		{
+			name: "fill braille",
+			opts: []option{UseColors(NoColor)},
+			draw: func(c *Canvas) {
+				c.Fill(rect(0, 36, 36, 72))
+			},
+			want: "⣿⣿  \n    \n",
+		},
+		{
+			name: "fill background",
+			opts: []option{UseColors(NoColor)},
+			draw: func(c *Canvas) {
+				c.Fill(rect(0, 0, 72, 72))
+				c.SetColor(color.White)
+				c.Fill(rect(0, 0, 36, 72))
+			},
+			want: "  ⣿⣿\n  ⣿⣿\n",
+		},
+		{
+			name: "fill translated",
+			opts: []option{UseColors(NoColor)},
+			draw: func(c *Canvas) {
+				c.Translate(36, -36)
+				c.Fill(rect(0, 36, 36, 72))
+			},
+			want: "    \n  ⣿⣿\n",
+		},
+		{
+			name: "stroke braille",
+			opts: []option{UseColors(NoColor)},
+			draw: func(c *Canvas) {
+				var p vg.Path
+				p.Move(0, 36)
+				p.Line(72, 36)
+				c.Stroke(p)
+			},
+			want: "    \n⠉⠉⠉⠉\n",
+		},
+		{
+			name: "stroke dashed",
+			opts: []option{UseColors(NoColor)},
+			draw: func(c *Canvas) {
+				c.SetLineDash([]vg.Length{18, 18}, 0)
+				var p vg.Path
+				p.Move(0, 36)
+				p.Line(72, 36)
+				c.Stroke(p)
+			},
+			want: "    \n⠉ ⠉ \n",
+		},
+		{
+			name: "stroke blocks",
+			opts: []option{UseMode(Blocks), UseColors(NoColor)},
+			draw: func(c *Canvas) {
+				var p vg.Path
+				p.Move(9, 0)
+				p.Line(9, 72)
+				c.Stroke(p)
+			},
+			want: "█   \n█   \n",
+		},
+		{
+			name: "blocks truecolor",
+			opts: []option{UseMode(Blocks), UseColors(TrueColor)},
+			draw: func(c *Canvas) {
+				c.SetColor(color.RGBA{R: 0xff, A: 0xff})
+				c.Fill(rect(0, 54, 18, 72))
+				c.SetColor(color.RGBA{B: 0xff, A: 0xff})
+				c.Fill(rect(0, 36, 18, 54))
+			},
+			want: "\x1b[0;38;2;255;0;0;48;2;0;0;255m▀\x1b[0m   \n    \n",
+		},
+		{
+			name: "image blocks",
+			opts: []option{UseMode(Blocks), UseColors(TrueColor)},
+			draw: func(c *Canvas) {
+				img := image.NewNRGBA(image.Rect(0, 0, 1, 2))
+				img.Set(0, 0, color.RGBA{R: 0xff, A: 0xff})
+				img.Set(0, 1, color.RGBA{B: 0xff, A: 0xff})
+				c.DrawImage(0, 36, 18, 36, img, vg.Bilinear)
+			},
+			want: "\x1b[0;38;2;255;0;0;48;2;0;0;255m▀\x1b[0m   \n    \n",
+		},
+		{
+			name: "braille 256",
+			opts: []option{UseColors(Color256)},
+			draw: func(c *Canvas) {
+				c.SetColor(color.RGBA{R: 0xff, A: 0xff})
+				c.Fill(rect(36, 0, 72, 36))
+			},
+			want: "    \n  \x1b[0;38;5;196m⣿⣿\x1b[0m\n",
+		},
+	} {
+		c := NewWith(vg.Inch, vg.Inch, append(test.opts, UseColumns(4))...)
+		if cols, rows := c.Cells(); cols != 4 || rows != 2 {
+			t.Fatalf("unexpected canvas size for %q: got:%dx%d want:4x2", test.name, cols, rows)
+		}
+		test.draw(c)
+		got := render(t, c)
+		if got != test.want {
+			t.Errorf("unexpected output for %q:\ngot:\n%q\nwant:\n%q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFillString(t *testing.T) {
+	font, err := vg.MakeFont("Times-Roman", 12)
+	if err != nil {
+		t.Fatalf("failed to create font: %v", err)
+	}
+
+	c := NewWith(4*vg.Inch, vg.Inch, UseColors(NoColor))
+	w := font.Width("text")
+	c.FillString(font, 144-w/2, 36, "text")
+	c.Push()
+	c.Translate(18, 0)
+	c.Rotate(math.Pi / 2)
+	c.FillString(font, 36-w/2, 0, "up")
+	c.Pop()
+	got := render(t, c)
+	want := "" +
+		"                                                                                \n" +
+		"                                                                                \n" +
+		"                                                                                \n" +
+		"                                                                                \n" +
+		"   u                                  text                                      \n" +
+		"   p                                                                            \n" +
+		"                                                                                \n" +
+		"                                                                                \n" +
+		"                                                                                \n" +
+		"                                                                                \n"
+	if got != want {
+		t.Errorf("unexpected text output:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestANSI256(t *testing.T) {
+	for _, test := range []struct {
+		color color.NRGBA
+		want  int
+	}{
+		{color: color.NRGBA{A: 0xff}, want: 16},
+		{color: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, want: 231},
+		{color: color.NRGBA{R: 0xff, A: 0xff}, want: 196},
+		{color: color.NRGBA{G: 0xff, A: 0xff}, want: 46},
+		{color: color.NRGBA{B: 0xff, A: 0xff}, want: 21},
+		{color: color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}, want: 244},
+		{color: color.NRGBA{R: 0x5f, G: 0x87, B: 0xaf, A: 0xff}, want: 67},
+	} {
+		got := ansi256(test.color)
+		if got != test.want {
+			t.Errorf("unexpected palette index for %v: got:%d want:%d", test.color, got, test.want)
+		}
+	}
+}
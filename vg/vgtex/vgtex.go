@@ -0,0 +1,384 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package vgtex implements the vg.Canvas interface using
+// PGF commands for LaTeX documents.
+//
+// Text is passed through to LaTeX and typeset with the fonts
+// of the document, so it may contain LaTeX markup such as
+// math mode. The size of the text is laid out using the
+// metrics of the vg fonts, so the typeset text may be wider
+// or narrower than the space left for it. To reduce the
+// effect of this, text is centered on the position where
+// the vg font would have drawn it.
+//
+// The output is a pgfpicture environment that can be
+// included in a document loading the pgf package, for
+// example using \input, or a complete standalone document.
+package vgtex
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/gonum/plot/vg"
+)
+
+type Canvas struct {
+	stk  []ctx
+	w, h vg.Length
+	buf  *bytes.Buffer
+
+	// standalone and escape are the
+	// options of the canvas.
+	standalone bool
+	escape     bool
+}
+
+type ctx struct {
+	color  color.Color
+	width  vg.Length
+	dashes []vg.Length
+	offs   vg.Length
+	cap    vg.LineCap
+	join   vg.LineJoin
+	miter  float64
+}
+
+// initialCtx is the context of the initial
+// PGF graphics state.
+var initialCtx = ctx{miter: 10}
+
+// pr is the amount of precision to use when outputting float64s.
+const pr = 5
+
+// New returns a new Canvas that writes
+// a pgfpicture environment.
+func New(w, h vg.Length) *Canvas {
+	return NewWith(w, h)
+}
+
+// NewWith returns a new Canvas created according to
+// the specified options. The currently accepted
+// options are Standalone and EscapeText.
+func NewWith(w, h vg.Length, o ...option) *Canvas {
+	c := &Canvas{
+		stk: []ctx{initialCtx},
+		w:   w,
+		h:   h,
+		buf: new(bytes.Buffer),
+	}
+	for _, opt := range o {
+		opt(c)
+	}
+	fmt.Fprintf(c.buf, "\\pgfpathrectangle{\\pgfpointorigin}{\\pgfpoint{%.*gpt}{%.*gpt}}\n",
+		pr, w.Points(), pr, h.Points())
+	c.buf.WriteString("\\pgfusepath{use as bounding box}\n")
+	vg.Initialize(c)
+	return c
+}
+
+type option func(*Canvas)
+
+// Standalone specifies that the canvas is written as a
+// complete LaTeX document using the standalone document
+// class, rather than as a picture to be included in
+// another document.
+func Standalone() option {
+	return func(c *Canvas) {
+		c.standalone = true
+	}
+}
+
+// EscapeText specifies that the characters with a special
+// meaning to LaTeX are escaped in text, so that the text
+// is typeset literally rather than as LaTeX markup.
+func EscapeText() option {
+	return func(c *Canvas) {
+		c.escape = true
+	}
+}
+
+func (c *Canvas) Size() (w, h vg.Length) {
+	return c.w, c.h
+}
+
+// cur returns the top context on the stack.
+func (c *Canvas) cur() *ctx {
+	return &c.stk[len(c.stk)-1]
+}
+
+func (c *Canvas) SetLineWidth(w vg.Length) {
+	if c.cur().width != w {
+		c.cur().width = w
+		fmt.Fprintf(c.buf, "\\pgfsetlinewidth{%.*gpt}\n", pr, w.Points())
+	}
+}
+
+func (c *Canvas) SetLineDash(dashes []vg.Length, o vg.Length) {
+	cur := c.cur().dashes
+	dashEq := len(dashes) == len(cur)
+	for i := 0; dashEq && i < len(dashes); i++ {
+		if dashes[i] != cur[i] {
+			dashEq = false
+		}
+	}
+	if !dashEq || c.cur().offs != o {
+		c.cur().dashes = dashes
+		c.cur().offs = o
+		c.buf.WriteString("\\pgfsetdash{")
+		for _, d := range dashes {
+			fmt.Fprintf(c.buf, "{%.*gpt}", pr, d.Points())
+		}
+		fmt.Fprintf(c.buf, "}{%.*gpt}\n", pr, o.Points())
+	}
+}
+
+func (c *Canvas) SetLineCap(cap vg.LineCap) {
+	if cap < vg.ButtCap || cap > vg.SquareCap {
+		cap = vg.ButtCap
+	}
+	if c.cur().cap != cap {
+		c.cur().cap = cap
+		fmt.Fprintf(c.buf, "\\pgfset%scap\n", lineCaps[cap])
+	}
+}
+
+func (c *Canvas) SetLineJoin(join vg.LineJoin) {
+	if join < vg.MiterJoin || join > vg.BevelJoin {
+		join = vg.MiterJoin
+	}
+	if c.cur().join != join {
+		c.cur().join = join
+		fmt.Fprintf(c.buf, "\\pgfset%sjoin\n", lineJoins[join])
+	}
+}
+
+func (c *Canvas) SetMiterLimit(limit float64) {
+	if c.cur().miter != limit {
+		c.cur().miter = limit
+		fmt.Fprintf(c.buf, "\\pgfsetmiterlimit{%.*g}\n", pr, limit)
+	}
+}
+
+// lineCaps and lineJoins are the names of
+// the PGF line caps and joins.
+var (
+	lineCaps  = [...]string{vg.ButtCap: "butt", vg.RoundCap: "round", vg.SquareCap: "rect"}
+	lineJoins = [...]string{vg.MiterJoin: "miter", vg.RoundJoin: "round", vg.BevelJoin: "bevel"}
+)
+
+func (c *Canvas) SetColor(clr color.Color) {
+	if clr == nil {
+		clr = color.Black
+	}
+	if !sameColor(c.cur().color, clr) {
+		c.cur().color = clr
+		c.writeColor(clr)
+	}
+}
+
+// sameColor returns whether a and b are the same color.
+// The colors are compared by their RGBA values, since
+// a color of a type that is not comparable would make
+// comparing a and b with == panic.
+func sameColor(a, b color.Color) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	return ar == br && ag == bg && ab == bb && aa == ba
+}
+
+// writeColor defines the vgcolor LaTeX color
+// and uses it for stroking and filling.
+func (c *Canvas) writeColor(clr color.Color) {
+	n := color.NRGBAModel.Convert(clr).(color.NRGBA)
+	fmt.Fprintf(c.buf, "\\definecolor{vgcolor}{rgb}{%.*g,%.*g,%.*g}\n",
+		pr, float64(n.R)/math.MaxUint8, pr, float64(n.G)/math.MaxUint8, pr, float64(n.B)/math.MaxUint8)
+	c.buf.WriteString("\\pgfsetstrokecolor{vgcolor}\n\\pgfsetfillcolor{vgcolor}\n")
+	a := float64(n.A) / math.MaxUint8
+	fmt.Fprintf(c.buf, "\\pgfsetstrokeopacity{%.*g}\n\\pgfsetfillopacity{%.*g}\n", pr, a, pr, a)
+}
+
+func (c *Canvas) Rotate(r float64) {
+	fmt.Fprintf(c.buf, "\\pgftransformrotate{%.*g}\n", pr, r*180/math.Pi)
+}
+
+func (c *Canvas) Translate(x, y vg.Length) {
+	fmt.Fprintf(c.buf, "\\pgftransformshift{\\pgfpoint{%.*gpt}{%.*gpt}}\n",
+		pr, x.Points(), pr, y.Points())
+}
+
+func (c *Canvas) Scale(x, y float64) {
+	fmt.Fprintf(c.buf, "\\pgftransformxscale{%.*g}\n\\pgftransformyscale{%.*g}\n", pr, x, pr, y)
+}
+
+func (c *Canvas) Push() {
+	c.stk = append(c.stk, *c.cur())
+	c.buf.WriteString("\\begin{pgfscope}\n")
+}
+
+func (c *Canvas) Pop() {
+	c.stk = c.stk[:len(c.stk)-1]
+	c.buf.WriteString("\\end{pgfscope}\n")
+}
+
+func (c *Canvas) Stroke(path vg.Path) {
+	if c.cur().width <= 0 {
+		return
+	}
+	c.trace(path)
+	c.buf.WriteString("\\pgfusepath{stroke}\n")
+}
+
+func (c *Canvas) Fill(path vg.Path) {
+	c.trace(path)
+	c.buf.WriteString("\\pgfusepath{fill}\n")
+}
+
+// trace writes the path construction
+// commands for path.
+func (c *Canvas) trace(path vg.Path) {
+	for i, comp := range path {
+		switch comp.Type {
+		case vg.MoveComp:
+			c.point("moveto", comp.X, comp.Y)
+		case vg.LineComp:
+			c.point("lineto", comp.X, comp.Y)
+		case vg.ArcComp:
+			x := comp.X + comp.Radius*vg.Length(math.Cos(comp.Start))
+			y := comp.Y + comp.Radius*vg.Length(math.Sin(comp.Start))
+			if i == 0 {
+				c.point("moveto", x, y)
+			} else {
+				c.point("lineto", x, y)
+			}
+			end := comp.Start + comp.Angle
+			fmt.Fprintf(c.buf, "\\pgfpatharc{%.*g}{%.*g}{%.*gpt}\n",
+				pr, comp.Start*180/math.Pi, pr, end*180/math.Pi, pr, comp.Radius.Points())
+		case vg.CloseComp:
+			c.buf.WriteString("\\pgfpathclose\n")
+		default:
+			panic(fmt.Sprintf("Unknown path component type: %d\n", comp.Type))
+		}
+	}
+}
+
+// point writes a path command taking a single point.
+func (c *Canvas) point(cmd string, x, y vg.Length) {
+	fmt.Fprintf(c.buf, "\\pgfpath%s{\\pgfpoint{%.*gpt}{%.*gpt}}\n", cmd, pr, x.Points(), pr, y.Points())
+}
+
+// FillString implements the vg.Canvas.FillString method.
+// The text is centered on the position where the font
+// would draw it, and is typeset in the current color with
+// the size of the font by LaTeX using the document fonts.
+func (c *Canvas) FillString(fnt vg.Font, x, y vg.Length, str string) {
+	x += fnt.Width(str) / 2
+	if c.escape {
+		str = Escape(str)
+	}
+	c.buf.WriteString("\\begin{pgfscope}\n\\pgflowlevelsynccm\n")
+	fmt.Fprintf(c.buf, "\\pgftext[base,at={\\pgfpoint{%.*gpt}{%.*gpt}}]{\\fontsize{%.*gpt}{%.*gpt}\\selectfont\\color{vgcolor}%s}\n",
+		pr, x.Points(), pr, y.Points(), pr, fnt.Size.Points(), pr, 1.2*fnt.Size.Points(), str)
+	c.buf.WriteString("\\end{pgfscope}\n")
+}
+
+// RawText reports whether text is passed to FillString
+// without markup parsing, so that it can be typeset by
+// LaTeX.  This is the case unless the EscapeText option
+// was given.
+func (c *Canvas) RawText() bool {
+	return !c.escape
+}
+
+// DrawImage implements the vg.Canvas.DrawImage method.
+// PGF can only include images from files, so each run
+// of pixels of the same color in a row of the image is
+// drawn as a filled rectangle and the interpolation is
+// ignored.
+func (c *Canvas) DrawImage(x, y, w, h vg.Length, img image.Image, _ vg.Interpolation) {
+	b := img.Bounds()
+	if b.Empty() {
+		return
+	}
+	c.Push()
+	defer c.Pop()
+	pw, ph := w/vg.Length(b.Dx()), h/vg.Length(b.Dy())
+	for j := b.Min.Y; j < b.Max.Y; j++ {
+		py := y + h - vg.Length(j-b.Min.Y+1)*ph
+		for i := b.Min.X; i < b.Max.X; {
+			clr := color.NRGBAModel.Convert(img.At(i, j)).(color.NRGBA)
+			n := 1
+			for i+n < b.Max.X && color.NRGBAModel.Convert(img.At(i+n, j)) == clr {
+				n++
+			}
+			if clr.A != 0 {
+				c.SetColor(clr)
+				fmt.Fprintf(c.buf, "\\pgfpathrectangle{\\pgfpoint{%.*gpt}{%.*gpt}}{\\pgfpoint{%.*gpt}{%.*gpt}}\n\\pgfusepath{fill}\n",
+					pr, (x + vg.Length(i-b.Min.X)*pw).Points(), pr, py.Points(),
+					pr, (vg.Length(n) * pw).Points(), pr, ph.Points())
+			}
+			i += n
+		}
+	}
+}
+
+func (c *Canvas) DPI() float64 {
+	return 72
+}
+
+// WriteTo writes the canvas to an io.Writer.
+func (c *Canvas) WriteTo(w io.Writer) (int64, error) {
+	b := bufio.NewWriter(w)
+	var head, tail string
+	if c.standalone {
+		head = "\\documentclass{standalone}\n\\usepackage{pgf}\n\\begin{document}\n"
+		tail = "\\end{document}\n"
+	} else {
+		head = "% Created by github.com/gonum/plot/vg/vgtex.\n% Include in a document using \\usepackage{pgf}.\n"
+	}
+	var n int64
+	for _, p := range [][]byte{
+		[]byte(head + "\\begin{pgfpicture}\n"),
+		c.buf.Bytes(),
+		[]byte("\\end{pgfpicture}\n" + tail),
+	} {
+		m, err := b.Write(p)
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, b.Flush()
+}
+
+// latexEscaper replaces the characters with a
+// special meaning to LaTeX.
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`#`, `\#`,
+	`%`, `\%`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// Escape returns str with the characters that have a
+// special meaning to LaTeX escaped.
+func Escape(str string) string {
+	return latexEscaper.Replace(str)
+}
@@ -0,0 +1,182 @@
+// Copyright ©2015 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgtex
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/gonum/plot/vg"
+)
+
+func write(t *testing.T, c *Canvas) string {
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestCanvas(t *testing.T) {
+	c := New(2*vg.Inch, vg.Inch)
+	c.SetColor(color.NRGBA{R: 0xff, A: 0x80})
+	c.SetLineWidth(2)
+	c.SetLineDash([]vg.Length{3, 1}, 1)
+	c.Push()
+	c.Translate(10, 20)
+	c.Rotate(math.Pi / 2)
+	var p vg.Path
+	p.Move(0, 0)
+	p.Line(10, 0)
+	p.Arc(0, 0, 10, 0, math.Pi/2)
+	p.Close()
+	c.Stroke(p)
+	c.Pop()
+	c.Fill(p[:2])
+	got := write(t, c)
+
+	want := `% Created by github.com/gonum/plot/vg/vgtex.
+% Include in a document using \usepackage{pgf}.
+\begin{pgfpicture}
+\pgfpathrectangle{\pgfpointorigin}{\pgfpoint{144pt}{72pt}}
+\pgfusepath{use as bounding box}
+\pgfsetlinewidth{1pt}
+\definecolor{vgcolor}{rgb}{0,0,0}
+\pgfsetstrokecolor{vgcolor}
+\pgfsetfillcolor{vgcolor}
+\pgfsetstrokeopacity{1}
+\pgfsetfillopacity{1}
+\definecolor{vgcolor}{rgb}{1,0,0}
+\pgfsetstrokecolor{vgcolor}
+\pgfsetfillcolor{vgcolor}
+\pgfsetstrokeopacity{0.50196}
+\pgfsetfillopacity{0.50196}
+\pgfsetlinewidth{2pt}
+\pgfsetdash{{3pt}{1pt}}{1pt}
+\begin{pgfscope}
+\pgftransformshift{\pgfpoint{10pt}{20pt}}
+\pgftransformrotate{90}
+\pgfpathmoveto{\pgfpoint{0pt}{0pt}}
+\pgfpathlineto{\pgfpoint{10pt}{0pt}}
+\pgfpathlineto{\pgfpoint{10pt}{0pt}}
+\pgfpatharc{0}{90}{10pt}
+\pgfpathclose
+\pgfusepath{stroke}
+\end{pgfscope}
+\pgfpathmoveto{\pgfpoint{0pt}{0pt}}
+\pgfpathlineto{\pgfpoint{10pt}{0pt}}
+\pgfusepath{fill}
+\end{pgfpicture}
+`
+	if got != want {
+		t.Errorf("unexpected output:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFillString(t *testing.T) {
+	fnt, err := vg.MakeFont("Helvetica", 10)
+	if err != nil {
+		t.Fatalf("failed to make font: %v", err)
+	}
+	for _, test := range []struct {
+		opts []option
+		text string
+		want string
+	}{
+		{
+			text: `$\alpha_1$`,
+			want: `{\fontsize{10pt}{12pt}\selectfont\color{vgcolor}$\alpha_1$}`,
+		},
+		{
+			opts: []option{EscapeText()},
+			text: `50% of $x_1 & {y}`,
+			want: `{\fontsize{10pt}{12pt}\selectfont\color{vgcolor}50\% of \$x\_1 \& \{y\}}`,
+		},
+	} {
+		c := NewWith(vg.Inch, vg.Inch, test.opts...)
+		c.FillString(fnt, 10, 20, test.text)
+		got := write(t, c)
+		if !strings.Contains(got, "\\pgflowlevelsynccm\n") {
+			t.Errorf("missing transform synchronization for %q", test.text)
+		}
+		if !strings.Contains(got, test.want) {
+			t.Errorf("unexpected text for %q:\n%s", test.text, got)
+		}
+		if !strings.Contains(got, "\\pgftext[base,at={\\pgfpoint{") {
+			t.Errorf("missing text position for %q", test.text)
+		}
+	}
+}
+
+func TestStandalone(t *testing.T) {
+	got := write(t, NewWith(vg.Inch, vg.Inch, Standalone()))
+	if !strings.HasPrefix(got, "\\documentclass{standalone}\n\\usepackage{pgf}\n\\begin{document}\n\\begin{pgfpicture}\n") {
+		t.Errorf("unexpected start of document:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "\\end{pgfpicture}\n\\end{document}\n") {
+		t.Errorf("unexpected end of document:\n%s", got)
+	}
+}
+
+func TestDrawImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	for x := 0; x < 3; x++ {
+		img.Set(x, 0, color.Black)
+	}
+	img.Set(0, 1, color.NRGBA{B: 0xff, A: 0xff})
+	c := New(vg.Inch, vg.Inch)
+	c.DrawImage(0, 0, 30, 20, img, vg.Bilinear)
+	got := write(t, c)
+
+	for _, want := range []string{
+		"\\pgfpathrectangle{\\pgfpoint{0pt}{10pt}}{\\pgfpoint{30pt}{10pt}}\n\\pgfusepath{fill}\n",
+		"\\definecolor{vgcolor}{rgb}{0,0,1}\n",
+		"\\pgfpathrectangle{\\pgfpoint{0pt}{0pt}}{\\pgfpoint{10pt}{10pt}}\n\\pgfusepath{fill}\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in output:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "\\pgfusepath{fill}"); n != 2 {
+		t.Errorf("unexpected number of filled rectangles: got:%d want:2", n)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+	}{
+		{in: "plain text", want: "plain text"},
+		{in: `a\b`, want: `a\textbackslash{}b`},
+		{in: "#1 ~ x^2", want: `\#1 \textasciitilde{} x\textasciicircum{}2`},
+	} {
+		if got := Escape(test.in); got != test.want {
+			t.Errorf("unexpected escape of %q: got:%q want:%q", test.in, got, test.want)
+		}
+	}
+}
+
+// sliceColor is a color of a type that is not comparable.
+type sliceColor []uint32
+
+func (c sliceColor) RGBA() (r, g, b, a uint32) {
+	return c[0], c[1], c[2], c[3]
+}
+
+func TestSetColorUncomparable(t *testing.T) {
+	const def = "\\definecolor{vgcolor}"
+	want := strings.Count(write(t, New(vg.Inch, vg.Inch)), def) + 1
+	c := New(vg.Inch, vg.Inch)
+	c.SetColor(sliceColor{0, 0, 0xffff, 0xffff})
+	c.SetColor(sliceColor{0, 0, 0xffff, 0xffff})
+	c.SetColor(color.RGBA{B: 0xff, A: 0xff})
+	if got := strings.Count(write(t, c), def); got != want {
+		t.Errorf("unexpected number of color definitions: got:%d want:%d", got, want)
+	}
+}